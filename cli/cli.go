@@ -0,0 +1,758 @@
+// Package cli implements non-interactive dpcmder commands (ls, get, put, rm,
+// mkdir, export-domain, save-config, exec, diff-objects & sync) which can be
+// used from scripts without terminal user interface.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/clbanning/mxj/v2"
+	"github.com/croz-ltd/dpcmder/config"
+	"github.com/croz-ltd/dpcmder/model"
+	"github.com/croz-ltd/dpcmder/repo/dp"
+	"github.com/croz-ltd/dpcmder/ui"
+	"github.com/croz-ltd/dpcmder/utils/errs"
+	"github.com/croz-ltd/dpcmder/utils/logging"
+)
+
+// Exit statuses returned by non-interactive commands.
+const (
+	ExitOk       = 0
+	ExitError    = 1
+	ExitUsage    = 2
+	ExitNotFound = 3
+)
+
+// cliError is error which contains exit status dpcmder should return.
+type cliError struct {
+	status int
+	msg    string
+}
+
+func (e cliError) Error() string {
+	return e.msg
+}
+
+// usageErrorf creates error for wrong usage of non-interactive command.
+func usageErrorf(format string, v ...interface{}) error {
+	return cliError{status: ExitUsage, msg: fmt.Sprintf(format, v...)}
+}
+
+// notFoundErrorf creates error for non-existing item used in non-interactive command.
+func notFoundErrorf(format string, v ...interface{}) error {
+	return cliError{status: ExitNotFound, msg: fmt.Sprintf(format, v...)}
+}
+
+// dpTarget contains DataPower location given as command argument in form of
+// <appliance>:<domain>:<path> (for example "MyDp:default:local:///dir").
+type dpTarget struct {
+	appliance string
+	domain    string
+	path      string
+}
+
+// Paths used in DataPower target (instead of filestore path) to access
+// DataPower objects ("objects/<class>/<name>") or statuses ("status/<class>").
+const (
+	objectsPath = "objects"
+	statusPath  = "status"
+)
+
+func (t dpTarget) String() string {
+	return fmt.Sprintf("%s:%s:%s", t.appliance, t.domain, t.path)
+}
+
+// Run runs non-interactive command given with its arguments and returns exit
+// status which should be returned by dpcmder.
+func Run(args []string) int {
+	logging.LogDebugf("cli/Run(%v)", args)
+	if len(args) == 0 {
+		return usage()
+	}
+
+	var err error
+	command, commandArgs := args[0], args[1:]
+	switch command {
+	case "ls":
+		err = ls(commandArgs)
+	case "get":
+		err = get(commandArgs)
+	case "put":
+		err = put(commandArgs)
+	case "rm":
+		err = rm(commandArgs)
+	case "mkdir":
+		err = mkdir(commandArgs)
+	case "export-domain":
+		err = exportDomain(commandArgs)
+	case "save-config":
+		err = saveConfig(commandArgs)
+	case "exec":
+		err = execConfig(commandArgs)
+	case "diff-objects":
+		err = diffObjects(commandArgs)
+	case "sync":
+		err = syncDirs(commandArgs)
+	default:
+		err = usageErrorf("Unknown command '%s'.", command)
+	}
+
+	if err != nil {
+		logging.LogDebugf("cli/Run() - command '%s' failed: %v", command, err)
+		status := ExitError
+		if cliErr, ok := err.(cliError); ok {
+			status = cliErr.status
+		}
+		if jsonOutput() {
+			errorJSON, _ := json.Marshal(struct {
+				Command string `json:"command"`
+				Error   string `json:"error"`
+				Status  int    `json:"status"`
+			}{command, err.Error(), status})
+			fmt.Fprintln(os.Stderr, string(errorJSON))
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", command, err)
+			if status == ExitUsage {
+				usage()
+			}
+		}
+		return status
+	}
+
+	return ExitOk
+}
+
+// usage prints usage of non-interactive commands to stderr.
+func usage() int {
+	fmt.Fprintln(os.Stderr, "Non-interactive commands:")
+	fmt.Fprintf(os.Stderr, " %s ls [APPLIANCE[:DOMAIN[:PATH]]]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, " %s get APPLIANCE:DOMAIN:PATH [LOCAL_FILE]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, " %s put LOCAL_FILE APPLIANCE:DOMAIN:PATH\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, " %s rm APPLIANCE:DOMAIN:PATH\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, " %s mkdir APPLIANCE:DOMAIN:PATH\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, " %s export-domain APPLIANCE:DOMAIN [LOCAL_FILE]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, " %s save-config APPLIANCE:DOMAIN\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, " %s exec APPLIANCE:DOMAIN:PATH\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, " %s diff-objects OBJECT OBJECT\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, " %s sync APPLIANCE:DOMAIN:PATH LOCAL_DIR | SYNC_SESSION\n", os.Args[0])
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, " APPLIANCE - name of DataPower configuration saved in ~/.dpcmder/config.json")
+	fmt.Fprintln(os.Stderr, " PATH - DataPower path, for example local:///dir/file.xsl, objects/CLASS/NAME or status/CLASS")
+	fmt.Fprintln(os.Stderr, " LOCAL_FILE - local file path, '-' for stdin/stdout")
+	fmt.Fprintln(os.Stderr, " OBJECT - APPLIANCE:DOMAIN:objects/CLASS/NAME or LOCAL_FILE with object configuration")
+	fmt.Fprintln(os.Stderr, " SYNC_SESSION - name of sync session saved in ~/.dpcmder/config.json")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Sync command syncs LOCAL_DIR to DataPower directory until interrupted (SIGINT/SIGTERM).")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "With -json flag listings, objects, statuses and errors are written as JSON.")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintf(os.Stderr, "Exit status: %d - success, %d - error, %d - wrong usage, %d - not found.\n",
+		ExitOk, ExitError, ExitUsage, ExitNotFound)
+	return ExitUsage
+}
+
+// parseDpTarget parses DataPower location given in form of
+// <appliance>:<domain>:<path>, domain and path are optional.
+func parseDpTarget(target string) (dpTarget, error) {
+	result := dpTarget{}
+	parts := strings.SplitN(target, ":", 3)
+	result.appliance = parts[0]
+	if result.appliance == "" {
+		return result, usageErrorf("Missing DataPower appliance in '%s'.", target)
+	}
+	if len(parts) > 1 {
+		result.domain = parts[1]
+	}
+	if len(parts) > 2 {
+		result.path = normalizeDpPath(parts[2])
+		if result.domain == "" && result.path != "" {
+			return result, usageErrorf("Missing DataPower domain in '%s'.", target)
+		}
+	}
+
+	return result, nil
+}
+
+// dpPathRegexp matches DataPower path with filestore and rest of the path.
+var dpPathRegexp = regexp.MustCompile(`^([^:/]+):/*(.*)$`)
+
+// normalizeDpPath converts DataPower path given as URL (local:///dir/file) to
+// path used in dpcmder (local:/dir/file).
+func normalizeDpPath(dpPath string) string {
+	matches := dpPathRegexp.FindStringSubmatch(dpPath)
+	if matches == nil {
+		return strings.TrimRight(dpPath, "/")
+	}
+	filestore, restPath := matches[1], strings.TrimRight(matches[2], "/")
+	if restPath == "" {
+		return filestore + ":"
+	}
+	return filestore + ":/" + restPath
+}
+
+// splitDpPath splits DataPower path to parent path and file name.
+func splitDpPath(dpPath string) (parentPath, fileName string) {
+	lastSeparatorIdx := strings.LastIndex(dpPath, "/")
+	if lastSeparatorIdx == -1 {
+		return "", dpPath
+	}
+	return dpPath[:lastSeparatorIdx], dpPath[lastSeparatorIdx+1:]
+}
+
+// initAppliance prepares DataPower repo used for the given side to access
+// appliance from the target.
+func initAppliance(target dpTarget, side model.Side) error {
+	dpa, err := applianceConfig(target.appliance)
+	if err != nil {
+		return err
+	}
+	return dp.Repos[side].InitNetworkSettings(target.appliance, dpa)
+}
+
+// applianceConfig returns saved DataPower appliance configuration with
+// password set.
+func applianceConfig(appliance string) (config.DataPowerAppliance, error) {
+	dpa, ok := config.Conf.DataPowerAppliances[appliance]
+	if !ok {
+		return dpa, notFoundErrorf("DataPower appliance configuration '%s' not found.", appliance)
+	}
+	if dpa.Password == "" {
+		dpa.SetDpPlaintextPassword(config.DpTransientPassword(appliance))
+	}
+	if dpa.Password == "" {
+		return dpa, usageErrorf("Password for DataPower appliance configuration '%s' is not saved.", appliance)
+	}
+	return dpa, nil
+}
+
+// viewConfig creates DataPower view config for the target domain and path.
+func viewConfig(target dpTarget) (*model.ItemConfig, error) {
+	applianceView := &model.ItemConfig{Type: model.ItemDpConfiguration,
+		Name: target.appliance, DpAppliance: target.appliance,
+		Parent: &model.ItemConfig{Type: model.ItemNone}}
+	if target.domain == "" {
+		return applianceView, nil
+	}
+	domainView := &model.ItemConfig{Type: model.ItemDpDomain,
+		Name: target.domain, DpAppliance: target.appliance,
+		DpDomain: target.domain, Parent: applianceView}
+	if target.path == "" {
+		return domainView, nil
+	}
+
+	pathElements := strings.SplitN(target.path, "/", 2)
+	switch pathElements[0] {
+	case objectsPath:
+		dp.Repo.DpViewMode = model.DpObjectMode
+		classListView := &model.ItemConfig{Type: model.ItemDpObjectClassList,
+			Name: "Object classes", Path: "Object classes",
+			DpAppliance: target.appliance, DpDomain: target.domain, Parent: domainView}
+		if len(pathElements) == 1 {
+			return classListView, nil
+		}
+		return &model.ItemConfig{Type: model.ItemDpObjectClass,
+			Name: pathElements[1], Path: pathElements[1],
+			DpAppliance: target.appliance, DpDomain: target.domain, Parent: classListView}, nil
+	case statusPath:
+		dp.Repo.DpViewMode = model.DpStatusMode
+		classListView := &model.ItemConfig{Type: model.ItemDpStatusClassList,
+			Name: "Status classes", Path: "Status classes",
+			DpAppliance: target.appliance, DpDomain: target.domain, Parent: domainView}
+		if len(pathElements) == 1 {
+			return classListView, nil
+		}
+		return &model.ItemConfig{Type: model.ItemDpStatusClass,
+			Name: pathElements[1], Path: pathElements[1],
+			DpAppliance: target.appliance, DpDomain: target.domain, Parent: classListView}, nil
+	default:
+		dp.Repo.DpViewMode = model.DpFilestoreMode
+		return dp.Repo.GetViewConfigByPath(domainView, target.path)
+	}
+}
+
+// findItem finds item with given name in the parent view of the target path
+// and returns parent view and item found (nil if item is not found).
+func findItem(target dpTarget) (*model.ItemConfig, *model.Item, error) {
+	if target.path == "" {
+		return nil, nil, usageErrorf("Missing DataPower path in '%s'.", target)
+	}
+	parentPath, fileName := splitDpPath(target.path)
+	if parentPath == "" || fileName == "" {
+		return nil, nil, usageErrorf("Wrong DataPower file path in '%s'.", target)
+	}
+	parentTarget := target
+	parentTarget.path = parentPath
+	parentView, err := viewConfig(parentTarget)
+	if err != nil {
+		return nil, nil, err
+	}
+	items, err := dp.Repo.GetList(context.Background(), parentView)
+	if err != nil {
+		return nil, nil, err
+	}
+	for idx := range items {
+		if items[idx].Name == fileName {
+			return parentView, &items[idx], nil
+		}
+	}
+
+	return parentView, nil, nil
+}
+
+// targetArg parses command argument with DataPower location and prepares
+// DataPower repo to access appliance from the target.
+func targetArg(arg string) (dpTarget, error) {
+	target, err := parseDpTarget(arg)
+	if err != nil {
+		return target, err
+	}
+	return target, initAppliance(target, model.Left)
+}
+
+func ls(args []string) error {
+	logging.LogDebugf("cli/ls(%v)", args)
+	var view *model.ItemConfig
+	switch len(args) {
+	case 0:
+		view = &model.ItemConfig{Type: model.ItemNone}
+	case 1:
+		target, err := targetArg(args[0])
+		if err != nil {
+			return err
+		}
+		view, err = viewConfig(target)
+		if err != nil {
+			return err
+		}
+	default:
+		return usageErrorf("Wrong number of arguments.")
+	}
+
+	items, err := dp.Repo.GetList(context.Background(), view)
+	if err != nil {
+		return err
+	}
+	listedItems := make(model.ItemList, 0, len(items))
+	for _, item := range items {
+		if item.Name != ".." {
+			listedItems = append(listedItems, item)
+		}
+	}
+
+	if jsonOutput() {
+		itemsJSON, err := json.MarshalIndent(listedItems, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(itemsJSON))
+		return nil
+	}
+	for _, item := range listedItems {
+		fmt.Println(item.DisplayString())
+	}
+
+	return nil
+}
+
+func get(args []string) error {
+	logging.LogDebugf("cli/get(%v)", args)
+	if len(args) < 1 || len(args) > 2 {
+		return usageErrorf("Wrong number of arguments.")
+	}
+	target, err := targetArg(args[0])
+	if err != nil {
+		return err
+	}
+	parentView, item, err := findItem(target)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return notFoundErrorf("Item '%s' not found.", target)
+	}
+
+	// Objects and statuses are written to stdout by default, files to the
+	// local file with the same name.
+	localPath := "-"
+	var fileContent []byte
+	switch item.Config.Type {
+	case model.ItemFile:
+		localPath = item.Name
+		fileContent, err = dp.Repo.GetFile(context.Background(), parentView, item.Name)
+	case model.ItemDpObject:
+		fileContent, err = dp.Repo.GetObject(context.Background(), target.domain, parentView.Path, item.Name, false)
+	case model.ItemDpStatusClass:
+		fileContent, err = dp.Repo.GetStatuses(context.Background(), target.domain, item.Name)
+	default:
+		return errs.Errorf("Can't get '%s' (%s).", target, item.Config.Type.UserFriendlyString())
+	}
+	if err != nil {
+		return err
+	}
+	if item.Config.Type != model.ItemFile && jsonOutput() {
+		fileContent, err = jsonContent(fileContent)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(args) == 2 {
+		localPath = args[1]
+	}
+	if localPath == "-" {
+		_, err = os.Stdout.Write(fileContent)
+		return err
+	}
+	return ioutil.WriteFile(localPath, fileContent, os.ModePerm)
+}
+
+func put(args []string) error {
+	logging.LogDebugf("cli/put(%v)", args)
+	if len(args) != 2 {
+		return usageErrorf("Wrong number of arguments.")
+	}
+	var fileContent []byte
+	var err error
+	if args[0] == "-" {
+		fileContent, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		fileContent, err = ioutil.ReadFile(args[0])
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			return notFoundErrorf("Local file '%s' not found.", args[0])
+		}
+		return err
+	}
+	target, err := targetArg(args[1])
+	if err != nil {
+		return err
+	}
+	parentView, item, err := findItem(target)
+	if err != nil {
+		return err
+	}
+
+	_, fileName := splitDpPath(target.path)
+	if item != nil && item.Config.Type == model.ItemDirectory {
+		if args[0] == "-" {
+			return usageErrorf("Can't put stdin to directory '%s'.", target)
+		}
+		parentView, err = viewConfig(target)
+		if err != nil {
+			return err
+		}
+		fileName = localFileName(args[0])
+	}
+
+	_, err = dp.Repo.UpdateFile(context.Background(), parentView, fileName, fileContent)
+	return err
+}
+
+func rm(args []string) error {
+	logging.LogDebugf("cli/rm(%v)", args)
+	if len(args) != 1 {
+		return usageErrorf("Wrong number of arguments.")
+	}
+	target, err := targetArg(args[0])
+	if err != nil {
+		return err
+	}
+	parentView, item, err := findItem(target)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return notFoundErrorf("File or directory '%s' not found.", target)
+	}
+	deleted, err := dp.Repo.Delete(context.Background(), parentView, item.Config.Type, parentView.Path, item.Name)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errs.Errorf("Couldn't delete '%s'.", target)
+	}
+
+	return nil
+}
+
+func mkdir(args []string) error {
+	logging.LogDebugf("cli/mkdir(%v)", args)
+	if len(args) != 1 {
+		return usageErrorf("Wrong number of arguments.")
+	}
+	target, err := targetArg(args[0])
+	if err != nil {
+		return err
+	}
+	parentView, item, err := findItem(target)
+	if err != nil {
+		return err
+	}
+	_, dirName := splitDpPath(target.path)
+	if item != nil {
+		return errs.Errorf("Can't create directory '%s', %s with same name exists.",
+			target, item.Config.Type.UserFriendlyString())
+	}
+	created, err := dp.Repo.CreateDir(context.Background(), parentView, parentView.Path, dirName)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return errs.Errorf("Couldn't create directory '%s'.", target)
+	}
+
+	return nil
+}
+
+func exportDomain(args []string) error {
+	logging.LogDebugf("cli/exportDomain(%v)", args)
+	if len(args) < 1 || len(args) > 2 {
+		return usageErrorf("Wrong number of arguments.")
+	}
+	target, err := targetArg(args[0])
+	if err != nil {
+		return err
+	}
+	if target.domain == "" || target.path != "" {
+		return usageErrorf("Wrong DataPower domain '%s'.", args[0])
+	}
+
+	exportFileName := target.appliance + "_" + target.domain + "_" + time.Now().Format("20060102150405") + ".zip"
+	if len(args) == 2 {
+		exportFileName = args[1]
+	}
+	exportFileBytes, err := dp.Repo.ExportDomain(context.Background(), target.domain, localFileName(exportFileName))
+	if err != nil {
+		return err
+	}
+	if exportFileName == "-" {
+		_, err = os.Stdout.Write(exportFileBytes)
+		return err
+	}
+	return ioutil.WriteFile(exportFileName, exportFileBytes, os.ModePerm)
+}
+
+func saveConfig(args []string) error {
+	logging.LogDebugf("cli/saveConfig(%v)", args)
+	if len(args) != 1 {
+		return usageErrorf("Wrong number of arguments.")
+	}
+	target, err := targetArg(args[0])
+	if err != nil {
+		return err
+	}
+	if target.domain == "" || target.path != "" {
+		return usageErrorf("Wrong DataPower domain '%s'.", args[0])
+	}
+	view, err := viewConfig(target)
+	if err != nil {
+		return err
+	}
+
+	return dp.Repo.SaveConfiguration(context.Background(), view)
+}
+
+func execConfig(args []string) error {
+	logging.LogDebugf("cli/execConfig(%v)", args)
+	if len(args) != 1 {
+		return usageErrorf("Wrong number of arguments.")
+	}
+	target, err := targetArg(args[0])
+	if err != nil {
+		return err
+	}
+	_, item, err := findItem(target)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return notFoundErrorf("File '%s' not found.", target)
+	}
+	if item.Config.Type != model.ItemFile {
+		return errs.Errorf("Can't exec '%s' (%s).", target, item.Config.Type.UserFriendlyString())
+	}
+
+	return dp.Repo.ExecConfig(context.Background(), item.Config)
+}
+
+func diffObjects(args []string) error {
+	logging.LogDebugf("cli/diffObjects(%v)", args)
+	if len(args) != 2 {
+		return usageErrorf("Wrong number of arguments.")
+	}
+	objectContents := make([][]byte, 2)
+	for idx, arg := range args {
+		objectContent, err := objectArg(arg, model.Side(idx))
+		if err != nil {
+			return err
+		}
+		objectContents[idx] = objectContent
+	}
+
+	propertyDiffs, err := dp.CompareObjects(objectContents[0], objectContents[1])
+	if err != nil {
+		return err
+	}
+	if jsonOutput() {
+		diffsJSON, err := json.MarshalIndent(propertyDiffs, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(diffsJSON))
+		return nil
+	}
+	for _, propertyDiff := range propertyDiffs {
+		fmt.Println(propertyDiff.String())
+	}
+
+	return nil
+}
+
+// objectArg returns DataPower object configuration given as command argument
+// in form of <appliance>:<domain>:objects/<class>/<name> or local file path
+// (DataPower repo used for the given side fetches object from appliance).
+func objectArg(arg string, side model.Side) ([]byte, error) {
+	argParts := strings.SplitN(arg, ":", 3)
+	if _, ok := config.Conf.DataPowerAppliances[argParts[0]]; !ok || len(argParts) < 3 {
+		var objectContent []byte
+		var err error
+		if arg == "-" {
+			objectContent, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			objectContent, err = ioutil.ReadFile(arg)
+		}
+		if os.IsNotExist(err) {
+			return nil, notFoundErrorf("Local file '%s' not found.", arg)
+		}
+		return objectContent, err
+	}
+
+	target, err := parseDpTarget(arg)
+	if err != nil {
+		return nil, err
+	}
+	pathElements := strings.Split(target.path, "/")
+	if len(pathElements) != 3 || pathElements[0] != objectsPath {
+		return nil, usageErrorf("Wrong DataPower object path in '%s'.", target)
+	}
+	err = initAppliance(target, side)
+	if err != nil {
+		return nil, err
+	}
+	objectContent, err := dp.Repos[side].GetObject(context.Background(), target.domain, pathElements[1], pathElements[2], false)
+	if err != nil {
+		return nil, err
+	}
+	if objectContent == nil {
+		return nil, notFoundErrorf("Object '%s' not found.", target)
+	}
+	return objectContent, nil
+}
+
+func syncDirs(args []string) error {
+	logging.LogDebugf("cli/syncDirs(%v)", args)
+	session, err := syncSessionArgs(args)
+	if err != nil {
+		return err
+	}
+	dpa, err := applianceConfig(session.DpAppliance)
+	if err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	stop := make(chan struct{})
+	go func() {
+		sig := <-signals
+		logging.LogDebugf("cli/syncDirs() - signal received: %v", sig)
+		close(stop)
+	}()
+
+	return ui.RunSync(session, dpa, os.Stdout, stop)
+}
+
+// syncSessionArgs creates sync session from the sync command arguments - name
+// of the saved sync session or DataPower target and local directory.
+func syncSessionArgs(args []string) (*model.SyncSession, error) {
+	switch len(args) {
+	case 1:
+		sessionConfig, ok := config.Conf.SyncSessions[args[0]]
+		if !ok {
+			return nil, notFoundErrorf("Sync session '%s' not found.", args[0])
+		}
+		return &model.SyncSession{Name: args[0],
+			DpAppliance: sessionConfig.DpAppliance, DpDomain: sessionConfig.DpDomain,
+			DirDp: sessionConfig.DirDp, DirLocal: sessionConfig.DirLocal,
+			PostSyncActions: sessionConfig.PostSyncActions}, nil
+	case 2:
+		target, err := parseDpTarget(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if target.path == "" {
+			return nil, usageErrorf("Missing DataPower path in '%s'.", target)
+		}
+		dirLocal, err := filepath.Abs(args[1])
+		if err != nil {
+			return nil, err
+		}
+		fileInfo, err := os.Stat(dirLocal)
+		if err != nil || !fileInfo.IsDir() {
+			return nil, notFoundErrorf("Local directory '%s' not found.", args[1])
+		}
+		return &model.SyncSession{Name: adHocSyncSessionName(dirLocal),
+			DpAppliance: target.appliance, DpDomain: target.domain,
+			DirDp: target.path, DirLocal: dirLocal}, nil
+	default:
+		return nil, usageErrorf("Wrong number of arguments.")
+	}
+}
+
+// adHocSyncSessionName returns name of the sync session given by the DataPower
+// target and local directory which doesn't collide with saved sync sessions.
+func adHocSyncSessionName(dirLocal string) string {
+	name := filepath.Base(dirLocal)
+	for idx := 2; ; idx++ {
+		if _, saved := config.Conf.SyncSessions[name]; !saved {
+			return name
+		}
+		name = fmt.Sprintf("%s-%d", filepath.Base(dirLocal), idx)
+	}
+}
+
+// jsonOutput returns true if results should be written as JSON.
+func jsonOutput() bool {
+	return config.JSONOutput != nil && *config.JSONOutput
+}
+
+// jsonContent returns DataPower object or status content as JSON - REST
+// responses are already JSON, SOMA XML responses are converted to JSON.
+func jsonContent(content []byte) ([]byte, error) {
+	if json.Valid(content) {
+		return content, nil
+	}
+	mv, err := mxj.NewMapXml(content)
+	if err != nil {
+		return nil, err
+	}
+	return mv.JsonIndent("", "  ")
+}
+
+// localFileName returns file name part of local file path.
+func localFileName(filePath string) string {
+	lastSeparatorIdx := strings.LastIndexAny(filePath, string(os.PathSeparator)+"/")
+	return filePath[lastSeparatorIdx+1:]
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/croz-ltd/dpcmder/config"
+	"github.com/croz-ltd/dpcmder/model"
+	"github.com/croz-ltd/dpcmder/repo/dp"
+	"github.com/croz-ltd/dpcmder/utils/assert"
+)
+
+func TestParseDpTarget(t *testing.T) {
+	testDataMatrix := []struct {
+		target string
+		want   dpTarget
+		err    bool
+	}{
+		{"MyDp", dpTarget{appliance: "MyDp"}, false},
+		{"MyDp:default", dpTarget{appliance: "MyDp", domain: "default"}, false},
+		{"MyDp:default:local:", dpTarget{appliance: "MyDp", domain: "default", path: "local:"}, false},
+		{"MyDp:default:local:///", dpTarget{appliance: "MyDp", domain: "default", path: "local:"}, false},
+		{"MyDp:default:local:///dir/file.xsl", dpTarget{appliance: "MyDp", domain: "default", path: "local:/dir/file.xsl"}, false},
+		{"MyDp:default:local:/dir/", dpTarget{appliance: "MyDp", domain: "default", path: "local:/dir"}, false},
+		{"MyDp::local:///dir", dpTarget{}, true},
+		{":default", dpTarget{}, true},
+	}
+	for _, testCase := range testDataMatrix {
+		t.Run(testCase.target, func(t *testing.T) {
+			got, err := parseDpTarget(testCase.target)
+			if testCase.err {
+				assert.NotNil(t, "parseDpTarget", err)
+				cliErr, ok := err.(cliError)
+				assert.True(t, "parseDpTarget cliError", ok)
+				assert.Equals(t, "parseDpTarget status", cliErr.status, ExitUsage)
+			} else {
+				assert.Nil(t, "parseDpTarget", err)
+				assert.Equals(t, "parseDpTarget", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestSplitDpPath(t *testing.T) {
+	testDataMatrix := []struct {
+		dpPath, parentPath, fileName string
+	}{
+		{"local:/file.xsl", "local:", "file.xsl"},
+		{"local:/dir/sub/file.xsl", "local:/dir/sub", "file.xsl"},
+		{"local:", "", "local:"},
+	}
+	for _, testCase := range testDataMatrix {
+		t.Run(testCase.dpPath, func(t *testing.T) {
+			parentPath, fileName := splitDpPath(testCase.dpPath)
+			assert.Equals(t, "splitDpPath parent", parentPath, testCase.parentPath)
+			assert.Equals(t, "splitDpPath name", fileName, testCase.fileName)
+		})
+	}
+}
+
+func TestViewConfig(t *testing.T) {
+	testDataMatrix := []struct {
+		target   dpTarget
+		itemType model.ItemType
+		path     string
+		viewMode model.DpViewMode
+	}{
+		{dpTarget{appliance: "MyDp"}, model.ItemDpConfiguration, "", model.DpFilestoreMode},
+		{dpTarget{appliance: "MyDp", domain: "default"}, model.ItemDpDomain, "", model.DpFilestoreMode},
+		{dpTarget{appliance: "MyDp", domain: "default", path: "local:/dir"}, model.ItemDirectory, "local:/dir", model.DpFilestoreMode},
+		{dpTarget{appliance: "MyDp", domain: "default", path: "objects"}, model.ItemDpObjectClassList, "Object classes", model.DpObjectMode},
+		{dpTarget{appliance: "MyDp", domain: "default", path: "objects/XMLFirewallService"}, model.ItemDpObjectClass, "XMLFirewallService", model.DpObjectMode},
+		{dpTarget{appliance: "MyDp", domain: "default", path: "status"}, model.ItemDpStatusClassList, "Status classes", model.DpStatusMode},
+		{dpTarget{appliance: "MyDp", domain: "default", path: "status/ObjectStatus"}, model.ItemDpStatusClass, "ObjectStatus", model.DpStatusMode},
+	}
+	for _, testCase := range testDataMatrix {
+		t.Run(testCase.target.String(), func(t *testing.T) {
+			got, err := viewConfig(testCase.target)
+			assert.Nil(t, "viewConfig", err)
+			assert.Equals(t, "viewConfig type", got.Type, testCase.itemType)
+			assert.Equals(t, "viewConfig path", got.Path, testCase.path)
+			assert.Equals(t, "viewConfig domain", got.DpDomain, testCase.target.domain)
+			if testCase.target.path != "" {
+				assert.Equals(t, "viewConfig view mode", dp.Repo.DpViewMode, testCase.viewMode)
+			}
+		})
+	}
+}
+
+func TestJSONContent(t *testing.T) {
+	got, err := jsonContent([]byte(`{"XMLFirewallService":{"name":"MyFirewall"}}`))
+	assert.Nil(t, "jsonContent", err)
+	assert.Equals(t, "jsonContent", string(got), `{"XMLFirewallService":{"name":"MyFirewall"}}`)
+
+	got, err = jsonContent([]byte(`<XMLFirewallService name="MyFirewall"><mAdminState>enabled</mAdminState></XMLFirewallService>`))
+	assert.Nil(t, "jsonContent", err)
+	var gotMap map[string]interface{}
+	assert.Nil(t, "jsonContent unmarshal", json.Unmarshal(got, &gotMap))
+	assert.DeepEqual(t, "jsonContent", gotMap,
+		map[string]interface{}{"XMLFirewallService": map[string]interface{}{
+			"-name": "MyFirewall", "mAdminState": "enabled"}})
+
+	_, err = jsonContent([]byte("not json, not xml"))
+	assert.NotNil(t, "jsonContent", err)
+}
+
+func TestObjectArg(t *testing.T) {
+	objectFile := filepath.Join(t.TempDir(), "object.xml")
+	objectContent := []byte(`<XMLManager name="default"/>`)
+	err := ioutil.WriteFile(objectFile, objectContent, 0644)
+	assert.Nil(t, "objectArg", err)
+
+	got, err := objectArg(objectFile, model.Left)
+	assert.Nil(t, "objectArg", err)
+	assert.DeepEqual(t, "objectArg", got, objectContent)
+
+	_, err = objectArg(objectFile+".missing", model.Right)
+	assert.DeepEqual(t, "objectArg", err,
+		notFoundErrorf("Local file '%s' not found.", objectFile+".missing"))
+}
+
+func TestSyncSessionArgs(t *testing.T) {
+	localDir := t.TempDir()
+	config.Conf.SyncSessions = map[string]config.SyncSession{
+		"saved": {DpAppliance: "MyDp", DpDomain: "default", DirDp: "local:/dir", DirLocal: "/tmp/dir",
+			PostSyncActions: []config.PostSyncAction{{Type: config.PostSyncFlushStylesheetCache}}}}
+	defer func() { config.Conf.SyncSessions = nil }()
+
+	got, err := syncSessionArgs([]string{"saved"})
+	assert.Nil(t, "syncSessionArgs", err)
+	assert.DeepEqual(t, "syncSessionArgs", got, &model.SyncSession{Name: "saved",
+		DpAppliance: "MyDp", DpDomain: "default", DirDp: "local:/dir", DirLocal: "/tmp/dir",
+		PostSyncActions: []config.PostSyncAction{{Type: config.PostSyncFlushStylesheetCache}}})
+
+	got, err = syncSessionArgs([]string{"MyDp:default:local:///dir/", localDir})
+	assert.Nil(t, "syncSessionArgs", err)
+	assert.DeepEqual(t, "syncSessionArgs", got, &model.SyncSession{Name: filepath.Base(localDir),
+		DpAppliance: "MyDp", DpDomain: "default", DirDp: "local:/dir", DirLocal: localDir})
+
+	// Ad hoc session doesn't use name (and post sync actions) of the saved session.
+	savedConfig := config.Conf.SyncSessions["saved"]
+	config.Conf.SyncSessions[filepath.Base(localDir)] = savedConfig
+	config.Conf.SyncSessions[filepath.Base(localDir)+"-2"] = savedConfig
+	got, err = syncSessionArgs([]string{"MyDp:default:local:///dir/", localDir})
+	assert.Nil(t, "syncSessionArgs", err)
+	assert.DeepEqual(t, "syncSessionArgs", got, &model.SyncSession{Name: filepath.Base(localDir) + "-3",
+		DpAppliance: "MyDp", DpDomain: "default", DirDp: "local:/dir", DirLocal: localDir})
+
+	_, err = syncSessionArgs([]string{"missing"})
+	assert.DeepEqual(t, "syncSessionArgs", err, notFoundErrorf("Sync session 'missing' not found."))
+	_, err = syncSessionArgs([]string{"MyDp:default", localDir})
+	assert.DeepEqual(t, "syncSessionArgs", err, usageErrorf("Missing DataPower path in 'MyDp:default:'."))
+	_, err = syncSessionArgs([]string{"MyDp:default:local:", localDir + "-missing"})
+	assert.DeepEqual(t, "syncSessionArgs", err,
+		notFoundErrorf("Local directory '%s' not found.", localDir+"-missing"))
+	_, err = syncSessionArgs(nil)
+	assert.DeepEqual(t, "syncSessionArgs", err, usageErrorf("Wrong number of arguments."))
+}
@@ -4,13 +4,14 @@
 package config
 
 import (
-	"encoding/base32"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"os/user"
+	"path"
 	"strings"
+	"sync"
 
 	"github.com/croz-ltd/confident"
 	"github.com/croz-ltd/dpcmder/help"
@@ -23,6 +24,8 @@ const (
 	// configDirName & configFileName are used to save / find dpcmder configuration.
 	configDirName  = ".dpcmder"
 	configFileName = "config.json"
+	// syncDirName is name of the configuration subdirectory used to save sync state.
+	syncDirName = "sync"
 	// PreviousApplianceName is name of configuration for the last appliance
 	// configured with command-line parameters (without explicitly saving config).
 	PreviousApplianceName = "_PreviousAppliance_"
@@ -42,6 +45,8 @@ var (
 	// dpcmder.log file in current folder.
 	DebugLogFile *bool
 	TraceLogFile *bool
+	// JSONOutput enables machine-readable (JSON) output of non-interactive commands.
+	JSONOutput *bool
 	// DataPower connection parameters.
 	dpRestURL    *string
 	dpSomaURL    *string
@@ -50,6 +55,8 @@ var (
 	dpDomain     *string
 	proxy        *string
 	dpConfigName *string
+	// encryptPasswords enables encryption of saved DataPower passwords.
+	encryptPasswords *bool
 	// Help/Usage/Version flags - shows usage, help or version and exit.
 	helpUsage *bool
 	helpFull  *bool
@@ -60,12 +67,23 @@ var (
 // not saved to config during (other) configuration changes.
 var DpTransientPasswordMap = make(map[string]string)
 
+// dpHelperPasswordMap contains passwords printed by credential helpers so
+// helpers are not run for each DataPower request.
+var dpHelperPasswordMap = make(map[string]string)
+
+// transientPasswordsMutex guards transient & helper password maps which are
+// used by background goroutines too.
+var transientPasswordsMutex sync.Mutex
+
 // Config is a structure containing dpcmder configuration (saved to JSON).
 type Config struct {
 	Cmd                 Command
 	Log                 Log
 	Sync                Sync
+	Net                 Net
+	SyncSessions        map[string]SyncSession
 	DataPowerAppliances map[string]DataPowerAppliance
+	PasswordStore       *PasswordStore `json:",omitempty"`
 }
 
 // Command is a structure containing dpcmder external command configuration.
@@ -82,20 +100,115 @@ type Log struct {
 }
 
 // Sync is a structure containing dpcmder synchronization configuration used
-// when syncing local filesystem to datapower is enabled.
+// when syncing local filesystem to datapower is enabled. Local changes are
+// detected using filesystem notifications (debounced for DebounceMillis) if
+// possible, otherwise (or if Polling is set) local filesystem is checked every
+// Seconds. If TwoWay is set, changes made on the DataPower are copied to the
+// local filesystem too (DataPower is checked every Seconds). If DeleteRemoved
+// is set, files & directories removed from the local filesystem are deleted
+// from the DataPower too (and vice versa for two-way sync). IgnorePatterns contains
+// gitignore-style patterns of local files & directories which are not synced
+// (added to patterns found in the .dpcmderignore file of the synced directory).
 type Sync struct {
-	Seconds int
+	Seconds        int
+	DebounceMillis int
+	Polling        bool
+	TwoWay         bool
+	DeleteRemoved  bool
+	IgnorePatterns []string
+}
+
+// Net is a structure containing dpcmder network configuration used for
+// DataPower management calls. ConnectSeconds limits time spent connecting to
+// the appliance (including TLS handshake) and ReadSeconds limits time spent
+// waiting for the DataPower response (exports can take a while). GET requests
+// failing because of network errors are retried up to Retries times.
+type Net struct {
+	ConnectSeconds int
+	ReadSeconds    int
+	Retries        int
+}
+
+// SyncSession is a structure containing configuration of one named sync
+// session - local directory synced to the DataPower directory. PostSyncActions
+// are run (in the given order) after each batch of files is uploaded.
+type SyncSession struct {
+	DpAppliance     string
+	DpDomain        string
+	DirDp           string
+	DirLocal        string
+	PostSyncActions []PostSyncAction `json:",omitempty"`
+}
+
+// Post sync action types.
+const (
+	PostSyncFlushStylesheetCache = "FlushStylesheetCache"
+	PostSyncFlushDocumentCache   = "FlushDocumentCache"
+	PostSyncExecConfig           = "ExecConfig"
+	PostSyncCommand              = "Command"
+)
+
+// PostSyncAction is a structure containing configuration of an action run
+// after files are uploaded to the DataPower in sync mode:
+//   - FlushStylesheetCache/FlushDocumentCache flush cache of the XMLManager (of
+//     all XML managers in the synced domain if XMLManager is not set),
+//   - ExecConfig runs DataPower configuration script from the Path,
+//   - Command runs local Command (in the synced local directory) with DataPower
+//     paths of uploaded files appended as arguments.
+//
+// If FilePatterns are set action is run only when name of any uploaded file
+// matches one of the patterns (for example "*.xsl").
+type PostSyncAction struct {
+	Type         string
+	XMLManager   string   `json:",omitempty"`
+	Path         string   `json:",omitempty"`
+	Command      []string `json:",omitempty"`
+	FilePatterns []string `json:",omitempty"`
+}
+
+// MatchingFiles returns DataPower paths of uploaded files which trigger the
+// action.
+func (a PostSyncAction) MatchingFiles(dpPaths []string) []string {
+	if len(a.FilePatterns) == 0 {
+		return dpPaths
+	}
+	matchingPaths := make([]string, 0)
+	for _, dpPath := range dpPaths {
+		fileName := path.Base(dpPath)
+		for _, pattern := range a.FilePatterns {
+			if matched, _ := path.Match(pattern, fileName); matched {
+				matchingPaths = append(matchingPaths, dpPath)
+				break
+			}
+		}
+	}
+
+	return matchingPaths
 }
 
 // DataPowerAppliance is a structure containing dpcmder DataPower appliance
-// configuration details required to connect to appliances.
+// configuration details required to connect to appliances. CredentialHelper
+// is a command which prints the password (used when Password is not saved).
+// Proxy is URL of the proxy server (credentials can be given in the URL),
+// NoProxy contains comma-separated hosts which are accessed without proxy.
+// TLS certificate of the appliance is verified if TLSVerify is set (using
+// system CAs and CAs from the TLSCAFile) or if TLSFingerprint (SHA-256 hash of
+// the certificate) is set. TLSClientCertFile and TLSClientKeyFile are PEM
+// files used for mutual TLS.
 type DataPowerAppliance struct {
-	RestUrl  string
-	SomaUrl  string
-	Username string
-	Password string
-	Domain   string
-	Proxy    string
+	RestUrl           string
+	SomaUrl           string
+	Username          string
+	Password          string
+	Domain            string
+	Proxy             string
+	NoProxy           string `json:",omitempty"`
+	CredentialHelper  string `json:",omitempty"`
+	TLSVerify         bool   `json:",omitempty"`
+	TLSCAFile         string `json:",omitempty"`
+	TLSFingerprint    string `json:",omitempty"`
+	TLSClientCertFile string `json:",omitempty"`
+	TLSClientKeyFile  string `json:",omitempty"`
 }
 
 // List of DataPower management interfaces - returned by DpManagmentInterface().
@@ -106,21 +219,19 @@ const (
 )
 
 // SetDpPlaintextPassword sets encoded Password field on DataPowerAppliance
-// from plaintext password.
+// from plaintext password (encrypted if saved passwords are encrypted).
 func (dpa *DataPowerAppliance) SetDpPlaintextPassword(password string) {
-	b32password := base32.StdEncoding.EncodeToString([]byte(password))
-	dpa.Password = b32password
+	dpa.Password = encodeDpPassword(password)
 }
 
 // DpPlaintextPassword fetches decoded password from DataPowerAppliance struct.
 func (dpa *DataPowerAppliance) DpPlaintextPassword() string {
-	passBytes, err := base32.StdEncoding.DecodeString(dpa.Password)
+	password, err := decodeDpPassword(dpa.Password)
 	if err != nil {
-		logging.LogDebugf("config/DataPowerAppliance.DpPlaintextPassword() - Can't decode password: '%s', err: %v",
-			dpa.Password, err)
+		logging.LogDebugf("config/DataPowerAppliance.DpPlaintextPassword() - Can't decode password, err: %v", err)
 		return ""
 	}
-	return string(passBytes)
+	return password
 }
 
 // DpManagmentInterface returns management interface used to manage DataPower.
@@ -141,8 +252,10 @@ func (dpa *DataPowerAppliance) DpManagmentInterface() string {
 var Conf = Config{
 	Cmd: Command{
 		Viewer: "less", Editor: "vi", Diff: "diff"},
-	Log:                 Log{MaxEntrySize: logging.MaxEntrySize},
-	Sync:                Sync{Seconds: 4},
+	Log: Log{MaxEntrySize: logging.MaxEntrySize},
+	Sync: Sync{Seconds: 4, DebounceMillis: 200,
+		IgnorePatterns: []string{".git/", "*.swp", "*~"}},
+	Net:                 Net{ConnectSeconds: 10, ReadSeconds: 300, Retries: 2},
 	DataPowerAppliances: make(map[string]DataPowerAppliance)}
 
 // k is Confident library configuration instance.
@@ -161,6 +274,15 @@ func initConfiguration() {
 	logging.LogDebugf("config/initConfiguration() - Conf before read: %#v", Conf)
 	k.Read()
 	logging.LogDebugf("config/initConfiguration() - Conf after read: %#v", Conf)
+	unlockPasswordStore()
+	if *encryptPasswords {
+		enablePasswordStore()
+	}
+	if *dpPassword != "" {
+		// Password given as flag should be saved encrypted too.
+		password, _ := decodeDpPassword(*dpPassword)
+		setDpPasswordPlain(password)
+	}
 	if *dpRestURL != "" || *dpSomaURL != "" {
 		if *dpConfigName != "" {
 			validateDpConfigName()
@@ -188,6 +310,8 @@ func parseProgramArgs() {
 	dpConfigName = flag.String("c", "", "Name of DataPower connection configuration to save with given configuration params")
 	DebugLogFile = flag.Bool("debug", false, "Write debug dpcmder.log file in current dir")
 	TraceLogFile = flag.Bool("trace", false, "Write trace dpcmder.log file in current dir")
+	JSONOutput = flag.Bool("json", false, "Write results of non-interactive commands as JSON")
+	encryptPasswords = flag.Bool("encrypt-passwords", false, "Encrypt saved DataPower passwords using master passphrase")
 	helpUsage = flag.Bool("h", false, "Show dpcmder usage with examples")
 	helpFull = flag.Bool("help", false, "Show dpcmder in-program help on console")
 	version = flag.Bool("v", false, "Show dpcmder version")
@@ -207,6 +331,13 @@ func Init() {
 	validatePassword()
 }
 
+// CommandArgs returns program arguments left after flags are parsed - name of
+// non-interactive command with its arguments (empty if dpcmder should start
+// in interactive mode).
+func CommandArgs() []string {
+	return flag.Args()
+}
+
 // validateProgramArgs validate parsed program arguments and/or shows usage
 // message in case some mandatory arguments are missing.
 func validateProgramArgs() {
@@ -253,7 +384,7 @@ func validatePassword() {
 			} else {
 				password := string(pass)
 				setDpPasswordPlain(password)
-				DpTransientPasswordMap[CurrentApplianceName] = password
+				SetDpTransientPassword(CurrentApplianceName, password)
 			}
 		}
 
@@ -294,11 +425,24 @@ func configDirPathEnsureExists() string {
 	return configDirPath
 }
 
+// SyncDirPath returns path of the directory used to save sync state (manifests
+// of synced files) and in case it doesn't exist creates directory.
+func SyncDirPath() (string, error) {
+	syncDirPath := paths.GetFilePath(configDirPathEnsureExists(), syncDirName)
+	err := os.MkdirAll(syncDirPath, os.ModePerm)
+	if err != nil {
+		logging.LogDebug("config/SyncDirPath() - Can't create sync directory: ", err)
+		return "", err
+	}
+
+	return syncDirPath, nil
+}
+
 // setDpPasswordPlain sets config dpPassword encoded password field from
 // plaintext password.
 func setDpPasswordPlain(password string) {
-	b32password := base32.StdEncoding.EncodeToString([]byte(password))
-	dpPassword = &b32password
+	encodedPassword := encodeDpPassword(password)
+	dpPassword = &encodedPassword
 }
 
 // CreateDpApplianceConfig creates empty DataPower appliance JSON configuration as byte array.
@@ -338,6 +482,33 @@ func (c *Config) SetDpApplianceConfig(name string, contents []byte) error {
 	return nil
 }
 
+// SetSyncSession saves sync session configuration.
+func (c *Config) SetSyncSession(name string, session SyncSession) {
+	if c.SyncSessions == nil {
+		c.SyncSessions = make(map[string]SyncSession)
+	}
+	c.SyncSessions[name] = session
+	k.Persist()
+}
+
+// DeleteSyncSession deletes sync session configuration.
+func (c *Config) DeleteSyncSession(name string) {
+	delete(c.SyncSessions, name)
+	k.Persist()
+}
+
+// TrustDpApplianceCertificate saves fingerprint of the trusted TLS certificate
+// of the DataPower appliance.
+func (c *Config) TrustDpApplianceCertificate(name, fingerprint string) {
+	dpa, ok := c.DataPowerAppliances[name]
+	if !ok {
+		return
+	}
+	dpa.TLSFingerprint = fingerprint
+	c.DataPowerAppliances[name] = dpa
+	k.Persist()
+}
+
 // DeleteDpApplianceConfig deletes DataPower appliance JSON configuration.
 func (c *Config) DeleteDpApplianceConfig(name string) {
 	delete(c.DataPowerAppliances, name)
@@ -377,7 +548,7 @@ func LogConfig() {
 // usage prints usage help information with examples to console.
 func usage(exitStatus int) {
 	fmt.Println("Usage:")
-	fmt.Printf(" %s [-l LOCAL_FOLDER_PATH] [-r DATA_POWER_REST_URL | -s DATA_POWER_SOMA_AMP_URL] [-u USERNAME] [-p PASSWORD] [-d DP_DOMAIN] [-x PROXY_SERVER] [-c DP_CONFIG_NAME] [-debug] [-h] [-help]\n", os.Args[0])
+	fmt.Printf(" %s [-l LOCAL_FOLDER_PATH] [-r DATA_POWER_REST_URL | -s DATA_POWER_SOMA_AMP_URL] [-u USERNAME] [-p PASSWORD] [-d DP_DOMAIN] [-x PROXY_SERVER] [-c DP_CONFIG_NAME] [-encrypt-passwords] [-debug] [-json] [-h] [-help] [COMMAND [ARGS...]]\n", os.Args[0])
 	fmt.Println("")
 	fmt.Println(" -l LOCAL_FOLDER_PATH - set path to local folder")
 	fmt.Println(" -r DATA_POWER_REST_URL - set REST management URL for DataPower")
@@ -387,11 +558,14 @@ func usage(exitStatus int) {
 	fmt.Println(" -d DP_DOMAIN - connect to specific DataPower domain (can be neccessary on some security configurations)")
 	fmt.Println(" -x PROXY_SERVER - connect to DataPower through proxy")
 	fmt.Println(" -c DP_CONFIG_NAME - save DataPower configuration under given name")
+	fmt.Println(" -encrypt-passwords - encrypt saved DataPower passwords using master passphrase (asked on each start or read from DPCMDER_PASSPHRASE)")
 	fmt.Println(" -debug - turns on creation of dpcmder.log file with debug log messages")
 	fmt.Println(" -trace - turns on creation of dpcmder.log file with trace log messages")
+	fmt.Println(" -json - writes results of non-interactive commands as JSON")
 	fmt.Println(" -h - shows this (usage) help")
 	fmt.Println(" -help - shows dpcmder full help on console")
 	fmt.Println(" -v - shows dpcmder version")
+	fmt.Println(" COMMAND - runs non-interactive command (ls, get, put, rm, mkdir, export-domain, save-config, exec, diff-objects, sync)")
 	fmt.Println("")
 	fmt.Println("")
 	fmt.Println("Example:")
@@ -405,6 +579,10 @@ func usage(exitStatus int) {
 	fmt.Println("   - connect to DataPower using SOMA managment interface and write debug messages to ./dpcmder.log file")
 	fmt.Printf(" %s -s https://localhost:5550 -u admin -p admin -c LocalDp\n", os.Args[0])
 	fmt.Println("   - connect to DataPower using SOMA managment interface and save configuration parameters as LocalDp")
+	fmt.Printf(" %s ls LocalDp:default:local:///dir\n", os.Args[0])
+	fmt.Println("   - lists local:///dir directory of default domain on DataPower saved as LocalDp")
+	fmt.Printf(" %s put test.xsl LocalDp:default:local:///dir/test.xsl\n", os.Args[0])
+	fmt.Println("   - uploads test.xsl to local:///dir directory of default domain on DataPower saved as LocalDp")
 
 	os.Exit(exitStatus)
 }
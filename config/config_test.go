@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/croz-ltd/dpcmder/utils/assert"
+)
+
+func TestPostSyncActionMatchingFiles(t *testing.T) {
+	uploaded := []string{"local:/xsl/a.xsl", "local:/xsl/b.XSL", "local:/js/c.js", "local:/cfg/d.xml"}
+
+	testDataMatrix := []struct {
+		name     string
+		patterns []string
+		want     []string
+	}{
+		{"no patterns", nil, uploaded},
+		{"extension", []string{"*.xsl"}, []string{"local:/xsl/a.xsl"}},
+		{"more patterns", []string{"*.js", "*.xml"}, []string{"local:/js/c.js", "local:/cfg/d.xml"}},
+		{"file name only", []string{"xsl/*"}, []string{}},
+		{"exact name", []string{"b.XSL"}, []string{"local:/xsl/b.XSL"}},
+		{"character class", []string{"[ab].*"}, []string{"local:/xsl/a.xsl", "local:/xsl/b.XSL"}},
+		{"malformed pattern", []string{"[a"}, []string{}},
+		{"no match", []string{"*.json"}, []string{}},
+	}
+
+	for _, testRow := range testDataMatrix {
+		action := PostSyncAction{Type: PostSyncCommand, FilePatterns: testRow.patterns}
+		assert.DeepEqual(t, "MatchingFiles() "+testRow.name, action.MatchingFiles(uploaded), testRow.want)
+	}
+	assert.DeepEqual(t, "MatchingFiles() nothing uploaded",
+		PostSyncAction{FilePatterns: []string{"*.xsl"}}.MatchingFiles(nil), []string{})
+}
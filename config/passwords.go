@@ -0,0 +1,323 @@
+package config
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/croz-ltd/dpcmder/utils/errs"
+	"github.com/croz-ltd/dpcmder/utils/logging"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+const (
+	// encryptedPasswordPrefix marks passwords encrypted with the master key
+	// (base32 encoded passwords can't contain ':').
+	encryptedPasswordPrefix = "enc:"
+	// passwordStoreCheck is encrypted when password store is created to check
+	// if the correct master passphrase is entered.
+	passwordStoreCheck = "dpcmder"
+	// PassphraseEnvVar is environment variable which can be used to give
+	// master passphrase to dpcmder running without a terminal.
+	PassphraseEnvVar = "DPCMDER_PASSPHRASE"
+	// scrypt key derivation parameters.
+	scryptN      = 32768
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+)
+
+// credentialHelperTimeout is the longest time credential helper can run.
+var credentialHelperTimeout = 30 * time.Second
+
+// wrongPassphraseError is returned when master passphrase entered is wrong.
+const wrongPassphraseError = errs.Error("Wrong master passphrase.")
+
+// PasswordStore is a structure containing parameters used to check master
+// passphrase and derive the key used to encrypt DataPower passwords saved in
+// the configuration file.
+type PasswordStore struct {
+	Salt  string
+	Check string
+}
+
+// masterKey is the key used to encrypt and decrypt saved DataPower passwords,
+// set when password store is enabled and unlocked using master passphrase.
+var masterKey []byte
+
+// encodeDpPassword encodes plaintext password to the form saved in the
+// configuration - encrypted if password store is unlocked, base32 otherwise.
+func encodeDpPassword(password string) string {
+	if password == "" {
+		return ""
+	}
+	if masterKey != nil {
+		encrypted, err := encryptPassword(masterKey, password)
+		if err == nil {
+			return encrypted
+		}
+		logging.LogDebug("config/encodeDpPassword() - Can't encrypt password: ", err)
+	}
+	return base32.StdEncoding.EncodeToString([]byte(password))
+}
+
+// decodeDpPassword decodes password saved in the configuration to plaintext.
+func decodeDpPassword(password string) (string, error) {
+	if strings.HasPrefix(password, encryptedPasswordPrefix) {
+		if masterKey == nil {
+			return "", errs.Error("Password store is locked.")
+		}
+		return decryptPassword(masterKey, password)
+	}
+	passBytes, err := base32.StdEncoding.DecodeString(password)
+	if err != nil {
+		return "", err
+	}
+	return string(passBytes), nil
+}
+
+// deriveKey derives key used to encrypt passwords from the master passphrase.
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+}
+
+// encryptPassword encrypts password using AES-GCM, nonce is saved before the
+// ciphertext.
+func encryptPassword(key []byte, password string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(password), nil)
+	return encryptedPasswordPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptPassword decrypts password encrypted using encryptPassword.
+func decryptPassword(key []byte, encrypted string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(encrypted, encryptedPasswordPrefix))
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errs.Error("Encrypted password too short.")
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errs.Error("Can't decrypt password (wrong master passphrase?).")
+	}
+	return string(plain), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// newPasswordStore creates password store for the master passphrase and
+// returns it with the derived key.
+func newPasswordStore(passphrase string) (*PasswordStore, []byte, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, err
+	}
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	check, err := encryptPassword(key, passwordStoreCheck)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &PasswordStore{Salt: base64.StdEncoding.EncodeToString(salt), Check: check}, key, nil
+}
+
+// unlock derives key from the master passphrase and checks if it is correct.
+func (ps *PasswordStore) unlock(passphrase string) ([]byte, error) {
+	salt, err := base64.StdEncoding.DecodeString(ps.Salt)
+	if err != nil {
+		return nil, err
+	}
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	check, err := decryptPassword(key, ps.Check)
+	if err != nil || check != passwordStoreCheck {
+		return nil, wrongPassphraseError
+	}
+	return key, nil
+}
+
+// migratePasswords encrypts all DataPower passwords which are saved base32
+// encoded, returns true if any password is changed.
+func (c *Config) migratePasswords() bool {
+	migrated := false
+	for name, dpa := range c.DataPowerAppliances {
+		if dpa.Password == "" || strings.HasPrefix(dpa.Password, encryptedPasswordPrefix) {
+			continue
+		}
+		password, err := decodeDpPassword(dpa.Password)
+		if err != nil {
+			logging.LogDebugf("config/migratePasswords() - Can't decode password for '%s': %v", name, err)
+			continue
+		}
+		dpa.Password = encodeDpPassword(password)
+		c.DataPowerAppliances[name] = dpa
+		migrated = true
+	}
+	return migrated
+}
+
+// DpTransientPassword returns password of the appliance which is not saved in
+// the configuration - password entered through dpcmder dialogs or password
+// printed by the credential helper configured for the appliance. Credential
+// helper is run (outside of the lock) only if its password is not cached.
+func DpTransientPassword(applianceName string) string {
+	transientPasswordsMutex.Lock()
+	password := DpTransientPasswordMap[applianceName]
+	if password == "" {
+		password = dpHelperPasswordMap[applianceName]
+	}
+	dpa := Conf.DataPowerAppliances[applianceName]
+	transientPasswordsMutex.Unlock()
+	if password != "" || dpa.CredentialHelper == "" {
+		return password
+	}
+
+	password, err := dpa.helperPassword(applianceName)
+	if err != nil {
+		logging.LogDebugf("config/DpTransientPassword('%s') - credential helper err: %v", applianceName, err)
+		return ""
+	}
+	transientPasswordsMutex.Lock()
+	dpHelperPasswordMap[applianceName] = password
+	transientPasswordsMutex.Unlock()
+	return password
+}
+
+// helperPassword runs credential helper of the appliance using the shell and
+// returns the first line it prints. Appliance name and username are given to
+// the helper as DPCMDER_APPLIANCE and DPCMDER_USERNAME environment variables.
+// Helper is killed if it doesn't print the password in credentialHelperTimeout.
+func (dpa *DataPowerAppliance) helperPassword(applianceName string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), credentialHelperTimeout)
+	defer cancel()
+	helperCmd := exec.CommandContext(ctx, "sh", "-c", dpa.CredentialHelper)
+	if runtime.GOOS == "windows" {
+		helperCmd = exec.CommandContext(ctx, "cmd", "/C", dpa.CredentialHelper)
+	}
+	helperCmd.Env = append(os.Environ(),
+		"DPCMDER_APPLIANCE="+applianceName, "DPCMDER_USERNAME="+dpa.Username)
+	helperCmd.WaitDelay = time.Second
+	output, err := helperCmd.Output()
+	if ctx.Err() != nil {
+		return "", errs.Errorf("Credential helper '%s' stopped: %v", dpa.CredentialHelper, ctx.Err())
+	}
+	if err != nil {
+		return "", errs.Errorf("Credential helper '%s' failed: %v", dpa.CredentialHelper, err)
+	}
+	password := strings.TrimRight(strings.SplitN(string(output), "\n", 2)[0], "\r")
+	if password == "" {
+		return "", errs.Errorf("Credential helper '%s' returned empty password.", dpa.CredentialHelper)
+	}
+	return password, nil
+}
+
+// readPassphrase reads master passphrase from the environment variable or
+// asks user to enter it.
+func readPassphrase(prompt string) (string, error) {
+	if passphrase := os.Getenv(PassphraseEnvVar); passphrase != "" {
+		return passphrase, nil
+	}
+	fmt.Println(prompt)
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	if err != nil {
+		return "", err
+	}
+	return string(passphrase), nil
+}
+
+// unlockPasswordStore asks for the master passphrase if saved passwords are
+// encrypted and migrates any passwords saved base32 encoded.
+func unlockPasswordStore() {
+	if Conf.PasswordStore == nil {
+		return
+	}
+	for tries := 0; masterKey == nil; tries++ {
+		passphrase, err := readPassphrase("Master passphrase: ")
+		if err == nil {
+			masterKey, err = Conf.PasswordStore.unlock(passphrase)
+		}
+		if err != nil {
+			fmt.Println(err)
+			if tries == 2 || os.Getenv(PassphraseEnvVar) != "" {
+				os.Exit(1)
+			}
+		}
+	}
+	if Conf.migratePasswords() {
+		k.Persist()
+	}
+}
+
+// enablePasswordStore creates password store protected with a new master
+// passphrase and encrypts all saved DataPower passwords.
+func enablePasswordStore() {
+	if Conf.PasswordStore != nil {
+		fmt.Println("Saved passwords are already encrypted.")
+		return
+	}
+	passphrase, err := readPassphrase("New master passphrase: ")
+	if err == nil && os.Getenv(PassphraseEnvVar) == "" {
+		var repeated string
+		repeated, err = readPassphrase("Repeat master passphrase: ")
+		if err == nil && repeated != passphrase {
+			err = errs.Error("Master passphrases don't match.")
+		}
+	}
+	if err == nil && passphrase == "" {
+		err = errs.Error("Master passphrase can't be empty.")
+	}
+	if err == nil {
+		Conf.PasswordStore, masterKey, err = newPasswordStore(passphrase)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	Conf.migratePasswords()
+	k.Persist()
+	fmt.Println("Saved passwords encrypted using master passphrase.")
+}
+
+// SetDpTransientPassword sets password of the appliance entered through
+// dpcmder dialogs (password is not saved to the configuration). Password
+// printed by the credential helper is forgotten so helper is run again when
+// the password is cleared (for example when authentication fails).
+func SetDpTransientPassword(applianceName, password string) {
+	transientPasswordsMutex.Lock()
+	defer transientPasswordsMutex.Unlock()
+	DpTransientPasswordMap[applianceName] = password
+	delete(dpHelperPasswordMap, applianceName)
+}
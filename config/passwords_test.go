@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/croz-ltd/dpcmder/utils/assert"
+)
+
+func TestEncryptPassword(t *testing.T) {
+	key := make([]byte, scryptKeyLen)
+	encrypted, err := encryptPassword(key, "secret")
+	assert.Nil(t, "encryptPassword", err)
+	assert.True(t, "encryptPassword", strings.HasPrefix(encrypted, encryptedPasswordPrefix))
+	encryptedAgain, _ := encryptPassword(key, "secret")
+	assert.True(t, "encryptPassword nonce", encrypted != encryptedAgain)
+
+	decrypted, err := decryptPassword(key, encrypted)
+	assert.Nil(t, "decryptPassword", err)
+	assert.Equals(t, "decryptPassword", decrypted, "secret")
+
+	otherKey := make([]byte, scryptKeyLen)
+	otherKey[0] = 1
+	_, err = decryptPassword(otherKey, encrypted)
+	assert.NotNil(t, "decryptPassword", err)
+	_, err = decryptPassword(key, encryptedPasswordPrefix+"AAAA")
+	assert.NotNil(t, "decryptPassword", err)
+}
+
+func TestPasswordStoreUnlock(t *testing.T) {
+	store, key, err := newPasswordStore("passphrase")
+	assert.Nil(t, "newPasswordStore", err)
+
+	unlockedKey, err := store.unlock("passphrase")
+	assert.Nil(t, "unlock", err)
+	assert.DeepEqual(t, "unlock", unlockedKey, key)
+
+	_, err = store.unlock("wrong")
+	assert.Equals(t, "unlock", err, error(wrongPassphraseError))
+}
+
+func TestMigratePasswords(t *testing.T) {
+	defer func() { masterKey = nil }()
+	masterKey = nil
+	plainDpa := DataPowerAppliance{}
+	plainDpa.SetDpPlaintextPassword("secret")
+	assert.Equals(t, "SetDpPlaintextPassword", plainDpa.Password, "ONSWG4TFOQ======")
+	conf := Config{DataPowerAppliances: map[string]DataPowerAppliance{
+		"dp": plainDpa, "no-password": {}}}
+
+	masterKey = make([]byte, scryptKeyLen)
+	assert.True(t, "migratePasswords", conf.migratePasswords())
+	migratedDpa := conf.DataPowerAppliances["dp"]
+	assert.True(t, "migratePasswords", strings.HasPrefix(migratedDpa.Password, encryptedPasswordPrefix))
+	assert.Equals(t, "DpPlaintextPassword", migratedDpa.DpPlaintextPassword(), "secret")
+	assert.Equals(t, "migratePasswords", conf.DataPowerAppliances["no-password"].Password, "")
+	assert.False(t, "migratePasswords", conf.migratePasswords())
+
+	masterKey = nil
+	assert.Equals(t, "DpPlaintextPassword", migratedDpa.DpPlaintextPassword(), "")
+}
+
+func TestDpTransientPassword(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper test uses sh printf")
+	}
+	defer func() {
+		Conf.DataPowerAppliances = make(map[string]DataPowerAppliance)
+		DpTransientPasswordMap = make(map[string]string)
+		dpHelperPasswordMap = make(map[string]string)
+	}()
+	Conf.DataPowerAppliances = map[string]DataPowerAppliance{
+		"helper":        {Username: "admin", CredentialHelper: `printf "$DPCMDER_USERNAME@$DPCMDER_APPLIANCE\nignored"`},
+		"failed-helper": {CredentialHelper: "false"},
+		"no-helper":     {}}
+	DpTransientPasswordMap = map[string]string{"failed-helper": "entered", "no-helper": "entered"}
+
+	assert.Equals(t, "DpTransientPassword", DpTransientPassword("helper"), "admin@helper")
+	assert.Equals(t, "DpTransientPassword", DpTransientPassword("failed-helper"), "entered")
+	assert.Equals(t, "DpTransientPassword", DpTransientPassword("no-helper"), "entered")
+	assert.Equals(t, "DpTransientPassword", DpTransientPassword("missing"), "")
+
+	// Helper password is cached until password is set or cleared.
+	Conf.DataPowerAppliances["helper"] = DataPowerAppliance{CredentialHelper: "echo rotated"}
+	assert.Equals(t, "DpTransientPassword cached", DpTransientPassword("helper"), "admin@helper")
+	SetDpTransientPassword("helper", "entered")
+	assert.Equals(t, "DpTransientPassword entered", DpTransientPassword("helper"), "entered")
+	SetDpTransientPassword("helper", "")
+	assert.Equals(t, "DpTransientPassword cleared", DpTransientPassword("helper"), "rotated")
+}
+
+func TestDpTransientPasswordHelperTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper test uses sh sleep")
+	}
+	defer func(timeout time.Duration) {
+		credentialHelperTimeout = timeout
+		Conf.DataPowerAppliances = make(map[string]DataPowerAppliance)
+		dpHelperPasswordMap = make(map[string]string)
+	}(credentialHelperTimeout)
+	credentialHelperTimeout = 100 * time.Millisecond
+	Conf.DataPowerAppliances = map[string]DataPowerAppliance{
+		"slow-helper": {CredentialHelper: "sleep 10; echo late"}}
+
+	started := time.Now()
+	assert.Equals(t, "DpTransientPassword", DpTransientPassword("slow-helper"), "")
+	assert.True(t, "DpTransientPassword stopped after timeout", time.Since(started) < 5*time.Second)
+}
+
+func TestSetDpTransientPassword(t *testing.T) {
+	defer func() { DpTransientPasswordMap = make(map[string]string) }()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for index := 0; index < 100; index++ {
+			SetDpTransientPassword("MyDp", fmt.Sprintf("pass%d", index))
+		}
+	}()
+	for index := 0; index < 100; index++ {
+		DpTransientPassword("MyDp")
+	}
+	<-done
+
+	assert.Equals(t, "DpTransientPassword", DpTransientPassword("MyDp"), "pass99")
+}
@@ -6,6 +6,7 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/croz-ltd/dpcmder/cli"
 	"github.com/croz-ltd/dpcmder/config"
 	"github.com/croz-ltd/dpcmder/ui"
 	"github.com/croz-ltd/dpcmder/utils/logging"
@@ -13,6 +14,9 @@ import (
 
 func main() {
 	config.Init()
+	if commandArgs := config.CommandArgs(); len(commandArgs) > 0 {
+		os.Exit(cli.Run(commandArgs))
+	}
 	config.PrintConfig()
 
 	setupCloseHandler()
@@ -15,10 +15,16 @@ const (
 	UpdateViewShowListSelectionDialog UpdateViewEventType = UpdateViewEventType(3)
 	UpdateViewShowStatus              UpdateViewEventType = UpdateViewEventType(4)
 	UpdateViewShowProgress            UpdateViewEventType = UpdateViewEventType(5)
+	UpdateViewRefreshPanel            UpdateViewEventType = UpdateViewEventType(6)
+	UpdateViewRefreshSyncPanels       UpdateViewEventType = UpdateViewEventType(7)
 )
 
 // UpdateViewEvent contains information neccessary for all types of screen
-// update events.
+// update events. Background goroutines post events to the goroutine reading
+// user input instead of changing the model - UpdateViewShowStatus adds Status
+// to the model, UpdateViewRefreshPanel refreshes items of the panel on the
+// Side and UpdateViewRefreshSyncPanels refreshes panels showing directories
+// of the SyncSession.
 type UpdateViewEvent struct {
 	Type                     UpdateViewEventType
 	Model                    *model.Model
@@ -31,4 +37,6 @@ type UpdateViewEvent struct {
 	ListSelectionMessage     string
 	ListSelectionList        []string
 	ListSelectionSelectedIdx int
+	Side                     model.Side
+	SyncSession              *model.SyncSession
 }
@@ -5,6 +5,7 @@ package extprogs
 
 import (
 	"bytes"
+	"context"
 	"github.com/croz-ltd/dpcmder/config"
 	"github.com/croz-ltd/dpcmder/help"
 	"github.com/croz-ltd/dpcmder/ui/out"
@@ -226,6 +227,32 @@ func Diff(leftPath string, rightPath string) error {
 	return nil
 }
 
+// Run runs command in the given directory without attaching it to the
+// terminal and returns its combined output. Command is killed when the
+// context is done (for example when its timeout expires).
+func Run(ctx context.Context, dirPath string, command []string, args ...string) ([]byte, error) {
+	logging.LogDebugf("extprogs/Run('%s', %v, %v)", dirPath, command, args)
+	if len(command) == 0 {
+		return nil, errs.Error("Command to run not configured.")
+	}
+
+	cmd := exec.CommandContext(ctx, command[0], append(command[1:len(command):len(command)], args...)...)
+	cmd.Dir = dirPath
+	// Don't wait for output of processes started by the killed command.
+	cmd.WaitDelay = time.Second
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		logging.LogDebugf("extprogs/Run() ctx err: %v", ctx.Err())
+		return output, errs.Errorf("Command '%s' stopped: %v", command[0], ctx.Err())
+	}
+	if err != nil {
+		logging.LogDebugf("extprogs/Run() err: %v", err)
+		return output, errs.Errorf("Command '%s' failed: %v", command[0], err)
+	}
+
+	return output, nil
+}
+
 // ShowHelp shows help in configured external viewer.
 func ShowHelp() error {
 	return View("Help", []byte(help.Help))
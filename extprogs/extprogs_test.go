@@ -0,0 +1,38 @@
+package extprogs
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/croz-ltd/dpcmder/utils/assert"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("run test uses sh commands")
+	}
+	dir := t.TempDir()
+
+	output, err := Run(context.Background(), dir, []string{"sh", "-c", `echo "$(basename "$(pwd)") $*"`, "sh"},
+		"local:/a.xsl", "local:/b.xsl")
+	assert.Nil(t, "Run", err)
+	assert.Equals(t, "Run", strings.TrimSpace(string(output)), filepath.Base(dir)+" local:/a.xsl local:/b.xsl")
+
+	output, err = Run(context.Background(), dir, []string{"sh", "-c", "echo failed; exit 3"})
+	assert.NotNil(t, "Run", err)
+	assert.Equals(t, "Run", strings.TrimSpace(string(output)), "failed")
+
+	_, err = Run(context.Background(), dir, nil)
+	assert.NotNil(t, "Run", err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	_, err = Run(ctx, dir, []string{"sh", "-c", "sleep 10 & sleep 10"})
+	assert.NotNil(t, "Run", err)
+	assert.True(t, "Run stopped after timeout", time.Since(started) < 5*time.Second)
+}
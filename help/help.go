@@ -35,6 +35,9 @@ Shift+ArrowRight / L - navigate to the next view for the current side
 H                    - show view history list - can jump to any view in the current history
 Space                - select current item
 TAB                  - switch from left to right panel and vice versa
+R                    - switch current panel between local filesystem and DataPower
+                       (both panels can show DataPower to copy/diff between
+                       two appliances or domains)
 Return               - enter directory
 F2/2                 - refresh focused pane (reload files/dirs)
 F3/3                 - view current file, DataPower configuration, DataPower
@@ -46,6 +49,10 @@ F5/5                 - copy the selected (or current if none selected) directori
                      - if DataPower domain is selected create an export of the domain
                      - if DataPower configuration is selected create an export of
                        the whole appliance (SOMA only)
+                     - if local zip file is copied to the DataPower domain
+                       import the domain export (or restore the appliance backup
+                       if copied to the DataPower configuration - SOMA only),
+                       import preview (dry-run) is shown before the import
                      - in DataPower object configuration mode copy DataPower
                        object to file or copy file with proper object configuration
                        to DataPower object (XML/JSON, depending on REST/SOMA
@@ -54,21 +61,42 @@ F7/7                 - create directory
                      - create a new DataPower domain
 F8/8                 - create an empty file
                      - create a new DataPower configuration
+                     - in DataPower object configuration mode create a new
+                       DataPower object of any class (class is selected from all
+                       classes known to the appliance)
 F9/9                 - clone a current DataPower configuration under a new name
                      - clone a current DataPower object under new name
 DEL/x                - delete selected (or current if none selected) directories and files
                      - delete a DataPower configuration
+                     - delete a DataPower domain (domain name has to be entered
+                       to confirm deletion, domain can be exported before deletion)
                      - delete a DataPower object
 d                    - diff current files/directories
                        (should be "blocking" - see "Custom external commands" below)
                      - diff changes on modified DataPower object (SOMA only)
+                     - diff current DataPower objects if both panels are in
+                       DataPower object mode
+                     - diff all objects of DataPower domains if both panels
+                       show DataPower domains (objects which exist only in one
+                       domain or differ are listed and can be compared using diff)
+D                    - structural diff of current DataPower objects (or objects of
+                       DataPower domains) ignoring formatting and ordering -
+                       shows added, removed and changed object properties
 /                    - find string
 n                    - find next string
 N                    - find previous string
 f                    - filter visible items by a given string
 m                    - show all status messages saved in the history
+b                    - show background jobs (copy of directories, export, secure
+                       backup & policy of DataPower object run as jobs) - cancel,
+                       re-run or delete job, show job result or error
 .                    - enter a location (full path) for the local file system
-s                    - auto-synchronize selected directories (local to DataPower)
+s                    - start named sync session auto-synchronizing selected
+                       directories (local to DataPower, DataPower can be shown
+                       in any panel), more sessions can run at the same time
+C                    - show sync sessions (start/stop session, show results,
+                       resolve conflicts which stopped two-way synchronization
+                       using diff, delete session)
 S                    - save a running DataPower configuration
 B                    - create and copy a secure backup of the appliance
 e                    - run exec command on current or selected cfg file(s)
@@ -82,10 +110,11 @@ P                    - show DataPower policy for the current DataPower object
                        (can be used only on service, policy, matches, rules & actions)
                        exports the current DataPower object, analyzes it and
                        shows service, policy, matches, rules and actions for
-                       the object
+                       the object (in the background job, see b key)
 h                    - show help
 q                    - quit
 any-other-char       - show help (+ hex value of the key pressed visible in the status bar)
+Esc                  - cancel long running DataPower action (while progress dialog is shown)
 
 Navigational keys (except Left/Right can be used in combination with Shift for selections):
 PgUp  Up  PgDn
@@ -126,13 +155,5 @@ Some of the DataPower information is not available using REST interface.
 Some new features added are SOMA-only. For example, with REST you can't compare
 persisted DataPower object configuration to saved configuration.
 
-TODO:
-- add domain/appliance import?
-- add creation of new DataPower objects
-  (should be able to show/create all classes of objects, even ones without
-  object instances)
-- add deletion of a domain (with maybe 2 confirmation dialogs: are you sure?;
-  are you really sure?)
-
 (to show dpcmder usage help use "-h" flag instead of "-help" flag)
 `
@@ -2,11 +2,16 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/croz-ltd/dpcmder/config"
 	"github.com/croz-ltd/dpcmder/utils/logging"
+	"io"
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Side type is used for Left/Right constants.
@@ -21,6 +26,9 @@ const (
 // maxStatusCount - maximum number of statuses we keep in history
 const maxStatusCount = 1000
 
+// maxSyncResultCount - maximum number of results we keep for sync session
+const maxSyncResultCount = 100
+
 // ItemType is used for defining type of Item (or current "directory")
 type ItemType byte
 
@@ -113,8 +121,31 @@ type ItemDpObjectState struct {
 // ItemList is slice extended as a sortable list of Items (implements sort.Interface).
 type ItemList []Item
 
+// itemJSON is stable machine-readable (JSON) representation of Item.
+type itemJSON struct {
+	Name     string                 `json:"name"`
+	Type     string                 `json:"type"`
+	TypeName string                 `json:"typeName"`
+	Size     string                 `json:"size"`
+	Modified string                 `json:"modified"`
+	Path     string                 `json:"path,omitempty"`
+	State    *itemDpObjectStateJSON `json:"state,omitempty"`
+}
+
+// itemDpObjectStateJSON is stable machine-readable (JSON) representation of
+// ItemDpObjectState.
+type itemDpObjectStateJSON struct {
+	OpState     string `json:"opState"`
+	AdminState  string `json:"adminState"`
+	EventCode   string `json:"eventCode"`
+	ErrorCode   string `json:"errorCode"`
+	ConfigState string `json:"configState"`
+}
+
 // Model is a structure representing our dpcmder view of files,
 // both left-side DataPower view and right-side local filesystem view.
+// Model is changed only by the goroutine reading user input, background
+// goroutines post view updates to it (see events.UpdateViewEvent).
 type Model struct {
 	// viewConfig          [2]*ItemConfig
 	viewConfigHistory   [2][]*ItemConfig
@@ -129,14 +160,49 @@ type Model struct {
 	ItemMaxRows         int
 	HorizScroll         int
 	SearchBy            string
-	SyncModeOn          bool
-	SyncInitial         bool
-	SyncDpDomain        string
-	SyncDirDp           string
-	SyncDirLocal        string
+	syncSessions        map[string]*SyncSession
 	statuses            []string
 }
 
+// SyncSession contains configuration and state of one named sync session
+// (local directory synced to DataPower directory in its own goroutine). State
+// shared with the sync goroutine (on, lastSync, conflicts & results) is
+// accessed only using SyncSession methods.
+// PostSyncActions are copied from the configuration when session is started so
+// sync goroutine doesn't read the configuration changed by other goroutines.
+// Sync status messages are written to the Output if it is set (sync without
+// terminal user interface) instead of showing them in the status line. Each
+// run of the sync goroutine has its own stop channel (closed when session is
+// stopped) and done channel (closed when the goroutine ends).
+type SyncSession struct {
+	Name            string
+	DpAppliance     string
+	DpDomain        string
+	DirDp           string
+	DirLocal        string
+	PostSyncActions []config.PostSyncAction
+	Output          io.Writer
+	on              bool
+	lastSync        time.Time
+	conflicts       []SyncConflict
+	results         []string
+	stop            chan struct{}
+	done            chan struct{}
+	mutex           sync.Mutex
+}
+
+// SyncConflict contains info about the file which can't be synced
+// automatically in two-way sync (changed on both sides or changed on one side
+// and removed on the other).
+type SyncConflict struct {
+	PathFromRoot string
+	Reason       string
+}
+
+func (sc SyncConflict) String() string {
+	return fmt.Sprintf("%-22s %s", sc.Reason, sc.PathFromRoot)
+}
+
 // ViewMode represent one of available DataPower view modes.
 type DpViewMode byte
 
@@ -212,6 +278,24 @@ func (item Item) GetDisplayableType() string {
 	return string(item.Config.Type)
 }
 
+// MarshalJSON method returns stable JSON representation of Item used for
+// machine-readable output (implements json.Marshaler).
+func (item Item) MarshalJSON() ([]byte, error) {
+	result := itemJSON{Name: item.Name, Size: item.Size, Modified: item.Modified}
+	if item.Config != nil {
+		result.Type = item.Config.Type.String()
+		result.TypeName = item.Config.Type.UserFriendlyString()
+		result.Path = item.Config.Path
+		if item.Config.DpObjectState != (ItemDpObjectState{}) {
+			state := item.Config.DpObjectState
+			result.State = &itemDpObjectStateJSON{OpState: state.OpState,
+				AdminState: state.AdminState, EventCode: state.EventCode,
+				ErrorCode: state.ErrorCode, ConfigState: state.ConfigState}
+		}
+	}
+	return json.Marshal(result)
+}
+
 // ItemList methods (implements sort.Interface)
 
 // Len returns number of items in ItemList.
@@ -408,6 +492,17 @@ func (m *Model) NavCurrentViewForward(side Side) *ItemConfig {
 	return m.NavCurrentViewIdx(side, m.viewConfigCurrIdx[side]+1)
 }
 
+// ClearViewHistory removes all views and items for given Side - used when
+// different repository is shown on given Side.
+func (m *Model) ClearViewHistory(side Side) {
+	m.viewConfigHistory[side] = nil
+	m.viewConfigCurrIdx[side] = 0
+	m.title[side] = ""
+	m.currentFilter[side] = ""
+	m.currItemIdx[side] = 0
+	m.SetItems(side, nil)
+}
+
 // AddStatus adds new status event to history of statuses.
 func (m *Model) AddStatus(status string) {
 	m.statuses = append(m.statuses, status)
@@ -432,6 +527,171 @@ func (m *Model) Statuses() []string {
 	return m.statuses
 }
 
+// AddSyncSession adds sync session (replaces session with the same name).
+func (m *Model) AddSyncSession(session *SyncSession) {
+	if m.syncSessions == nil {
+		m.syncSessions = make(map[string]*SyncSession)
+	}
+	m.syncSessions[session.Name] = session
+}
+
+// DeleteSyncSession removes sync session with given name.
+func (m *Model) DeleteSyncSession(name string) {
+	delete(m.syncSessions, name)
+}
+
+// SyncSession returns sync session with given name (nil if not found).
+func (m *Model) SyncSession(name string) *SyncSession {
+	return m.syncSessions[name]
+}
+
+// SyncSessions returns all sync sessions sorted by name.
+func (m *Model) SyncSessions() []*SyncSession {
+	sessions := make([]*SyncSession, 0, len(m.syncSessions))
+	for _, session := range m.syncSessions {
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Name < sessions[j].Name })
+
+	return sessions
+}
+
+// SyncModeOn returns true if any sync session is running.
+func (m *Model) SyncModeOn() bool {
+	for _, session := range m.syncSessions {
+		if session.IsOn() {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns short description of the sync session.
+func (s *SyncSession) String() string {
+	return fmt.Sprintf("%s (%s:%s:'%s' <- '%s')", s.Name, s.DpAppliance, s.DpDomain, s.DirDp, s.DirLocal)
+}
+
+// State returns user friendly state of the sync session.
+func (s *SyncSession) State() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	switch {
+	case s.on:
+		return "running"
+	case len(s.conflicts) != 0:
+		return "conflicts"
+	default:
+		return "stopped"
+	}
+}
+
+// IsOn returns true if the sync session is running.
+func (s *SyncSession) IsOn() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.on
+}
+
+// Start marks the sync session as running and clears conflicts found before.
+// Returns channels of the new run - stop channel closed when the session is
+// stopped and done channel which sync goroutine should close when it ends.
+// Goroutine of the previous run must end (see Done) before session is started.
+func (s *SyncSession) Start() (<-chan struct{}, chan<- struct{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.on = true
+	s.conflicts = nil
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	return s.stop, s.done
+}
+
+// Stop marks the sync session as stopped (sync goroutine stops after the
+// current sync is done).
+func (s *SyncSession) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.stopRun()
+}
+
+// StopWithConflicts stops the sync session because of the conflicts found.
+func (s *SyncSession) StopWithConflicts(conflicts []SyncConflict) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.stopRun()
+	s.conflicts = conflicts
+}
+
+// Conflicts returns copy of the conflicts found by the two-way sync.
+func (s *SyncSession) Conflicts() []SyncConflict {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]SyncConflict{}, s.conflicts...)
+}
+
+// RemoveConflict removes resolved conflict at the given index.
+func (s *SyncSession) RemoveConflict(idx int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if idx < 0 || idx >= len(s.conflicts) {
+		return
+	}
+	s.conflicts = append(s.conflicts[:idx:idx], s.conflicts[idx+1:]...)
+}
+
+// stopRun marks the sync session as stopped and closes stop channel of the
+// current run, mutex should be locked by the caller.
+func (s *SyncSession) stopRun() {
+	if s.on && s.stop != nil {
+		close(s.stop)
+	}
+	s.on = false
+}
+
+// Done returns channel closed when goroutine of the last sync session run
+// ends (closed channel if session was never started).
+func (s *SyncSession) Done() <-chan struct{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.done == nil {
+		s.done = make(chan struct{})
+		close(s.done)
+	}
+	return s.done
+}
+
+// LastSyncTime returns time when the last sync was done.
+func (s *SyncSession) LastSyncTime() time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.lastSync
+}
+
+// SetLastSyncTime sets time when the last sync was done.
+func (s *SyncSession) SetLastSyncTime(lastSync time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.lastSync = lastSync
+}
+
+// AddResult adds new result to history of sync session results.
+func (s *SyncSession) AddResult(result string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.results = append(s.results, result)
+	overflowResultCount := len(s.results) - maxSyncResultCount
+	if overflowResultCount > 0 {
+		s.results = s.results[overflowResultCount:]
+	}
+}
+
+// Results returns history of sync session results.
+func (s *SyncSession) Results() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string{}, s.results...)
+}
+
 // IsCurrentSide returns true if given side is currently used.
 func (m *Model) IsCurrentSide(side Side) bool {
 	return side == m.currSide
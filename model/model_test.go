@@ -1,10 +1,12 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/croz-ltd/dpcmder/utils/assert"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // ItemType methods tests
@@ -109,6 +111,26 @@ func TestItemString(t *testing.T) {
 		"Item('master', '3000', '2019-02-06 14:06:10', true, IC(f, '' (), '' ()  IDOS(''/'', ''/'' ()) <nil>))")
 }
 
+func TestItemMarshalJSON(t *testing.T) {
+	item := Item{Config: &ItemConfig{Type: ItemFile, Path: "local:/dir/master"}, Name: "master", Size: "3000", Modified: "2019-02-06 14:06:10", Selected: true}
+	got, err := json.Marshal(item)
+	assert.Nil(t, "Item.MarshalJSON()", err)
+	assert.DeepEqual(t,
+		"Item.MarshalJSON()",
+		string(got),
+		`{"name":"master","type":"f","typeName":"file","size":"3000","modified":"2019-02-06 14:06:10","path":"local:/dir/master"}`)
+
+	item = Item{Config: &ItemConfig{Type: ItemDpObject,
+		DpObjectState: ItemDpObjectState{OpState: "up", AdminState: "enabled", ConfigState: "saved"}},
+		Name: "MyFirewall", Size: "", Modified: "saved"}
+	got, err = json.Marshal(item)
+	assert.Nil(t, "Item.MarshalJSON()", err)
+	assert.DeepEqual(t,
+		"Item.MarshalJSON()",
+		string(got),
+		`{"name":"MyFirewall","type":"o","typeName":"object","size":"","modified":"saved","state":{"opState":"up","adminState":"enabled","eventCode":"","errorCode":"","configState":"saved"}}`)
+}
+
 // ItemList methods tests
 
 func prepareItemList() ItemList {
@@ -308,6 +330,31 @@ func TestModelAddNextView(t *testing.T) {
 	checkViewConfig(Right, itemConfig2c, 1, 2)
 }
 
+func TestModelClearViewHistory(t *testing.T) {
+	model := Model{}
+	itemConfig1a := &ItemConfig{Path: "/path/1a"}
+	itemConfig1b := &ItemConfig{Path: "/path/1b"}
+	itemConfig2a := &ItemConfig{Path: "/path/2a"}
+	model.AddNextView(Left, itemConfig1a, "Left 1a")
+	model.AddNextView(Left, itemConfig1b, "Left 1b")
+	model.SetItems(Left, prepareItemList())
+	model.AddNextView(Right, itemConfig2a, "Right 2a")
+
+	model.ClearViewHistory(Left)
+	assert.Equals(t, "ViewConfigHistorySize(Left)", model.ViewConfigHistorySize(Left), 0)
+	assert.Equals(t, "ViewConfigHistorySelectedIdx(Left)", model.ViewConfigHistorySelectedIdx(Left), 0)
+	assert.Equals(t, "Title(Left)", model.Title(Left), "")
+	assert.Equals(t, "GetVisibleItemCount(Left)", model.GetVisibleItemCount(Left), 0)
+	assert.Equals(t, "ViewConfigHistorySize(Right)", model.ViewConfigHistorySize(Right), 1)
+	assert.Equals(t, "Title(Right)", model.Title(Right), "Right 2a")
+
+	model.AddNextView(Left, itemConfig1b, "Left 1b")
+	assert.Equals(t, "ViewConfigHistorySize(Left)", model.ViewConfigHistorySize(Left), 1)
+	if model.ViewConfig(Left) != itemConfig1b {
+		t.Errorf("Model ViewConfig(Left) should be '%s' but is '%s'.", itemConfig1b, model.ViewConfig(Left))
+	}
+}
+
 func TestModelNavCurrentViewBackFw(t *testing.T) {
 	model := Model{}
 
@@ -882,3 +929,106 @@ func TestModelStatusHandling(t *testing.T) {
 	assert.DeepEqual(t, "LastStatus()", fmt.Sprintf("Status event new no %d", maxStatusCount-1), model.LastStatus())
 	assert.DeepEqual(t, "Statuses() size", maxStatusCount, len(model.Statuses()))
 }
+
+func TestModelSyncSessions(t *testing.T) {
+	model := Model{}
+	assert.Equals(t, "SyncModeOn()", model.SyncModeOn(), false)
+	assert.Equals(t, "SyncSessions()", len(model.SyncSessions()), 0)
+
+	xslSession := &SyncSession{Name: "xsl", DpAppliance: "MyDp", DpDomain: "dev", DirDp: "local:/xsl", DirLocal: "/src/xsl"}
+	model.AddSyncSession(&SyncSession{Name: "gws"})
+	model.AddSyncSession(xslSession)
+	assert.Equals(t, "SyncSession()", model.SyncSession("xsl"), xslSession)
+	assert.Equals(t, "SyncSessions()", model.SyncSessions()[0].Name, "gws")
+	assert.Equals(t, "SyncSessions()", model.SyncSessions()[1], xslSession)
+	assert.Equals(t, "String()", xslSession.String(), "xsl (MyDp:dev:'local:/xsl' <- '/src/xsl')")
+	assert.Equals(t, "State()", xslSession.State(), "stopped")
+
+	xslSession.Start()
+	assert.Equals(t, "SyncModeOn()", model.SyncModeOn(), true)
+	assert.Equals(t, "State()", xslSession.State(), "running")
+	xslSession.StopWithConflicts([]SyncConflict{{PathFromRoot: "a.xsl", Reason: "changed on both sides"},
+		{PathFromRoot: "b.xsl", Reason: "deleted locally"}})
+	assert.Equals(t, "State()", xslSession.State(), "conflicts")
+	assert.Equals(t, "SyncConflict.String()", xslSession.Conflicts()[0].String(), "changed on both sides  a.xsl")
+	xslSession.Conflicts()[0].Reason = "changed"
+	xslSession.RemoveConflict(2)
+	assert.Equals(t, "Conflicts() size", len(xslSession.Conflicts()), 2)
+	xslSession.RemoveConflict(0)
+	assert.DeepEqual(t, "RemoveConflict()", xslSession.Conflicts(),
+		[]SyncConflict{{PathFromRoot: "b.xsl", Reason: "deleted locally"}})
+	xslSession.RemoveConflict(0)
+	assert.Equals(t, "State()", xslSession.State(), "stopped")
+
+	for index := 0; index < maxSyncResultCount+5; index++ {
+		xslSession.AddResult(fmt.Sprintf("Result no %d", index))
+	}
+	assert.Equals(t, "Results() size", len(xslSession.Results()), maxSyncResultCount)
+	assert.Equals(t, "Results()", xslSession.Results()[0], "Result no 5")
+
+	model.DeleteSyncSession("xsl")
+	assert.Equals(t, "SyncModeOn()", model.SyncModeOn(), false)
+	assert.True(t, "SyncSession()", model.SyncSession("xsl") == nil)
+}
+
+func TestSyncSessionConcurrentAccess(t *testing.T) {
+	model := Model{}
+	session := &SyncSession{Name: "xsl"}
+	model.AddSyncSession(session)
+	session.Start()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for index := 0; index < 100; index++ {
+			session.AddResult(fmt.Sprintf("Result no %d", index))
+			session.SetLastSyncTime(time.Now())
+		}
+		session.StopWithConflicts([]SyncConflict{{PathFromRoot: "a.xsl", Reason: "changed on both sides"}})
+	}()
+	for session.IsOn() {
+		model.SyncModeOn()
+		session.State()
+		session.Results()
+		session.LastSyncTime()
+	}
+	<-done
+
+	assert.Equals(t, "State()", session.State(), "conflicts")
+	assert.Equals(t, "Results() size", len(session.Results()), maxSyncResultCount)
+	assert.False(t, "LastSyncTime()", session.LastSyncTime().IsZero())
+	session.Start()
+	assert.Equals(t, "State()", session.State(), "running")
+	session.Stop()
+	assert.Equals(t, "State()", session.State(), "stopped")
+}
+
+func TestSyncSessionStopStart(t *testing.T) {
+	session := &SyncSession{Name: "xsl"}
+	select {
+	case <-session.Done():
+	default:
+		t.Errorf("Done() of the session never started should be closed.")
+	}
+
+	// Runs waiting for the previous run to end don't overlap (checked by -race).
+	var runCount int
+	run := func(stop <-chan struct{}, done chan<- struct{}) {
+		defer close(done)
+		runCount++
+		<-stop
+		time.Sleep(time.Millisecond)
+	}
+	for index := 0; index < 10; index++ {
+		<-session.Done()
+		stop, done := session.Start()
+		go run(stop, done)
+		assert.Equals(t, "IsOn()", session.IsOn(), true)
+		session.Stop()
+		session.Stop()
+	}
+	<-session.Done()
+
+	assert.Equals(t, "State()", session.State(), "stopped")
+	assert.Equals(t, "runCount", runCount, 10)
+}
@@ -0,0 +1,107 @@
+package dp
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/croz-ltd/dpcmder/config"
+	"github.com/croz-ltd/dpcmder/utils/errs"
+	"github.com/croz-ltd/dpcmder/utils/logging"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// maxIdleConnsPerAppliance is maximum number of idle (keep-alive) connections
+// kept open to one appliance.
+const maxIdleConnsPerAppliance = 8
+
+// httpClientKey contains appliance configuration used to create HTTP client.
+type httpClientKey struct {
+	appliance         string
+	proxy             string
+	noProxy           string
+	tlsVerify         bool
+	tlsCAFile         string
+	tlsFingerprint    string
+	tlsClientCertFile string
+	tlsClientKeyFile  string
+	connectTimeout    time.Duration
+	readTimeout       time.Duration
+}
+
+// httpClients contains HTTP clients (with pooled connections) created for
+// appliances, client is created again when appliance configuration changes.
+var httpClients = make(map[httpClientKey]*http.Client)
+var httpClientsMutex sync.Mutex
+
+// httpClient returns HTTP client used to connect to the appliance.
+func httpClient(dpa dpApplicance) (*http.Client, error) {
+	key := httpClientKey{appliance: dpa.name, proxy: dpa.Proxy, noProxy: dpa.NoProxy,
+		tlsVerify: dpa.TLSVerify, tlsCAFile: dpa.TLSCAFile, tlsFingerprint: dpa.TLSFingerprint,
+		tlsClientCertFile: dpa.TLSClientCertFile, tlsClientKeyFile: dpa.TLSClientKeyFile,
+		connectTimeout: seconds(config.Conf.Net.ConnectSeconds),
+		readTimeout:    seconds(config.Conf.Net.ReadSeconds)}
+	httpClientsMutex.Lock()
+	defer httpClientsMutex.Unlock()
+	if client, found := httpClients[key]; found {
+		return client, nil
+	}
+
+	client, err := newHTTPClient(dpa, key.connectTimeout, key.readTimeout)
+	if err != nil {
+		return nil, err
+	}
+	httpClients[key] = client
+
+	return client, nil
+}
+
+// newHTTPClient creates HTTP client used to connect to the appliance (zero
+// timeout means no limit).
+func newHTTPClient(dpa dpApplicance, connectTimeout, readTimeout time.Duration) (*http.Client, error) {
+	logging.LogDebugf("repo/dp/newHTTPClient('%s')", dpa.name)
+	tlsConf, err := tlsConfig(dpa)
+	if err != nil {
+		return nil, err
+	}
+	proxy, err := proxyFunc(dpa)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	transport.TLSClientConfig = tlsConf
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerAppliance
+	transport.DialContext = (&net.Dialer{Timeout: connectTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+	transport.ResponseHeaderTimeout = readTimeout
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// seconds converts number of seconds from configuration to duration.
+func seconds(value int) time.Duration {
+	return time.Duration(value) * time.Second
+}
+
+// proxyFunc returns function selecting proxy used for the request - proxy from
+// the appliance configuration if it is set, otherwise proxy from environment
+// variables (HTTP_PROXY, HTTPS_PROXY & NO_PROXY).
+func proxyFunc(dpa dpApplicance) (func(*http.Request) (*url.URL, error), error) {
+	if dpa.Proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	if _, err := url.Parse(dpa.Proxy); err != nil {
+		logging.LogDebug("repo/dp/proxyFunc() - Can't parse proxy URL: ", err)
+		return nil, errs.Errorf("Wrong proxy URL for DataPower appliance '%s': %v", dpa.name, err)
+	}
+	proxyConfig := httpproxy.Config{HTTPProxy: dpa.Proxy, HTTPSProxy: dpa.Proxy, NoProxy: dpa.NoProxy}
+	proxyForURL := proxyConfig.ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyForURL(req.URL)
+	}, nil
+}
@@ -4,9 +4,11 @@ package dp
 import (
 	"archive/zip"
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -15,6 +17,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/antchfx/jsonquery"
@@ -22,6 +25,7 @@ import (
 	"github.com/clbanning/mxj/v2"
 	"github.com/croz-ltd/dpcmder/config"
 	"github.com/croz-ltd/dpcmder/model"
+	"github.com/croz-ltd/dpcmder/repo"
 	"github.com/croz-ltd/dpcmder/utils/errs"
 	"github.com/croz-ltd/dpcmder/utils/logging"
 	"github.com/croz-ltd/dpcmder/utils/paths"
@@ -47,15 +51,63 @@ type dpRepo struct {
 }
 
 // Repo is instance or DataPower repo/Repo interface implementation used for all
-// operations on DataPower except syncing local filesystem to DataPower.
+// operations on DataPower shown in the left panel (and non-interactive
+// commands) except syncing local filesystem to DataPower.
 var Repo = dpRepo{name: "DataPower", dpFilestoreXmls: make(map[string]string),
 	DpViewMode: model.DpFilestoreMode, req: netRequester{}}
 
-// SyncRepo is instance or DataPower repo/Repo interface implementation used for
-// syncing local directory to DataPower directory.
-var SyncRepo = dpRepo{name: "SyncDataPower", dpFilestoreXmls: make(map[string]string),
+// RightRepo is instance or DataPower repo/Repo interface implementation used
+// for all operations on DataPower shown in the right panel.
+var RightRepo = dpRepo{name: "DataPower", dpFilestoreXmls: make(map[string]string),
 	DpViewMode: model.DpFilestoreMode, req: netRequester{}}
 
+// Repos contains DataPower repo instances used for the left and right panel.
+var Repos = []*dpRepo{model.Left: &Repo, model.Right: &RightRepo}
+
+// syncRepos contains DataPower repo instances used by sync sessions.
+var syncRepos = make(map[string]*dpRepo)
+var syncReposMutex sync.Mutex
+
+// SyncRepo returns DataPower repo/Repo interface implementation instance used
+// for syncing local directory to DataPower directory in the named sync session
+// (instance is created on first use).
+func SyncRepo(sessionName string) *dpRepo {
+	syncReposMutex.Lock()
+	defer syncReposMutex.Unlock()
+	syncRepo, found := syncRepos[sessionName]
+	if !found {
+		syncRepo = &dpRepo{name: "SyncDataPower", dpFilestoreXmls: make(map[string]string),
+			DpViewMode: model.DpFilestoreMode, req: netRequester{}}
+		syncRepos[sessionName] = syncRepo
+	}
+
+	return syncRepo
+}
+
+// DeleteSyncRepo removes DataPower repo instance used by the named sync session.
+func DeleteSyncRepo(sessionName string) {
+	syncReposMutex.Lock()
+	defer syncReposMutex.Unlock()
+	delete(syncRepos, sessionName)
+}
+
+// Copy returns new DataPower repo instance (with empty cache) connected to the
+// same appliance and using the same view mode, used by background jobs so
+// they don't depend on the appliance currently shown in the panel.
+func (r *dpRepo) Copy() *dpRepo {
+	return &dpRepo{name: r.name, dpFilestoreXmls: make(map[string]string),
+		dataPowerAppliance: r.dataPowerAppliance, DpViewMode: r.DpViewMode, req: r.req}
+}
+
+// CopyRepo returns copy of the DataPower repo (see Copy) which can be used by
+// another goroutine, other repos are stateless and returned unchanged.
+func CopyRepo(r repo.Repo) repo.Repo {
+	if dpr, ok := r.(*dpRepo); ok {
+		return dpr.Copy()
+	}
+	return r
+}
+
 // dpDomainInfo contains domain name and basic state
 type dpDomainInfo struct {
 	name       string
@@ -63,6 +115,87 @@ type dpDomainInfo struct {
 	down       bool
 }
 
+// ImportResult contains result of import for one DataPower object or file.
+type ImportResult struct {
+	Type   string
+	Class  string
+	Name   string
+	Status string
+}
+
+func (ir ImportResult) String() string {
+	if ir.Class != "" {
+		return fmt.Sprintf("%-6s %-9s %s (%s)", ir.Type, ir.Status, ir.Name, ir.Class)
+	}
+	return fmt.Sprintf("%-6s %-9s %s", ir.Type, ir.Status, ir.Name)
+}
+
+// SyncFile contains info about one file from the synced DataPower directory
+// hierarchy (PathFromRoot is path relative to the synced directory using "/"
+// as separator).
+type SyncFile struct {
+	PathFromRoot string
+	Path         string
+	Size         string
+	Modified     string
+}
+
+// DomainObject contains configuration of one DataPower object from a domain.
+type DomainObject struct {
+	Class  string
+	Name   string
+	Config []byte
+}
+
+// ObjectDiff contains result of comparison of one DataPower object between
+// two domains (Left or Right configuration is nil if object doesn't exist).
+type ObjectDiff struct {
+	Class  string
+	Name   string
+	Status string
+	Left   []byte
+	Right  []byte
+}
+
+func (od ObjectDiff) String() string {
+	return fmt.Sprintf("%-10s %s (%s)", od.Status, od.Name, od.Class)
+}
+
+// Statuses of the DataPower object comparison between two domains.
+const (
+	ObjectDiffOnlyLeft  = "only-left"
+	ObjectDiffOnlyRight = "only-right"
+	ObjectDiffChanged   = "changed"
+)
+
+// PropertyDiff contains one difference found by structural comparison of two
+// DataPower object configurations - Path is the property path (attributes are
+// prefixed with "@", list entries are suffixed with "[]").
+type PropertyDiff struct {
+	Path     string `json:"path"`
+	Status   string `json:"status"`
+	OldValue string `json:"oldValue,omitempty"`
+	NewValue string `json:"newValue,omitempty"`
+}
+
+func (pd PropertyDiff) String() string {
+	switch pd.Status {
+	case PropertyAdded:
+		return fmt.Sprintf("+ %s: %s", pd.Path, pd.NewValue)
+	case PropertyRemoved:
+		return fmt.Sprintf("- %s: %s", pd.Path, pd.OldValue)
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", pd.Path, pd.OldValue, pd.NewValue)
+	}
+}
+
+// Statuses of the property found by structural comparison of DataPower objects.
+const (
+	PropertyAdded   = "added"
+	PropertyRemoved = "removed"
+	PropertyChanged = "changed"
+)
+
 // Constants from xml-mgmt.xsd (dmConfigState type), only used ones.
 const (
 	objectStatusSaved    = "saved"
@@ -131,7 +264,7 @@ func getDpAppliance(itemToShow *model.ItemConfig) dpApplicance {
 		model.ItemDirectory:
 		dataPowerAppliance := config.Conf.DataPowerAppliances[itemToShow.DpAppliance]
 		if dataPowerAppliance.Password == "" {
-			dataPowerAppliance.SetDpPlaintextPassword(config.DpTransientPasswordMap[itemToShow.DpAppliance])
+			dataPowerAppliance.SetDpPlaintextPassword(config.DpTransientPassword(itemToShow.DpAppliance))
 		}
 		return dpApplicance{name: itemToShow.DpAppliance,
 			DataPowerAppliance: dataPowerAppliance}
@@ -141,7 +274,7 @@ func getDpAppliance(itemToShow *model.ItemConfig) dpApplicance {
 		return dpApplicance{}
 	}
 }
-func (r *dpRepo) GetList(itemToShow *model.ItemConfig) (model.ItemList, error) {
+func (r *dpRepo) GetList(ctx context.Context, itemToShow *model.ItemConfig) (model.ItemList, error) {
 	logging.LogDebugf("repo/dp/GetList(%v), r.DpViewMode: %s", itemToShow, r.DpViewMode)
 
 	switch r.DpViewMode {
@@ -159,10 +292,10 @@ func (r *dpRepo) GetList(itemToShow *model.ItemConfig) (model.ItemList, error) {
 		switch itemToShow.Type {
 		case model.ItemDpObjectClassList:
 			r.dataPowerAppliance = getDpAppliance(itemToShow)
-			return r.listObjectClasses(itemToShow)
+			return r.listObjectClasses(ctx, itemToShow)
 		case model.ItemDpObjectClass:
 			r.dataPowerAppliance = getDpAppliance(itemToShow)
-			return r.listObjects(itemToShow)
+			return r.listObjects(ctx, itemToShow)
 		default:
 			logging.LogDebugf("repo/dp/GetList(%v) - can't get children or item for DpViewMode: %s.",
 				itemToShow, r.DpViewMode)
@@ -183,10 +316,10 @@ func (r *dpRepo) GetList(itemToShow *model.ItemConfig) (model.ItemList, error) {
 		switch itemToShow.Type {
 		case model.ItemDpStatusClassList:
 			r.dataPowerAppliance = getDpAppliance(itemToShow)
-			return r.listStatusClasses(itemToShow)
+			return r.listStatusClasses(ctx, itemToShow)
 		case model.ItemDpStatusClass:
 			r.dataPowerAppliance = getDpAppliance(itemToShow)
-			return r.listStatuses(itemToShow)
+			return r.listStatuses(ctx, itemToShow)
 		default:
 			wrongView := r.DpViewMode
 			logging.LogDebugf("repo/dp/GetList(%v) - can't get children or item for DpViewMode: %s.",
@@ -205,18 +338,18 @@ func (r *dpRepo) GetList(itemToShow *model.ItemConfig) (model.ItemList, error) {
 		case model.ItemDpConfiguration:
 			r.dataPowerAppliance = getDpAppliance(itemToShow)
 			if itemToShow.DpDomain != "" {
-				return r.listFilestores(itemToShow)
+				return r.listFilestores(ctx, itemToShow)
 			}
-			return r.listDomains(itemToShow)
+			return r.listDomains(ctx, itemToShow)
 		case model.ItemDpDomain:
 			r.dataPowerAppliance = getDpAppliance(itemToShow)
-			return r.listFilestores(itemToShow)
+			return r.listFilestores(ctx, itemToShow)
 		case model.ItemDpFilestore:
 			r.dataPowerAppliance = getDpAppliance(itemToShow)
-			return r.listDpDir(itemToShow)
+			return r.listDpDir(ctx, itemToShow)
 		case model.ItemDirectory:
 			r.dataPowerAppliance = getDpAppliance(itemToShow)
-			return r.listDpDir(itemToShow)
+			return r.listDpDir(ctx, itemToShow)
 		default:
 			logging.LogDebugf("repo/dp/GetList(%v) - can't get children or item for DpViewMode: %s.",
 				itemToShow, r.DpViewMode)
@@ -234,24 +367,24 @@ func (r *dpRepo) InvalidateCache() {
 	}
 }
 
-func (r *dpRepo) GetFile(currentView *model.ItemConfig, fileName string) ([]byte, error) {
+func (r *dpRepo) GetFile(ctx context.Context, currentView *model.ItemConfig, fileName string) ([]byte, error) {
 	logging.LogDebugf("repo/dp/GetFile(%v, '%s')", currentView, fileName)
 	parentPath := currentView.Path
 	filePath := paths.GetDpPath(parentPath, fileName)
 	r.dataPowerAppliance = getDpAppliance(currentView)
 
-	return r.GetFileByPath(currentView.DpDomain, filePath)
+	return r.GetFileByPath(ctx, currentView.DpDomain, filePath)
 }
 
 // GetFileByPath fetches file from DataPower by it's domain and path.
-func (r *dpRepo) GetFileByPath(dpDomain, filePath string) ([]byte, error) {
+func (r *dpRepo) GetFileByPath(ctx context.Context, dpDomain, filePath string) ([]byte, error) {
 	logging.LogDebugf("repo/dp/GetFile('%s', '%s')", dpDomain, filePath)
 
 	switch r.dataPowerAppliance.DpManagmentInterface() {
 	case config.DpInterfaceRest:
 		restPath := makeRestPath(dpDomain, filePath)
 
-		fileB64, _, err := r.restGetForOneResult(restPath, "/file")
+		fileB64, _, err := r.restGetForOneResult(ctx, restPath, "/file")
 		if err != nil {
 			return nil, err
 		}
@@ -271,7 +404,7 @@ func (r *dpRepo) GetFileByPath(dpDomain, filePath string) ([]byte, error) {
 		</man:request>
 	</soapenv:Body>
 </soapenv:Envelope>`, dpDomain, filePath)
-		somaResponse, err := r.soma(somaRequest)
+		somaResponse, err := r.soma(ctx, somaRequest)
 		if err != nil {
 			return nil, err
 		}
@@ -301,16 +434,16 @@ func (r *dpRepo) GetFileByPath(dpDomain, filePath string) ([]byte, error) {
 	}
 }
 
-func (r *dpRepo) UpdateFile(currentView *model.ItemConfig, fileName string, newFileContent []byte) (bool, error) {
+func (r *dpRepo) UpdateFile(ctx context.Context, currentView *model.ItemConfig, fileName string, newFileContent []byte) (bool, error) {
 	logging.LogDebugf("repo/dp/UpdateFile(%s, '%s', ...)\n", currentView, fileName)
 	parentPath := currentView.Path
 	filePath := paths.GetDpPath(parentPath, fileName)
 	r.dataPowerAppliance = getDpAppliance(currentView)
-	return r.UpdateFileByPath(currentView.DpDomain, filePath, newFileContent)
+	return r.UpdateFileByPath(ctx, currentView.DpDomain, filePath, newFileContent)
 }
-func (r *dpRepo) UpdateFileByPath(dpDomain, filePath string, newFileContent []byte) (bool, error) {
+func (r *dpRepo) UpdateFileByPath(ctx context.Context, dpDomain, filePath string, newFileContent []byte) (bool, error) {
 	logging.LogDebugf("repo/dp/UpdateFileByPath('%s', '%s', ...)", dpDomain, filePath)
-	fileType, err := r.GetFileTypeByPath(dpDomain, filePath, ".")
+	fileType, err := r.GetFileTypeByPath(ctx, dpDomain, filePath, ".")
 	logging.LogDebugf("repo/dp/UpdateFileByPath() fileType: %s", fileType)
 	if err != nil {
 		return false, err
@@ -341,7 +474,7 @@ func (r *dpRepo) UpdateFileByPath(dpDomain, filePath string, newFileContent []by
 		requestBody := "{\"file\":{\"name\":\"" + fileName + "\",\"content\":\"" + base64.StdEncoding.EncodeToString(newFileContent) + "\"}}"
 
 		restPath := makeRestPath(dpDomain, updateFilePath)
-		jsonString, err := r.rest(restPath, restMethod, requestBody)
+		jsonString, err := r.rest(ctx, restPath, restMethod, requestBody)
 		if err != nil {
 			return false, err
 		}
@@ -370,7 +503,7 @@ func (r *dpRepo) UpdateFileByPath(dpDomain, filePath string, newFileContent []by
 		</man:request>
 	</soapenv:Body>
 </soapenv:Envelope>`, dpDomain, filePath, base64.StdEncoding.EncodeToString(newFileContent))
-			somaResponse, err := r.soma(somaRequest)
+			somaResponse, err := r.soma(ctx, somaRequest)
 			if err != nil {
 				return false, err
 			}
@@ -380,7 +513,7 @@ func (r *dpRepo) UpdateFileByPath(dpDomain, filePath string, newFileContent []by
 				return false, err
 			}
 			parentPath := paths.GetDpPath(filePath, "..")
-			err = r.refreshSomaFilesByPath(dpDomain, parentPath)
+			err = r.refreshSomaFilesByPath(ctx, dpDomain, parentPath)
 			if err != nil {
 				logging.LogDebugf("repo/dp/UpdateFileByPath() - Error refresing soma files by path '%s': err: %v", parentPath, err)
 				return false, err
@@ -413,22 +546,22 @@ func (r *dpRepo) UpdateFileByPath(dpDomain, filePath string, newFileContent []by
 	}
 }
 
-func (r *dpRepo) GetFileType(viewConfig *model.ItemConfig, parentPath, fileName string) (model.ItemType, error) {
+func (r *dpRepo) GetFileType(ctx context.Context, viewConfig *model.ItemConfig, parentPath, fileName string) (model.ItemType, error) {
 	logging.LogDebug(fmt.Sprintf("repo/dp/getFileType(%v, '%s', '%s')\n", viewConfig, parentPath, fileName))
 	dpDomain := viewConfig.DpDomain
 	r.dataPowerAppliance = getDpAppliance(viewConfig)
 
-	return r.GetFileTypeByPath(dpDomain, parentPath, fileName)
+	return r.GetFileTypeByPath(ctx, dpDomain, parentPath, fileName)
 }
 
-func (r *dpRepo) GetFileTypeByPath(dpDomain, parentPath, fileName string) (model.ItemType, error) {
+func (r *dpRepo) GetFileTypeByPath(ctx context.Context, dpDomain, parentPath, fileName string) (model.ItemType, error) {
 	logging.LogDebug(fmt.Sprintf("repo/dp/GetFileTypeByPath('%s', '%s', '%s')\n", dpDomain, parentPath, fileName))
 	filePath := paths.GetDpPath(parentPath, fileName)
 
 	switch r.dataPowerAppliance.DpManagmentInterface() {
 	case config.DpInterfaceRest:
 		restPath := makeRestPath(dpDomain, filePath)
-		jsonString, err := r.restGet(restPath)
+		jsonString, err := r.restGet(ctx, restPath)
 		if err != nil {
 			unexErr, ok := err.(errs.UnexpectedHTTPResponse)
 			if ok && unexErr.StatusCode == 404 {
@@ -521,13 +654,13 @@ func (r *dpRepo) GetFilePath(parentPath, fileName string) string {
 	return paths.GetDpPath(parentPath, fileName)
 }
 
-func (r *dpRepo) CreateDir(viewConfig *model.ItemConfig, parentPath, dirName string) (bool, error) {
+func (r *dpRepo) CreateDir(ctx context.Context, viewConfig *model.ItemConfig, parentPath, dirName string) (bool, error) {
 	logging.LogDebugf("repo/dp/CreateDir(%v, '%s', '%s')", viewConfig, parentPath, dirName)
-	return r.CreateDirByPath(viewConfig.DpDomain, parentPath, dirName)
+	return r.CreateDirByPath(ctx, viewConfig.DpDomain, parentPath, dirName)
 }
-func (r *dpRepo) CreateDirByPath(dpDomain, parentPath, dirName string) (bool, error) {
+func (r *dpRepo) CreateDirByPath(ctx context.Context, dpDomain, parentPath, dirName string) (bool, error) {
 	logging.LogDebugf("repo/dp/CreateDirByPath('%s', '%s', '%s')", dpDomain, parentPath, dirName)
-	fileType, err := r.GetFileTypeByPath(dpDomain, parentPath, dirName)
+	fileType, err := r.GetFileTypeByPath(ctx, dpDomain, parentPath, dirName)
 	if err != nil {
 		return false, err
 	}
@@ -538,7 +671,7 @@ func (r *dpRepo) CreateDirByPath(dpDomain, parentPath, dirName string) (bool, er
 		case config.DpInterfaceRest:
 			requestBody := "{\"directory\":{\"name\":\"" + dirName + "\"}}"
 			restPath := makeRestPath(dpDomain, parentPath)
-			jsonString, err := r.rest(restPath, "POST", requestBody)
+			jsonString, err := r.rest(ctx, restPath, "POST", requestBody)
 			if err != nil {
 				return false, err
 			}
@@ -566,7 +699,7 @@ func (r *dpRepo) CreateDirByPath(dpDomain, parentPath, dirName string) (bool, er
 		</man:request>
 	</soapenv:Body>
 </soapenv:Envelope>`, dpDomain, dirPath)
-			somaResponse, err := r.soma(somaRequest)
+			somaResponse, err := r.soma(ctx, somaRequest)
 			if err != nil {
 				return false, err
 			}
@@ -575,7 +708,7 @@ func (r *dpRepo) CreateDirByPath(dpDomain, parentPath, dirName string) (bool, er
 				logging.LogDebug("Error parsing response SOAP.", err)
 				return false, err
 			}
-			r.refreshSomaFilesByPath(dpDomain, dirPath)
+			r.refreshSomaFilesByPath(ctx, dpDomain, dirPath)
 			resultNode := xmlquery.FindOne(doc, "//*[local-name()='response']/*[local-name()='result']")
 			if resultNode != nil {
 				resultText := strings.Trim(resultNode.InnerText(), " \n\r\t")
@@ -599,7 +732,44 @@ func (r *dpRepo) CreateDirByPath(dpDomain, parentPath, dirName string) (bool, er
 	}
 }
 
-func (r *dpRepo) Delete(currentView *model.ItemConfig, itemType model.ItemType, parentPath, fileName string) (bool, error) {
+// ListFilesRecursive lists all files from the DataPower directory hierarchy
+// (with their size and modification time).
+func (r *dpRepo) ListFilesRecursive(ctx context.Context, dpDomain, dirPath string) ([]SyncFile, error) {
+	logging.LogDebugf("repo/dp/ListFilesRecursive('%s', '%s')", dpDomain, dirPath)
+	r.InvalidateCache()
+	return r.listFilesRecursive(ctx, &model.ItemConfig{Type: model.ItemDirectory,
+		DpAppliance: r.dataPowerAppliance.name, DpDomain: dpDomain, Path: dirPath}, "")
+}
+
+func (r *dpRepo) listFilesRecursive(ctx context.Context, dirConfig *model.ItemConfig, dirPathFromRoot string) ([]SyncFile, error) {
+	items, err := r.listFiles(ctx, dirConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	syncFiles := make([]SyncFile, 0)
+	for _, item := range items {
+		pathFromRoot := item.Name
+		if dirPathFromRoot != "" {
+			pathFromRoot = dirPathFromRoot + "/" + item.Name
+		}
+		switch item.Config.Type {
+		case model.ItemDirectory:
+			dirFiles, err := r.listFilesRecursive(ctx, item.Config, pathFromRoot)
+			if err != nil {
+				return nil, err
+			}
+			syncFiles = append(syncFiles, dirFiles...)
+		case model.ItemFile:
+			syncFiles = append(syncFiles, SyncFile{PathFromRoot: pathFromRoot,
+				Path: item.Config.Path, Size: item.Size, Modified: item.Modified})
+		}
+	}
+
+	return syncFiles, nil
+}
+
+func (r *dpRepo) Delete(ctx context.Context, currentView *model.ItemConfig, itemType model.ItemType, parentPath, fileName string) (bool, error) {
 	logging.LogDebugf("repo/dp/Delete(%v, '%s', '%s' (%s))", currentView, parentPath, fileName, itemType)
 
 	switch itemType {
@@ -613,7 +783,7 @@ func (r *dpRepo) Delete(currentView *model.ItemConfig, itemType model.ItemType,
 		switch r.dataPowerAppliance.DpManagmentInterface() {
 		case config.DpInterfaceRest:
 			restPath := makeRestPath(currentView.DpDomain, filePath)
-			jsonString, err := r.rest(restPath, "DELETE", "")
+			jsonString, err := r.rest(ctx, restPath, "DELETE", "")
 			if err != nil {
 				return false, err
 			}
@@ -630,7 +800,7 @@ func (r *dpRepo) Delete(currentView *model.ItemConfig, itemType model.ItemType,
 				return true, nil
 			}
 		case config.DpInterfaceSoma:
-			fileType, err := r.GetFileType(currentView, parentPath, fileName)
+			fileType, err := r.GetFileType(ctx, currentView, parentPath, fileName)
 			if err != nil {
 				return false, err
 			}
@@ -653,7 +823,7 @@ func (r *dpRepo) Delete(currentView *model.ItemConfig, itemType model.ItemType,
 	</soapenv:Body>
 </soapenv:Envelope>`, currentView.DpDomain, filePath)
 			}
-			somaResponse, err := r.soma(somaRequest)
+			somaResponse, err := r.soma(ctx, somaRequest)
 			if err != nil {
 				return false, err
 			}
@@ -662,7 +832,7 @@ func (r *dpRepo) Delete(currentView *model.ItemConfig, itemType model.ItemType,
 				logging.LogDebug("Error parsing response SOAP.", err)
 				return false, err
 			}
-			r.refreshSomaFiles(currentView)
+			r.refreshSomaFiles(ctx, currentView)
 			resultNode := xmlquery.FindOne(doc, "//*[local-name()='response']/*[local-name()='result']")
 			if resultNode != nil {
 				resultText := strings.Trim(resultNode.InnerText(), " \n\r\t")
@@ -679,7 +849,7 @@ func (r *dpRepo) Delete(currentView *model.ItemConfig, itemType model.ItemType,
 		case config.DpInterfaceRest:
 			restPath := fmt.Sprintf("/mgmt/config/%s/%s/%s", currentView.DpDomain, parentPath, fileName)
 			logging.LogDebugf("repo/dp/Delete(), restPath: '%s'", restPath)
-			jsonString, err := r.rest(restPath, "DELETE", "")
+			jsonString, err := r.rest(ctx, restPath, "DELETE", "")
 			if err != nil {
 				return false, err
 			}
@@ -700,7 +870,7 @@ func (r *dpRepo) Delete(currentView *model.ItemConfig, itemType model.ItemType,
 	</soapenv:Body>
 </soapenv:Envelope>`,
 				currentView.DpDomain, parentPath, fileName)
-			somaResponse, err := r.soma(somaRequest)
+			somaResponse, err := r.soma(ctx, somaRequest)
 			if err != nil {
 				return false, err
 			}
@@ -762,7 +932,7 @@ func (r *dpRepo) GetViewConfigByPath(currentView *model.ItemConfig, dirPath stri
 
 // ExportAppliance creates export of whole DataPower appliance and returns
 // base64 encoded exported zip file.
-func (r *dpRepo) ExportAppliance(applianceConfigName, exportFileName string) ([]byte, error) {
+func (r *dpRepo) ExportAppliance(ctx context.Context, applianceConfigName, exportFileName string) ([]byte, error) {
 	logging.LogDebugf("repo/dp/ExportAppliance('%s', '%s')", applianceConfigName, exportFileName)
 
 	// 0. Prepare DataPower connection configuration.
@@ -775,7 +945,7 @@ func (r *dpRepo) ExportAppliance(applianceConfigName, exportFileName string) ([]
 	}
 	defer clearCurrentConfig()
 	if r.dataPowerAppliance.Password == "" {
-		r.dataPowerAppliance.SetDpPlaintextPassword(config.DpTransientPasswordMap[applianceConfigName])
+		r.dataPowerAppliance.SetDpPlaintextPassword(config.DpTransientPassword(applianceConfigName))
 	}
 
 	switch r.dataPowerAppliance.DpManagmentInterface() {
@@ -787,7 +957,7 @@ func (r *dpRepo) ExportAppliance(applianceConfigName, exportFileName string) ([]
 	case config.DpInterfaceSoma:
 		// 1. Fetch export (backup) of all domains
 		//    Backup contains all domains export zip + export info and dp-aux files
-		domains, err := r.fetchDpDomains()
+		domains, err := r.fetchDpDomains(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -809,7 +979,7 @@ func (r *dpRepo) ExportAppliance(applianceConfigName, exportFileName string) ([]
 		</man:request>
 	</soapenv:Body>
 </soapenv:Envelope>`, exportFileName, backupRequestSomaDomains)
-		backupResponseSoma, err := r.soma(backupRequestSoma)
+		backupResponseSoma, err := r.soma(ctx, backupRequestSoma)
 		if err != nil {
 			return nil, err
 		}
@@ -832,7 +1002,7 @@ func (r *dpRepo) ExportAppliance(applianceConfigName, exportFileName string) ([]
 
 // ExportDomain creates export of given domain and returns base64 encoded
 // exported zip file.
-func (r *dpRepo) ExportDomain(domainName, exportFileName string) ([]byte, error) {
+func (r *dpRepo) ExportDomain(ctx context.Context, domainName, exportFileName string) ([]byte, error) {
 	logging.LogDebugf("repo/dp/ExportDomain('%s', '%s')", domainName, exportFileName)
 	switch r.dataPowerAppliance.DpManagmentInterface() {
 	case config.DpInterfaceRest:
@@ -846,7 +1016,7 @@ func (r *dpRepo) ExportDomain(domainName, exportFileName string) ([]byte, error)
 		    "IncludeInternalFiles":"off"
 		  }
 		}`, exportFileName)
-		locationURL, _, err := r.restPostForResult(
+		locationURL, _, err := r.restPostForResult(ctx,
 			"/mgmt/actionqueue/"+domainName,
 			exportRequestJSON,
 			"/Export/status",
@@ -859,7 +1029,7 @@ func (r *dpRepo) ExportDomain(domainName, exportFileName string) ([]byte, error)
 		timeStart := time.Now()
 		for {
 			// 2. Check for current status of export request
-			status, exportResponseJSON, err := r.restGetForOneResult(locationURL, "/status")
+			status, exportResponseJSON, err := r.restGetForOneResult(ctx, locationURL, "/status")
 			logging.LogDebugf("repo/dp/ExportDomain() status: '%s'", status)
 			if err != nil {
 				return nil, err
@@ -900,7 +1070,7 @@ func (r *dpRepo) ExportDomain(domainName, exportFileName string) ([]byte, error)
 		</man:request>
 	</soapenv:Body>
 </soapenv:Envelope>`, exportFileName, domainName)
-		backupResponseSoma, err := r.soma(backupRequestSoma)
+		backupResponseSoma, err := r.soma(ctx, backupRequestSoma)
 		if err != nil {
 			return nil, err
 		}
@@ -954,10 +1124,222 @@ func (r *dpRepo) ExportDomain(domainName, exportFileName string) ([]byte, error)
 	}
 }
 
+// ImportAppliance restores backup of whole DataPower appliance from given
+// zip file bytes and returns results of import for each object and file.
+// If dryRun is set import is only simulated and results show which objects
+// and files would be created, modified or left untouched.
+func (r *dpRepo) ImportAppliance(ctx context.Context, applianceConfigName string, backupFileBytes []byte,
+	overwriteFiles, overwriteObjects, dryRun bool) ([]ImportResult, error) {
+	logging.LogDebugf("repo/dp/ImportAppliance('%s', .., %t, %t, %t)",
+		applianceConfigName, overwriteFiles, overwriteObjects, dryRun)
+
+	// 0. Prepare DataPower connection configuration.
+	oldDataPowerAppliance := r.dataPowerAppliance
+	r.dataPowerAppliance = dpApplicance{name: applianceConfigName,
+		DataPowerAppliance: config.Conf.DataPowerAppliances[applianceConfigName]}
+	clearCurrentConfig := func() {
+		r.dataPowerAppliance = oldDataPowerAppliance
+	}
+	defer clearCurrentConfig()
+	if r.dataPowerAppliance.Password == "" {
+		r.dataPowerAppliance.SetDpPlaintextPassword(config.DpTransientPassword(applianceConfigName))
+	}
+
+	switch r.dataPowerAppliance.DpManagmentInterface() {
+	case config.DpInterfaceRest:
+		// Don't know how to restore backup of multiple domains using REST.
+		return nil,
+			errs.Errorf("DataPower management interface %s not supported for appliance import.",
+				r.dataPowerAppliance.DpManagmentInterface())
+	case config.DpInterfaceSoma:
+		restoreRequestSoma := fmt.Sprintf(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"
+  xmlns:man="http://www.datapower.com/schemas/management">
+	<soapenv:Header/>
+	<soapenv:Body>
+		<man:request>
+			<man:do-restore source-type="ZIP" overwrite-files="%t" overwrite-objects="%t" rewrite-local-ip="true" dry-run="%t">
+				<man:input-file>%s</man:input-file>
+			</man:do-restore>
+		</man:request>
+	</soapenv:Body>
+</soapenv:Envelope>`, overwriteFiles, overwriteObjects, dryRun,
+			base64.StdEncoding.EncodeToString(backupFileBytes))
+		restoreResponseSoma, err := r.soma(ctx, restoreRequestSoma)
+		if err != nil {
+			return nil, err
+		}
+
+		return parseImportResultsFromXML(restoreResponseSoma)
+	default:
+		return nil, errs.Errorf("DataPower management interface %s not supported.", r.dataPowerAppliance.DpManagmentInterface())
+	}
+}
+
+// ImportDomain imports given export zip file bytes into the given domain and
+// returns results of import for each object and file. If dryRun is set import
+// is only simulated and results show which objects and files would be
+// created, modified or left untouched.
+func (r *dpRepo) ImportDomain(ctx context.Context, domainName string, importFileBytes []byte,
+	overwriteFiles, overwriteObjects, dryRun bool) ([]ImportResult, error) {
+	logging.LogDebugf("repo/dp/ImportDomain('%s', .., %t, %t, %t)",
+		domainName, overwriteFiles, overwriteObjects, dryRun)
+
+	importFileB64 := base64.StdEncoding.EncodeToString(importFileBytes)
+	switch r.dataPowerAppliance.DpManagmentInterface() {
+	case config.DpInterfaceRest:
+		onOff := func(value bool) string {
+			if value {
+				return "on"
+			}
+			return "off"
+		}
+		// 1. Start import (send import request)
+		importRequestJSON := fmt.Sprintf(`{"Import":
+		  {
+		    "Format":"ZIP",
+		    "InputFile":"%s",
+		    "OverwriteFiles":"%s",
+		    "OverwriteObjects":"%s",
+		    "RewriteLocalIP":"on",
+		    "DryRun":"%s"
+		  }
+		}`, importFileB64, onOff(overwriteFiles), onOff(overwriteObjects), onOff(dryRun))
+		locationURL, _, err := r.restPostForResult(ctx,
+			"/mgmt/actionqueue/"+domainName,
+			importRequestJSON,
+			"/Import/status",
+			"Action request accepted.",
+			"/_links/location/href")
+		if err != nil {
+			return nil, err
+		}
+
+		timeStart := time.Now()
+		for {
+			// 2. Check for current status of import request
+			status, importResponseJSON, err := r.restGetForOneResult(ctx, locationURL, "/status")
+			logging.LogDebugf("repo/dp/ImportDomain() status: '%s'", status)
+			if err != nil {
+				return nil, err
+			}
+
+			switch status {
+			case "started":
+				if time.Since(timeStart) > 120*time.Second {
+					logging.LogDebugf("repo/dp/ImportDomain() waiting for import since %v, giving up.\n last importResponseJSON: '%s'", timeStart, importResponseJSON)
+					return nil, errs.Errorf("Import didn't finish since %v, giving up.", timeStart)
+				}
+				time.Sleep(1 * time.Second)
+			case "completed":
+				// 3. When import is completed parse import results
+				logging.LogDebugf("repo/dp/ImportDomain() import finished after %d.", time.Since(timeStart))
+				return parseImportResultsFromJSON(importResponseJSON)
+			default:
+				return nil, errs.Errorf("Unexpected response from server ('%s').", status)
+			}
+		}
+	case config.DpInterfaceSoma:
+		importRequestSoma := fmt.Sprintf(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"
+	xmlns:man="http://www.datapower.com/schemas/management">
+	<soapenv:Header/>
+	<soapenv:Body>
+		<man:request domain="%s">
+			<man:do-import source-type="ZIP" overwrite-files="%t" overwrite-objects="%t" rewrite-local-ip="true" dry-run="%t">
+				<man:input-file>%s</man:input-file>
+			</man:do-import>
+		</man:request>
+	</soapenv:Body>
+</soapenv:Envelope>`, domainName, overwriteFiles, overwriteObjects, dryRun, importFileB64)
+		importResponseSoma, err := r.soma(ctx, importRequestSoma)
+		if err != nil {
+			return nil, err
+		}
+
+		return parseImportResultsFromXML(importResponseSoma)
+	default:
+		logging.LogDebug("repo/dp/ImportDomain(), using neither REST neither SOMA.")
+		return nil, errs.Error("DataPower management interface not set.")
+	}
+}
+
+// parseImportResultsFromJSON parses REST Import action response and returns
+// results of import for each object and file.
+func parseImportResultsFromJSON(importResponseJSON string) ([]ImportResult, error) {
+	doc, err := jsonquery.Parse(strings.NewReader(importResponseJSON))
+	if err != nil {
+		logging.LogDebug("Error parsing response JSON.", err)
+		return nil, err
+	}
+
+	if jsonquery.FindOne(doc, "/result") == nil {
+		logging.LogDebugf("Can't find import result in response:\n'%s'", importResponseJSON)
+		return nil, errs.Error("Unexpected response from server, can't find import result.")
+	}
+
+	innerText := func(node *jsonquery.Node, name string) string {
+		child := node.SelectElement(name)
+		if child == nil {
+			return ""
+		}
+		return child.InnerText()
+	}
+
+	results := make([]ImportResult, 0)
+	for _, classNode := range jsonquery.Find(doc, "/result//imported-objects//class") {
+		objectNode := classNode.Parent
+		results = append(results, ImportResult{Type: "object",
+			Class:  classNode.InnerText(),
+			Name:   innerText(objectNode, "name"),
+			Status: innerText(objectNode, "status")})
+	}
+	for _, nameNode := range jsonquery.Find(doc, "/result//imported-files//name") {
+		fileNode := nameNode.Parent
+		results = append(results, ImportResult{Type: "file",
+			Name:   nameNode.InnerText(),
+			Status: innerText(fileNode, "status")})
+	}
+
+	return results, nil
+}
+
+// parseImportResultsFromXML parses SOMA do-import/do-restore response and
+// returns results of import for each object and file.
+func parseImportResultsFromXML(importResponseSoma string) ([]ImportResult, error) {
+	doc, err := xmlquery.Parse(strings.NewReader(importResponseSoma))
+	if err != nil {
+		logging.LogDebug("Error parsing response SOAP.", err)
+		return nil, err
+	}
+
+	if xmlquery.FindOne(doc, "//*[local-name()='response']/*[local-name()='import']") == nil {
+		resultNode := xmlquery.FindOne(doc, "//*[local-name()='response']/*[local-name()='result']")
+		if resultNode != nil {
+			return nil, errs.Errorf("DataPower import error: '%s'", strings.TrimSpace(resultNode.InnerText()))
+		}
+		logging.LogDebugf("Can't find import result in SOMA response:\n'%s'", importResponseSoma)
+		return nil, errs.Error("Unexpected SOMA, can't find import result.")
+	}
+
+	results := make([]ImportResult, 0)
+	for _, objectNode := range xmlquery.Find(doc, "//*[local-name()='imported-objects']/*[local-name()='object']") {
+		results = append(results, ImportResult{Type: "object",
+			Class:  objectNode.SelectAttr("class"),
+			Name:   objectNode.SelectAttr("name"),
+			Status: objectNode.SelectAttr("status")})
+	}
+	for _, fileNode := range xmlquery.Find(doc, "//*[local-name()='imported-files']/*[local-name()='file']") {
+		results = append(results, ImportResult{Type: "file",
+			Name:   fileNode.SelectAttr("name"),
+			Status: fileNode.SelectAttr("status")})
+	}
+
+	return results, nil
+}
+
 // SecureBackupAppliance creates secure backup of DataPower appliance using
 // given Certificate object certName on the given exportDestPath and returns
 // error in case of error or nil for success.
-func (r *dpRepo) SecureBackupAppliance(applianceConfigName, certName, exportDestPath string) error {
+func (r *dpRepo) SecureBackupAppliance(ctx context.Context, applianceConfigName, certName, exportDestPath string) error {
 	logging.LogDebugf("repo/dp/SecureBackupAppliance('%s', '%s', '%s')",
 		applianceConfigName, certName, exportDestPath)
 
@@ -970,7 +1352,7 @@ func (r *dpRepo) SecureBackupAppliance(applianceConfigName, certName, exportDest
 	}
 	defer clearCurrentConfig()
 	if r.dataPowerAppliance.Password == "" {
-		r.dataPowerAppliance.SetDpPlaintextPassword(config.DpTransientPasswordMap[applianceConfigName])
+		r.dataPowerAppliance.SetDpPlaintextPassword(config.DpTransientPassword(applianceConfigName))
 	}
 
 	switch r.dataPowerAppliance.DpManagmentInterface() {
@@ -995,7 +1377,7 @@ func (r *dpRepo) SecureBackupAppliance(applianceConfigName, certName, exportDest
 		</man:request>
 	</soapenv:Body>
 </soapenv:Envelope>`, certName, exportDestPath)
-		secureBackupResponseSoma, err := r.soma(secureBackupRequestSoma)
+		secureBackupResponseSoma, err := r.soma(ctx, secureBackupRequestSoma)
 		if err != nil {
 			return err
 		}
@@ -1015,7 +1397,7 @@ func (r *dpRepo) SecureBackupAppliance(applianceConfigName, certName, exportDest
 
 // GetObjectDetails parses DataPower export to show service policy
 // with all rules, matches & actions.
-func (r *dpRepo) GetObjectDetails(domainName, objectClassName, objectName string) ([]byte, error) {
+func (r *dpRepo) GetObjectDetails(ctx context.Context, domainName, objectClassName, objectName string) ([]byte, error) {
 	logging.LogDebugf("repo/dp/GetObjectDetails('%s', '%s', '%s')",
 		domainName, objectClassName, objectName)
 	switch r.dataPowerAppliance.DpManagmentInterface() {
@@ -1040,7 +1422,7 @@ func (r *dpRepo) GetObjectDetails(domainName, objectClassName, objectName string
 		      ]
 		  }
 		}`, objectClassName, objectName)
-		locationURL, _, err := r.restPostForResult(
+		locationURL, _, err := r.restPostForResult(ctx,
 			"/mgmt/actionqueue/"+domainName,
 			exportRequestJSON,
 			"/Export/status",
@@ -1053,7 +1435,7 @@ func (r *dpRepo) GetObjectDetails(domainName, objectClassName, objectName string
 		timeStart := time.Now()
 		for {
 			// 2. Check for current status of export request
-			status, exportResponseJSON, err := r.restGetForOneResult(locationURL, "/status")
+			status, exportResponseJSON, err := r.restGetForOneResult(ctx, locationURL, "/status")
 			logging.LogDebugf("repo/dp/GetObjectDetails() status: '%s'", status)
 			if err != nil {
 				return nil, err
@@ -1137,7 +1519,7 @@ func (r *dpRepo) GetObjectDetails(domainName, objectClassName, objectName string
       </man:request>
    </soapenv:Body>
 </soapenv:Envelope>`, domainName, objectClassName, objectName)
-		exportResponseSoma, err := r.soma(exportRequestSoma)
+		exportResponseSoma, err := r.soma(ctx, exportRequestSoma)
 		if err != nil {
 			return nil, err
 		}
@@ -1408,7 +1790,7 @@ func getObjectDetailsFromExportXML(exportXMLBytes []byte, objectClassName, objec
 
 // GetObject fetches DataPower object configuration. If persisted flag is true
 // fetch persisted object, otherwise fetch current object from memory.
-func (r *dpRepo) GetObject(dpDomain, objectClass, objectName string, persisted bool) ([]byte, error) {
+func (r *dpRepo) GetObject(ctx context.Context, dpDomain, objectClass, objectName string, persisted bool) ([]byte, error) {
 	logging.LogDebugf("repo/dp/GetObject('%s', '%s', '%s', %t)",
 		dpDomain, objectClass, objectName, persisted)
 
@@ -1419,7 +1801,7 @@ func (r *dpRepo) GetObject(dpDomain, objectClass, objectName string, persisted b
 		}
 		getObjectURL := fmt.Sprintf("/mgmt/config/%s/%s/%s",
 			dpDomain, objectClass, objectName)
-		objectJSON, err := r.restGet(getObjectURL)
+		objectJSON, err := r.restGet(ctx, getObjectURL)
 		if err != nil {
 			if respErr, ok := err.(errs.UnexpectedHTTPResponse); ok && respErr.StatusCode == 404 {
 				return nil, nil
@@ -1444,7 +1826,7 @@ func (r *dpRepo) GetObject(dpDomain, objectClass, objectName string, persisted b
 	</soapenv:Body>
 </soapenv:Envelope>`,
 			dpDomain, objectClass, objectName, persisted)
-		somaResponse, err := r.soma(somaRequest)
+		somaResponse, err := r.soma(ctx, somaRequest)
 		if err != nil {
 			return nil, err
 		}
@@ -1475,7 +1857,7 @@ func (r *dpRepo) GetObject(dpDomain, objectClass, objectName string, persisted b
 }
 
 // SetObject updates or creates DataPower object configuration.
-func (r *dpRepo) SetObject(dpDomain, objectClass, objectName string, objectContent []byte, existingObject bool) error {
+func (r *dpRepo) SetObject(ctx context.Context, dpDomain, objectClass, objectName string, objectContent []byte, existingObject bool) error {
 	logging.LogDebugf("repo/dp/SetObject('%s', '%s', '%s', .., %t)",
 		dpDomain, objectClass, objectName, existingObject)
 
@@ -1493,7 +1875,7 @@ func (r *dpRepo) SetObject(dpDomain, objectClass, objectName string, objectConte
 				dpDomain, objectClass)
 			setObjectMethod = "POST"
 		}
-		resultJSON, err := r.rest(setObjectURL, setObjectMethod, string(objectContent))
+		resultJSON, err := r.rest(ctx, setObjectURL, setObjectMethod, string(objectContent))
 		if err != nil {
 			return err
 		}
@@ -1522,7 +1904,7 @@ xmlns:man="http://www.datapower.com/schemas/management">
 	</soapenv:Body>
 </soapenv:Envelope>`, dpDomain, objectContent)
 		logging.LogDebugf("repo/dp/SetObject(), somaRequest: '%s'", somaRequest)
-		somaResponse, err := r.soma(somaRequest)
+		somaResponse, err := r.soma(ctx, somaRequest)
 		if err != nil {
 			return err
 		}
@@ -1567,8 +1949,457 @@ func (r *dpRepo) RenameObject(dpObject []byte, objectName string) ([]byte, error
 	}
 }
 
+// ListObjectClassNames returns names of all object classes known to the
+// DataPower appliance, including classes without any object instances.
+func (r *dpRepo) ListObjectClassNames(ctx context.Context) ([]string, error) {
+	logging.LogDebug("repo/dp/ListObjectClassNames()")
+
+	var classNames []string
+	switch r.dataPowerAppliance.DpManagmentInterface() {
+	case config.DpInterfaceRest:
+		doc, err := r.restGetDoc(ctx, "/mgmt/config/")
+		if err != nil {
+			return nil, err
+		}
+		classNames = make([]string, 0)
+		for _, linkNode := range jsonquery.Find(doc, "/_links/*") {
+			if linkNode.Data != "self" && linkNode.Data != "doc" {
+				classNames = append(classNames, linkNode.Data)
+			}
+		}
+	case config.DpInterfaceSoma:
+		somaRequest := `<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"
+	xmlns:man="http://www.datapower.com/schemas/management">
+	<soapenv:Header/>
+	<soapenv:Body>
+		<man:request>
+			<man:get-schema/>
+		</man:request>
+	</soapenv:Body>
+</soapenv:Envelope>`
+		doc, err := r.somaGetDoc(ctx, somaRequest)
+		if err != nil {
+			return nil, err
+		}
+		classNames = make([]string, 0)
+		query := "//*[local-name()='complexType' and " +
+			"*[local-name()='complexContent']/*[local-name()='extension' and @base='ConfigBase']]"
+		for _, typeNode := range xmlquery.Find(doc, query) {
+			typeName := typeNode.SelectAttr("name")
+			if strings.HasPrefix(typeName, "Config") {
+				classNames = append(classNames, strings.TrimPrefix(typeName, "Config"))
+			}
+		}
+	default:
+		logging.LogDebug("repo/dp/ListObjectClassNames(), using neither REST neither SOMA.")
+		return nil, errs.Error("DataPower management interface not set.")
+	}
+
+	if len(classNames) == 0 {
+		return nil, errs.Error("Can't find any object class on the appliance.")
+	}
+	sort.Strings(classNames)
+
+	logging.LogDebugf("repo/dp/ListObjectClassNames(), classNames: %v", classNames)
+	return classNames, nil
+}
+
+// CreateObjectSkeleton returns minimal XML/JSON definition of object with
+// given class and name which can be used as a starting point for creation
+// of new object (XML/JSON is used depending on REST/SOMA interface used).
+func (r *dpRepo) CreateObjectSkeleton(objectClass, objectName string) ([]byte, error) {
+	logging.LogDebugf("repo/dp/CreateObjectSkeleton('%s', '%s')", objectClass, objectName)
+	switch r.dataPowerAppliance.DpManagmentInterface() {
+	case config.DpInterfaceRest:
+		return []byte(fmt.Sprintf(`{
+  "%s": {
+    "name": "%s",
+    "mAdminState": "enabled"
+  }
+}
+`, objectClass, objectName)), nil
+	case config.DpInterfaceSoma:
+		return []byte(fmt.Sprintf(`<%s name="%s">
+  <mAdminState>enabled</mAdminState>
+</%s>
+`, objectClass, objectName, objectClass)), nil
+	default:
+		logging.LogDebug("repo/dp/CreateObjectSkeleton(), using neither REST neither SOMA.")
+		return nil, errs.Error("DataPower management interface not set.")
+	}
+}
+
+// GetDomainObjects fetches configurations of all DataPower objects in the
+// given domain.
+func (r *dpRepo) GetDomainObjects(ctx context.Context, dpDomain string) ([]DomainObject, error) {
+	logging.LogDebugf("repo/dp/GetDomainObjects('%s')", dpDomain)
+
+	var classNamesWithDuplicates []string
+	var err error
+	switch r.dataPowerAppliance.DpManagmentInterface() {
+	case config.DpInterfaceRest:
+		listObjectStatusesURL := fmt.Sprintf("/mgmt/status/%s/ObjectStatus", dpDomain)
+		classNamesWithDuplicates, _, err =
+			r.restGetForListResult(ctx, listObjectStatusesURL, "/ObjectStatus//Class")
+	case config.DpInterfaceSoma:
+		somaRequest := fmt.Sprintf(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"
+	xmlns:man="http://www.datapower.com/schemas/management">
+	<soapenv:Header/>
+	<soapenv:Body>
+		<man:request domain="%s">
+			<man:get-status class="ObjectStatus"/>
+		</man:request>
+	</soapenv:Body>
+</soapenv:Envelope>`, dpDomain)
+		var somaResponse string
+		somaResponse, err = r.soma(ctx, somaRequest)
+		if err != nil {
+			return nil, err
+		}
+		classNamesWithDuplicates, err = parseSOMAFindList(somaResponse,
+			"//*[local-name()='response']/*[local-name()='status']/*[local-name()='ObjectStatus']/Class")
+	default:
+		logging.LogDebug("repo/dp/GetDomainObjects(), using neither REST neither SOMA.")
+		return nil, errs.Error("DataPower management interface not set.")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	classNameMap := make(map[string]bool)
+	objects := make([]DomainObject, 0)
+	for _, className := range classNamesWithDuplicates {
+		if classNameMap[className] {
+			continue
+		}
+		classNameMap[className] = true
+
+		// Objects are read from the object class configurations since ObjectStatus
+		// can contain wrong names for "singleton" objects (see listObjects).
+		var classObjects []DomainObject
+		switch r.dataPowerAppliance.DpManagmentInterface() {
+		case config.DpInterfaceRest:
+			listObjectsURL := fmt.Sprintf("/mgmt/config/%s/%s", dpDomain, className)
+			var objectsJSON string
+			objectsJSON, err = r.restGet(ctx, listObjectsURL)
+			if err != nil {
+				return nil, err
+			}
+			classObjects, err = parseRESTDomainObjects(objectsJSON, className)
+		case config.DpInterfaceSoma:
+			somaRequest := fmt.Sprintf(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"
+	xmlns:man="http://www.datapower.com/schemas/management">
+	<soapenv:Header/>
+	<soapenv:Body>
+		<man:request domain="%s">
+			<man:get-config class="%s"/>
+		</man:request>
+	</soapenv:Body>
+</soapenv:Envelope>`, dpDomain, className)
+			var somaResponse string
+			somaResponse, err = r.soma(ctx, somaRequest)
+			if err != nil {
+				return nil, err
+			}
+			classObjects, err = parseSOMADomainObjects(somaResponse, className)
+		}
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, classObjects...)
+	}
+
+	logging.LogDebugf("repo/dp/GetDomainObjects(), len(objects): %d", len(objects))
+	return objects, nil
+}
+
+// parseRESTDomainObjects parses REST configuration list of the object class,
+// object configurations are cleaned the same way as in GetObject.
+func parseRESTDomainObjects(objectsJSON, className string) ([]DomainObject, error) {
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(objectsJSON), &response); err != nil {
+		logging.LogDebug("Error parsing JSON.", err)
+		return nil, err
+	}
+	classJSON, ok := response[className]
+	if !ok {
+		return nil, nil
+	}
+	// Class with a single object is not returned as an array.
+	var objectJSONs []json.RawMessage
+	if err := json.Unmarshal(classJSON, &objectJSONs); err != nil {
+		objectJSONs = []json.RawMessage{classJSON}
+	}
+
+	objects := make([]DomainObject, 0, len(objectJSONs))
+	for _, objectJSON := range objectJSONs {
+		var object struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(objectJSON, &object); err != nil {
+			logging.LogDebug("Error parsing JSON.", err)
+			return nil, err
+		}
+		configJSON, err := json.Marshal(map[string]json.RawMessage{className: objectJSON})
+		if err != nil {
+			return nil, err
+		}
+		cleanedJSON, err := cleanJSONObject(string(configJSON))
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, DomainObject{Class: className, Name: object.Name, Config: cleanedJSON})
+	}
+
+	return objects, nil
+}
+
+// parseSOMADomainObjects parses SOMA configuration list of the object class,
+// object configurations are cleaned the same way as in GetObject.
+func parseSOMADomainObjects(somaResponse, className string) ([]DomainObject, error) {
+	doc, err := xmlquery.Parse(strings.NewReader(somaResponse))
+	if err != nil {
+		logging.LogDebug("Error parsing response SOAP.", err)
+		return nil, err
+	}
+
+	configNodes := xmlquery.Find(doc, "//*[local-name()='response']/*[local-name()='config']/*")
+	objects := make([]DomainObject, 0, len(configNodes))
+	for _, configNode := range configNodes {
+		cleanedXML, err := cleanXML(configNode.OutputXML(true))
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects,
+			DomainObject{Class: className, Name: configNode.SelectAttr("name"), Config: []byte(cleanedXML)})
+	}
+
+	return objects, nil
+}
+
+// DiffDomainObjects compares DataPower objects from two domains and returns
+// objects found only in the left domain, only in the right domain or objects
+// with different configuration, sorted by object class and name.
+func DiffDomainObjects(leftObjects, rightObjects []DomainObject) []ObjectDiff {
+	logging.LogDebugf("repo/dp/DiffDomainObjects(%d, %d)", len(leftObjects), len(rightObjects))
+	objectKey := func(object DomainObject) string {
+		return object.Class + "/" + object.Name
+	}
+	rightObjectMap := make(map[string]DomainObject, len(rightObjects))
+	for _, rightObject := range rightObjects {
+		rightObjectMap[objectKey(rightObject)] = rightObject
+	}
+
+	diffs := make([]ObjectDiff, 0)
+	leftObjectKeys := make(map[string]bool, len(leftObjects))
+	for _, leftObject := range leftObjects {
+		leftObjectKeys[objectKey(leftObject)] = true
+		rightObject, found := rightObjectMap[objectKey(leftObject)]
+		switch {
+		case !found:
+			diffs = append(diffs, ObjectDiff{Class: leftObject.Class, Name: leftObject.Name,
+				Status: ObjectDiffOnlyLeft, Left: leftObject.Config})
+		case objectConfigsDiffer(leftObject.Config, rightObject.Config):
+			diffs = append(diffs, ObjectDiff{Class: leftObject.Class, Name: leftObject.Name,
+				Status: ObjectDiffChanged, Left: leftObject.Config, Right: rightObject.Config})
+		}
+	}
+	for _, rightObject := range rightObjects {
+		if !leftObjectKeys[objectKey(rightObject)] {
+			diffs = append(diffs, ObjectDiff{Class: rightObject.Class, Name: rightObject.Name,
+				Status: ObjectDiffOnlyRight, Right: rightObject.Config})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Class != diffs[j].Class {
+			return diffs[i].Class < diffs[j].Class
+		}
+		return diffs[i].Name < diffs[j].Name
+	})
+
+	return diffs
+}
+
+// objectConfigsDiffer returns true if object configurations differ - if
+// configurations can't be compared structurally they are compared as text.
+func objectConfigsDiffer(leftConfig, rightConfig []byte) bool {
+	propertyDiffs, err := CompareObjects(leftConfig, rightConfig)
+	if err != nil {
+		logging.LogDebugf("repo/dp/objectConfigsDiffer() - can't compare structurally: %v", err)
+		return normalizeObjectConfig(leftConfig) != normalizeObjectConfig(rightConfig)
+	}
+	return len(propertyDiffs) > 0
+}
+
+// normalizeObjectConfig returns object configuration (already cleaned by
+// GetObject) in a form suitable for comparison - JSON is reformatted with
+// sorted keys and surrounding whitespace is removed.
+func normalizeObjectConfig(objectConfig []byte) string {
+	var objectJSON interface{}
+	if err := json.Unmarshal(objectConfig, &objectJSON); err == nil {
+		normalizedJSON, err := json.MarshalIndent(objectJSON, "", "  ")
+		if err == nil {
+			return string(normalizedJSON)
+		}
+	}
+	return strings.TrimSpace(string(objectConfig))
+}
+
+// CompareObjects compares two DataPower object configurations (XML or JSON)
+// structurally - formatting and order of elements/list entries are ignored.
+// Returns added, removed and changed properties sorted by property path.
+func CompareObjects(oldObject, newObject []byte) ([]PropertyDiff, error) {
+	logging.LogDebug("repo/dp/CompareObjects(..)")
+	oldMap, err := parseObjectMap(oldObject)
+	if err != nil {
+		return nil, err
+	}
+	newMap, err := parseObjectMap(newObject)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := compareObjectValues("", map[string]interface{}(oldMap),
+		map[string]interface{}(newMap), make([]PropertyDiff, 0))
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].Path < diffs[j].Path
+	})
+
+	logging.LogDebugf("repo/dp/CompareObjects(), diffs: %v", diffs)
+	return diffs, nil
+}
+
+// parseObjectMap parses DataPower object configuration given as JSON or XML,
+// empty configuration (non-existing object) is parsed as empty map.
+func parseObjectMap(object []byte) (mxj.Map, error) {
+	trimmedObject := bytes.TrimSpace(object)
+	switch {
+	case len(trimmedObject) == 0:
+		return mxj.Map{}, nil
+	case json.Valid(trimmedObject):
+		return mxj.NewMapJson(trimmedObject)
+	default:
+		return mxj.NewMapXml(trimmedObject)
+	}
+}
+
+// compareObjectValues compares values of object properties found on the given
+// path and appends differences found to diffs.
+func compareObjectValues(path string, oldValue, newValue interface{}, diffs []PropertyDiff) []PropertyDiff {
+	oldList, oldIsList := oldValue.([]interface{})
+	newList, newIsList := newValue.([]interface{})
+	if oldIsList || newIsList {
+		// Element repeated only once in XML is not parsed as list.
+		if !oldIsList {
+			oldList = []interface{}{oldValue}
+		}
+		if !newIsList {
+			newList = []interface{}{newValue}
+		}
+		if len(oldList) == 1 && len(newList) == 1 {
+			return compareObjectValues(path, oldList[0], newList[0], diffs)
+		}
+		return compareObjectLists(path, oldList, newList, diffs)
+	}
+
+	oldMap, oldIsMap := oldValue.(map[string]interface{})
+	newMap, newIsMap := newValue.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		for key, oldChild := range oldMap {
+			childPath := objectPropertyPath(path, key)
+			newChild, found := newMap[key]
+			if !found {
+				diffs = append(diffs, PropertyDiff{Path: childPath, Status: PropertyRemoved,
+					OldValue: objectValueString(oldChild)})
+				continue
+			}
+			diffs = compareObjectValues(childPath, oldChild, newChild, diffs)
+		}
+		for key, newChild := range newMap {
+			if _, found := oldMap[key]; !found {
+				diffs = append(diffs, PropertyDiff{Path: objectPropertyPath(path, key),
+					Status: PropertyAdded, NewValue: objectValueString(newChild)})
+			}
+		}
+		return diffs
+	}
+
+	oldString, newString := objectValueString(oldValue), objectValueString(newValue)
+	if oldString != newString {
+		diffs = append(diffs, PropertyDiff{Path: path, Status: PropertyChanged,
+			OldValue: oldString, NewValue: newString})
+	}
+	return diffs
+}
+
+// compareObjectLists compares list entries regardless of their order and
+// appends removed and added entries to diffs.
+func compareObjectLists(path string, oldList, newList []interface{}, diffs []PropertyDiff) []PropertyDiff {
+	listPath := path + "[]"
+	oldCounts := make(map[string]int)
+	for _, oldEntry := range oldList {
+		oldCounts[objectValueString(oldEntry)]++
+	}
+	newCounts := make(map[string]int)
+	for _, newEntry := range newList {
+		newCounts[objectValueString(newEntry)]++
+	}
+
+	oldSeen := make(map[string]int)
+	for _, oldEntry := range oldList {
+		oldString := objectValueString(oldEntry)
+		oldSeen[oldString]++
+		if oldSeen[oldString] > newCounts[oldString] {
+			diffs = append(diffs, PropertyDiff{Path: listPath, Status: PropertyRemoved, OldValue: oldString})
+		}
+	}
+	newSeen := make(map[string]int)
+	for _, newEntry := range newList {
+		newString := objectValueString(newEntry)
+		newSeen[newString]++
+		if newSeen[newString] > oldCounts[newString] {
+			diffs = append(diffs, PropertyDiff{Path: listPath, Status: PropertyAdded, NewValue: newString})
+		}
+	}
+	return diffs
+}
+
+// objectPropertyPath returns path of the child property - XML attributes
+// (parsed with "-" prefix) are shown with "@" prefix and XML element text is
+// shown as element value.
+func objectPropertyPath(parentPath, key string) string {
+	switch {
+	case key == "#text":
+		return parentPath
+	case strings.HasPrefix(key, "-"):
+		key = "@" + key[1:]
+	}
+	if parentPath == "" {
+		return key
+	}
+	return parentPath + "/" + key
+}
+
+// objectValueString returns string representation of the object property
+// value - complex values are represented as JSON with sorted keys.
+func objectValueString(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(value)
+	default:
+		valueJSON, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		return string(valueJSON)
+	}
+}
+
 // GetStatus fetches DataPower status info.
-func (r *dpRepo) GetStatus(dpDomain, statusClass string, statusIdx int) ([]byte, error) {
+func (r *dpRepo) GetStatus(ctx context.Context, dpDomain, statusClass string, statusIdx int) ([]byte, error) {
 	logging.LogDebugf("repo/dp/GetStatus('%s', '%s', %d)",
 		dpDomain, statusClass, statusIdx)
 
@@ -1576,7 +2407,7 @@ func (r *dpRepo) GetStatus(dpDomain, statusClass string, statusIdx int) ([]byte,
 	case config.DpInterfaceRest:
 		getStatusesURL := fmt.Sprintf("/mgmt/status/%s/%s",
 			dpDomain, statusClass)
-		statusesRespJSON, err := r.restGet(getStatusesURL)
+		statusesRespJSON, err := r.restGet(ctx, getStatusesURL)
 		if err != nil {
 			if respErr, ok := err.(errs.UnexpectedHTTPResponse); ok && respErr.StatusCode == 404 {
 				return nil, nil
@@ -1642,7 +2473,7 @@ func (r *dpRepo) GetStatus(dpDomain, statusClass string, statusIdx int) ([]byte,
 	</soapenv:Body>
 </soapenv:Envelope>`,
 			dpDomain, statusClass)
-		somaResponse, err := r.soma(somaStatusRequest)
+		somaResponse, err := r.soma(ctx, somaStatusRequest)
 		if err != nil {
 			return nil, err
 		}
@@ -1674,14 +2505,14 @@ func (r *dpRepo) GetStatus(dpDomain, statusClass string, statusIdx int) ([]byte,
 }
 
 // GetStatuses fetches DataPower status info for all statuses in class.
-func (r *dpRepo) GetStatuses(dpDomain, statusClass string) ([]byte, error) {
+func (r *dpRepo) GetStatuses(ctx context.Context, dpDomain, statusClass string) ([]byte, error) {
 	logging.LogDebugf("repo/dp/GetStatuses('%s', '%s')", dpDomain, statusClass)
 
 	switch r.dataPowerAppliance.DpManagmentInterface() {
 	case config.DpInterfaceRest:
 		getStatusesURL := fmt.Sprintf("/mgmt/status/%s/%s",
 			dpDomain, statusClass)
-		statusesRespJSON, err := r.restGet(getStatusesURL)
+		statusesRespJSON, err := r.restGet(ctx, getStatusesURL)
 		if err != nil {
 			if respErr, ok := err.(errs.UnexpectedHTTPResponse); ok && respErr.StatusCode == 404 {
 				return nil, nil
@@ -1721,7 +2552,7 @@ func (r *dpRepo) GetStatuses(dpDomain, statusClass string) ([]byte, error) {
 	</soapenv:Body>
 </soapenv:Envelope>`,
 			dpDomain, statusClass)
-		somaResponse, err := r.soma(somaStatusRequest)
+		somaResponse, err := r.soma(ctx, somaStatusRequest)
 		if err != nil {
 			return nil, err
 		}
@@ -1752,12 +2583,12 @@ func (r *dpRepo) GetStatuses(dpDomain, statusClass string) ([]byte, error) {
 }
 
 // SaveConfiguration saves current DataPower configuration.
-func (r *dpRepo) SaveConfiguration(itemConfig *model.ItemConfig) error {
+func (r *dpRepo) SaveConfiguration(ctx context.Context, itemConfig *model.ItemConfig) error {
 	logging.LogDebugf("repo/dp/SaveConfiguration(%v)", itemConfig)
 	switch r.dataPowerAppliance.DpManagmentInterface() {
 	case config.DpInterfaceRest:
 		saveConfigRequestJSON := `{"SaveConfig":"0"}`
-		resultText, _, err := r.restPostForResult(
+		resultText, _, err := r.restPostForResult(ctx,
 			"/mgmt/actionqueue/"+itemConfig.DpDomain,
 			saveConfigRequestJSON,
 			"/SaveConfig",
@@ -1781,7 +2612,7 @@ func (r *dpRepo) SaveConfiguration(itemConfig *model.ItemConfig) error {
 	</soapenv:Body>
 </soapenv:Envelope>`, itemConfig.DpDomain)
 		logging.LogDebugf("repo/dp/SaveConfiguration(), somaRequest: '%s'", somaRequest)
-		somaResponse, err := r.soma(somaRequest)
+		somaResponse, err := r.soma(ctx, somaRequest)
 		if err != nil {
 			return err
 		}
@@ -1804,7 +2635,7 @@ func (r *dpRepo) SaveConfiguration(itemConfig *model.ItemConfig) error {
 }
 
 // CreateDomain creates new domain on DataPower appliance.
-func (r *dpRepo) CreateDomain(domainName string) error {
+func (r *dpRepo) CreateDomain(ctx context.Context, domainName string) error {
 	logging.LogDebugf("repo/dp/CreateDomain('%s')", domainName)
 
 	switch r.dataPowerAppliance.DpManagmentInterface() {
@@ -1825,7 +2656,7 @@ func (r *dpRepo) CreateDomain(domainName string) error {
 	</soapenv:Body>
 </soapenv:Envelope>`, domainName)
 		logging.LogDebugf("repo/dp/CreateDomain(), somaRequest: '%s'", somaRequest)
-		somaResponse, err := r.soma(somaRequest)
+		somaResponse, err := r.soma(ctx, somaRequest)
 		if err != nil {
 			return err
 		}
@@ -1846,6 +2677,64 @@ func (r *dpRepo) CreateDomain(domainName string) error {
 	}
 }
 
+// DeleteDomain deletes domain from DataPower appliance.
+func (r *dpRepo) DeleteDomain(ctx context.Context, domainName string) error {
+	logging.LogDebugf("repo/dp/DeleteDomain('%s')", domainName)
+
+	if domainName == "default" {
+		return errs.Error("Can't delete default domain.")
+	}
+
+	switch r.dataPowerAppliance.DpManagmentInterface() {
+	case config.DpInterfaceRest:
+		restPath := fmt.Sprintf("/mgmt/config/default/Domain/%s", domainName)
+		jsonString, err := r.rest(ctx, restPath, "DELETE", "")
+		if err != nil {
+			return err
+		}
+		logging.LogDebugf("repo/dp/DeleteDomain(), jsonString: '%s'", jsonString)
+		resultMsg, err := parseJSONFindOne(jsonString, fmt.Sprintf("/%s", domainName))
+		if err != nil {
+			return err
+		}
+		if resultMsg != "Configuration was deleted." {
+			return errs.Errorf("Unexpected result of REST delete: '%s'.", resultMsg)
+		}
+
+		return nil
+	case config.DpInterfaceSoma:
+		somaRequest := fmt.Sprintf(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"
+	xmlns:man="http://www.datapower.com/schemas/management">
+	<soapenv:Header/>
+	<soapenv:Body>
+		<man:request>
+			<man:del-config><Domain name="%s"/></man:del-config>
+		</man:request>
+	</soapenv:Body>
+</soapenv:Envelope>`, domainName)
+		logging.LogDebugf("repo/dp/DeleteDomain(), somaRequest: '%s'", somaRequest)
+		somaResponse, err := r.soma(ctx, somaRequest)
+		if err != nil {
+			return err
+		}
+
+		logging.LogDebugf("repo/dp/DeleteDomain(), somaResponse: '%s'", somaResponse)
+		resultMsg, err := parseSOMAFindOne(somaResponse, "//*[local-name()='response']/*[local-name()='result']")
+		if err != nil {
+			return err
+		}
+		resultMsg = strings.TrimSpace(resultMsg)
+		if resultMsg != "OK" {
+			return errs.Errorf("Unexpected result of SOMA delete: '%s'.", resultMsg)
+		}
+
+		return nil
+	default:
+		logging.LogDebug("repo/dp/DeleteDomain(), using neither REST neither SOMA.")
+		return errs.Error("DataPower management interface not set.")
+	}
+}
+
 // ParseObjectClassAndName parses bytes with XML/JSON definition of object
 // (XML/JSON should be used depending on REST/SOMA interface used).
 func (r *dpRepo) ParseObjectClassAndName(objectBytes []byte) (objectClass, objectName string, err error) {
@@ -1920,7 +2809,7 @@ func (r *dpRepo) GetItemInfo(itemConfig *model.ItemConfig) ([]byte, error) {
 	return itemInfo, err
 }
 
-func (r *dpRepo) FlushCache(
+func (r *dpRepo) FlushCache(ctx context.Context,
 	domainName, statusClass, statusName string, itemType model.ItemType) (bool, error) {
 	logging.LogDebugf("repo/dp/FlushCache('%s', '%s', '%s' (%s))",
 		domainName, statusClass, statusName, itemType)
@@ -1934,12 +2823,12 @@ func (r *dpRepo) FlushCache(
 				getStatusesURL := fmt.Sprintf("/mgmt/status/%s/%s", domainName, statusClass)
 				getStatusesQuery := fmt.Sprintf("/%s//XMLManager/value", statusClass)
 				statusNames, _, err :=
-					r.restGetForListResult(getStatusesURL, getStatusesQuery)
+					r.restGetForListResult(ctx, getStatusesURL, getStatusesQuery)
 				if err != nil {
 					return false, err
 				}
 				for _, statusName := range statusNames {
-					res, err := r.FlushCache(
+					res, err := r.FlushCache(ctx,
 						domainName, statusClass, statusName, model.ItemDpStatus)
 					if err != nil || !res {
 						return res, err
@@ -1960,7 +2849,7 @@ func (r *dpRepo) FlushCache(
 				</man:request>
 			</soapenv:Body>
 		</soapenv:Envelope>`, domainName, statusClass)
-				doc, err := r.somaGetDoc(somaStatusRequest)
+				doc, err := r.somaGetDoc(ctx, somaStatusRequest)
 				if err != nil {
 					return false, err
 				}
@@ -1974,7 +2863,7 @@ func (r *dpRepo) FlushCache(
 				}
 				for _, node := range nodes {
 					statusName := node.InnerText()
-					res, err := r.FlushCache(
+					res, err := r.FlushCache(ctx,
 						domainName, statusClass, statusName, model.ItemDpStatus)
 					if err != nil || !res {
 						return res, err
@@ -2006,7 +2895,7 @@ func (r *dpRepo) FlushCache(
 			flushRequestJSON :=
 				fmt.Sprintf(`{"%s":{"XMLManager":"%s"}}`, flushCacheOp, statusName)
 
-			jsonResponseString, err := r.rest(restActionPath, "POST", flushRequestJSON)
+			jsonResponseString, err := r.rest(ctx, restActionPath, "POST", flushRequestJSON)
 			if err != nil {
 				return false, err
 			}
@@ -2035,7 +2924,7 @@ func (r *dpRepo) FlushCache(
    </soapenv:Body>
 </soapenv:Envelope>`,
 				domainName, flushCacheOp, statusName, flushCacheOp)
-			somaResponse, err := r.soma(somaRequest)
+			somaResponse, err := r.soma(ctx, somaRequest)
 			if err != nil {
 				return false, err
 			}
@@ -2063,13 +2952,13 @@ func (r *dpRepo) FlushCache(
 }
 
 // ExecConfig run exec dommand for a DataPower configuration script.
-func (r *dpRepo) ExecConfig(itemConfig *model.ItemConfig) error {
+func (r *dpRepo) ExecConfig(ctx context.Context, itemConfig *model.ItemConfig) error {
 	logging.LogDebugf("repo/dp/ExecConfig(%v)", itemConfig)
 
 	switch r.dataPowerAppliance.DpManagmentInterface() {
 	case config.DpInterfaceRest:
 		execConfigRequestJSON := fmt.Sprintf(`{"ExecConfig":{"URL":"%s"}}`, itemConfig.Path)
-		resultText, _, err := r.restPostForResult(
+		resultText, _, err := r.restPostForResult(ctx,
 			"/mgmt/actionqueue/"+itemConfig.DpDomain,
 			execConfigRequestJSON,
 			"/ExecConfig",
@@ -2092,7 +2981,7 @@ func (r *dpRepo) ExecConfig(itemConfig *model.ItemConfig) error {
 		</man:request>
 	</soapenv:Body>
 </soapenv:Envelope>`, itemConfig.DpDomain, itemConfig.Path)
-		response, err := r.soma(somaRequest)
+		response, err := r.soma(ctx, somaRequest)
 		if err != nil {
 			return err
 		}
@@ -2165,9 +3054,9 @@ func listAppliances() (model.ItemList, error) {
 }
 
 // listDomains loads DataPower domains from current DataPower.
-func (r *dpRepo) listDomains(selectedItemConfig *model.ItemConfig) (model.ItemList, error) {
+func (r *dpRepo) listDomains(ctx context.Context, selectedItemConfig *model.ItemConfig) (model.ItemList, error) {
 	logging.LogDebugf("repo/dp/listDomains('%s')", selectedItemConfig)
-	domains, err := r.fetchDpDomains()
+	domains, err := r.fetchDpDomains(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -2199,11 +3088,11 @@ func (r *dpRepo) listDomains(selectedItemConfig *model.ItemConfig) (model.ItemLi
 }
 
 // listFilestores loads DataPower filestores in current domain (cert:, local:,..).
-func (r *dpRepo) listFilestores(selectedItemConfig *model.ItemConfig) (model.ItemList, error) {
+func (r *dpRepo) listFilestores(ctx context.Context, selectedItemConfig *model.ItemConfig) (model.ItemList, error) {
 	logging.LogDebugf("repo/dp/listFilestores('%s')", selectedItemConfig)
 	switch r.dataPowerAppliance.DpManagmentInterface() {
 	case config.DpInterfaceRest:
-		jsonString, err := r.restGet("/mgmt/filestore/" + selectedItemConfig.DpDomain)
+		jsonString, err := r.restGet(ctx, "/mgmt/filestore/"+selectedItemConfig.DpDomain)
 		if err != nil {
 			return nil, err
 		}
@@ -2244,7 +3133,7 @@ func (r *dpRepo) listFilestores(selectedItemConfig *model.ItemConfig) (model.Ite
 		</man:request>
 	</soapenv:Body>
 </soapenv:Envelope>`, selectedItemConfig.DpDomain)
-		dpFilestoresXML, err := r.soma(somaRequest)
+		dpFilestoresXML, err := r.soma(ctx, somaRequest)
 		if err != nil {
 			return nil, err
 		}
@@ -2278,10 +3167,10 @@ func (r *dpRepo) listFilestores(selectedItemConfig *model.ItemConfig) (model.Ite
 }
 
 // listDpDir loads DataPower directory (local:, local:///test,..).
-func (r *dpRepo) listDpDir(selectedItemConfig *model.ItemConfig) (model.ItemList, error) {
+func (r *dpRepo) listDpDir(ctx context.Context, selectedItemConfig *model.ItemConfig) (model.ItemList, error) {
 	logging.LogDebugf("repo/dp/listDpDir('%s')", selectedItemConfig)
 	parentDir := model.Item{Name: "..", Config: selectedItemConfig.Parent}
-	filesDirs, err := r.listFiles(selectedItemConfig)
+	filesDirs, err := r.listFiles(ctx, selectedItemConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -2293,7 +3182,7 @@ func (r *dpRepo) listDpDir(selectedItemConfig *model.ItemConfig) (model.ItemList
 	return itemsWithParentDir, nil
 }
 
-func (r *dpRepo) fetchFilestoreIfNeeded(dpDomain, dpFilestoreLocation string, forceReload bool) error {
+func (r *dpRepo) fetchFilestoreIfNeeded(ctx context.Context, dpDomain, dpFilestoreLocation string, forceReload bool) error {
 	if r.dataPowerAppliance.SomaUrl != "" {
 		// If we open filestore or open file but want to reload - refresh current filestore XML cache.
 		if forceReload || r.invalidateCache || r.dpFilestoreXmls[dpFilestoreLocation] == "" {
@@ -2305,7 +3194,7 @@ func (r *dpRepo) fetchFilestoreIfNeeded(dpDomain, dpFilestoreLocation string, fo
 	</soapenv:Body>
 </soapenv:Envelope>`, dpDomain, dpFilestoreLocation)
 			var err error
-			r.dpFilestoreXmls[dpFilestoreLocation], err = r.soma(somaRequest)
+			r.dpFilestoreXmls[dpFilestoreLocation], err = r.soma(ctx, somaRequest)
 			if err != nil {
 				return err
 			}
@@ -2315,14 +3204,14 @@ func (r *dpRepo) fetchFilestoreIfNeeded(dpDomain, dpFilestoreLocation string, fo
 	return nil
 }
 
-func (r *dpRepo) listFiles(selectedItemConfig *model.ItemConfig) ([]model.Item, error) {
+func (r *dpRepo) listFiles(ctx context.Context, selectedItemConfig *model.ItemConfig) ([]model.Item, error) {
 	logging.LogDebugf("repo/dp/listFiles('%s')", selectedItemConfig)
 
 	switch r.dataPowerAppliance.DpManagmentInterface() {
 	case config.DpInterfaceRest:
 		items := make(model.ItemList, 0)
 		currRestDirPath := strings.Replace(selectedItemConfig.Path, ":", "", 1)
-		jsonString, err := r.restGet("/mgmt/filestore/" + selectedItemConfig.DpDomain + "/" + currRestDirPath)
+		jsonString, err := r.restGet(ctx, "/mgmt/filestore/"+selectedItemConfig.DpDomain+"/"+currRestDirPath)
 		if err != nil {
 			return nil, err
 		}
@@ -2382,7 +3271,7 @@ func (r *dpRepo) listFiles(selectedItemConfig *model.ItemConfig) ([]model.Item,
 		var dpFileNodes []*xmlquery.Node
 
 		// If we open filestore or open file but want to reload - refresh current filestore XML cache.
-		err := r.fetchFilestoreIfNeeded(selectedItemConfig.DpDomain, dpFilestoreLocation, dpFilestoreIsRoot)
+		err := r.fetchFilestoreIfNeeded(ctx, selectedItemConfig.DpDomain, dpFilestoreLocation, dpFilestoreIsRoot)
 		if err != nil {
 			logging.LogDebug("Error parsing response SOMA.", err)
 			return nil, err
@@ -2450,7 +3339,7 @@ func (r *dpRepo) listFiles(selectedItemConfig *model.ItemConfig) ([]model.Item,
 }
 
 // listObjectClasses lists all object classes used in current DataPower domain.
-func (r *dpRepo) listObjectClasses(currentView *model.ItemConfig) (model.ItemList, error) {
+func (r *dpRepo) listObjectClasses(ctx context.Context, currentView *model.ItemConfig) (model.ItemList, error) {
 	logging.LogDebugf("repo/dp/listObjectClasses(%v)", currentView)
 
 	if currentView.DpAppliance == "" {
@@ -2471,7 +3360,7 @@ func (r *dpRepo) listObjectClasses(currentView *model.ItemConfig) (model.ItemLis
 	case config.DpInterfaceRest:
 		listObjectStatusesURL := fmt.Sprintf("/mgmt/status/%s/ObjectStatus", currentView.DpDomain)
 		classNamesAndStatusesWithDuplicates, _, err =
-			r.restGetForListsResult(listObjectStatusesURL,
+			r.restGetForListsResult(ctx, listObjectStatusesURL,
 				"/ObjectStatus//Class", "/ObjectStatus//ConfigState")
 
 	case config.DpInterfaceSoma:
@@ -2485,7 +3374,7 @@ func (r *dpRepo) listObjectClasses(currentView *model.ItemConfig) (model.ItemLis
 	</soapenv:Body>
 </soapenv:Envelope>`, currentView.DpDomain)
 		var somaResponse string
-		somaResponse, err = r.soma(somaRequest)
+		somaResponse, err = r.soma(ctx, somaRequest)
 		if err != nil {
 			return nil, err
 		}
@@ -2543,7 +3432,7 @@ func (r *dpRepo) listObjectClasses(currentView *model.ItemConfig) (model.ItemLis
 }
 
 // listObjects lists all objects of selected class in current DataPower domain.
-func (r *dpRepo) listObjects(itemConfig *model.ItemConfig) (model.ItemList, error) {
+func (r *dpRepo) listObjects(ctx context.Context, itemConfig *model.ItemConfig) (model.ItemList, error) {
 	logging.LogDebugf("repo/dp/listObjects(%v)", itemConfig)
 
 	switch itemConfig.Type {
@@ -2564,7 +3453,7 @@ func (r *dpRepo) listObjects(itemConfig *model.ItemConfig) (model.ItemList, erro
 		// example: WebGUI (Name (status): "web-mgmt", name (config): "WebGUI-Settings").
 		listObjectStatusesURL := fmt.Sprintf("/mgmt/status/%s/ObjectStatus", itemConfig.DpDomain)
 		objectNamesAndStatuses, _, err :=
-			r.restGetForListsResult(listObjectStatusesURL,
+			r.restGetForListsResult(ctx, listObjectStatusesURL,
 				fmt.Sprintf("/ObjectStatus//Class[text()='%s']/../Name", objectClassName),
 				fmt.Sprintf("/ObjectStatus//Class[text()='%s']/../ConfigState", objectClassName),
 				fmt.Sprintf("/ObjectStatus//Class[text()='%s']/../OpState", objectClassName),
@@ -2577,7 +3466,7 @@ func (r *dpRepo) listObjects(itemConfig *model.ItemConfig) (model.ItemList, erro
 
 		listObjectsURL := fmt.Sprintf("/mgmt/config/%s/%s", itemConfig.DpDomain, objectClassName)
 		objectNameQuery := fmt.Sprintf("/%s//name", objectClassName)
-		objectNames, _, err := r.restGetForListResult(listObjectsURL, objectNameQuery)
+		objectNames, _, err := r.restGetForListResult(ctx, listObjectsURL, objectNameQuery)
 		if err != nil {
 			return nil, err
 		}
@@ -2648,11 +3537,11 @@ func (r *dpRepo) listObjects(itemConfig *model.ItemConfig) (model.ItemList, erro
 		</man:request>
 	</soapenv:Body>
 </soapenv:Envelope>`, itemConfig.DpDomain, objectClassName)
-		somaConfigResponse, err := r.soma(somaConfigRequest)
+		somaConfigResponse, err := r.soma(ctx, somaConfigRequest)
 		if err != nil {
 			return nil, err
 		}
-		somaStatusResponse, err := r.soma(somaStatusRequest)
+		somaStatusResponse, err := r.soma(ctx, somaStatusRequest)
 		if err != nil {
 			return nil, err
 		}
@@ -2720,7 +3609,7 @@ func (r *dpRepo) listObjects(itemConfig *model.ItemConfig) (model.ItemList, erro
 }
 
 // listStatusClasses lists all status classes used in current DataPower domain.
-func (r *dpRepo) listStatusClasses(currentView *model.ItemConfig) (model.ItemList, error) {
+func (r *dpRepo) listStatusClasses(ctx context.Context, currentView *model.ItemConfig) (model.ItemList, error) {
 	logging.LogDebugf("repo/dp/listStatusClasses(%v)", currentView)
 
 	if currentView.DpAppliance == "" {
@@ -2739,7 +3628,7 @@ func (r *dpRepo) listStatusClasses(currentView *model.ItemConfig) (model.ItemLis
 
 	switch r.dataPowerAppliance.DpManagmentInterface() {
 	case config.DpInterfaceRest:
-		responseJSON, err := r.restGet("/mgmt/status/")
+		responseJSON, err := r.restGet(ctx, "/mgmt/status/")
 		if err != nil {
 			return nil, err
 		}
@@ -2771,7 +3660,7 @@ func (r *dpRepo) listStatusClasses(currentView *model.ItemConfig) (model.ItemLis
 	</soapenv:Body>
 </soapenv:Envelope>`, currentView.DpDomain)
 		var somaResponse string
-		somaResponse, err = r.soma(somaRequest)
+		somaResponse, err = r.soma(ctx, somaRequest)
 		if err != nil {
 			return nil, err
 		}
@@ -2907,7 +3796,7 @@ func parseStatusFromXML(
 }
 
 // listStatuses lists all statuses of selected class in current DataPower domain.
-func (r *dpRepo) listStatuses(itemConfig *model.ItemConfig) (model.ItemList, error) {
+func (r *dpRepo) listStatuses(ctx context.Context, itemConfig *model.ItemConfig) (model.ItemList, error) {
 	logging.LogDebugf("repo/dp/listStatuses(%v)", itemConfig)
 
 	switch itemConfig.Type {
@@ -2922,7 +3811,7 @@ func (r *dpRepo) listStatuses(itemConfig *model.ItemConfig) (model.ItemList, err
 	case config.DpInterfaceRest:
 		getStatusesURL := fmt.Sprintf("/mgmt/status/%s/%s",
 			itemConfig.DpDomain, itemConfig.Path)
-		statusesRespJSON, err := r.restGet(getStatusesURL)
+		statusesRespJSON, err := r.restGet(ctx, getStatusesURL)
 		if err != nil {
 			if respErr, ok := err.(errs.UnexpectedHTTPResponse); ok && respErr.StatusCode == 404 {
 				return nil, nil
@@ -3002,7 +3891,7 @@ func (r *dpRepo) listStatuses(itemConfig *model.ItemConfig) (model.ItemList, err
 		</man:request>
 	</soapenv:Body>
 </soapenv:Envelope>`, itemConfig.DpDomain, itemConfig.Path)
-		doc, err := r.somaGetDoc(somaStatusRequest)
+		doc, err := r.somaGetDoc(ctx, somaStatusRequest)
 		if err != nil {
 			return nil, err
 		}
@@ -3042,10 +3931,10 @@ func (r *dpRepo) listStatuses(itemConfig *model.ItemConfig) (model.ItemList, err
 	}
 }
 
-func (r *dpRepo) refreshSomaFiles(viewConfig *model.ItemConfig) error {
-	return r.refreshSomaFilesByPath(viewConfig.DpDomain, viewConfig.Path)
+func (r *dpRepo) refreshSomaFiles(ctx context.Context, viewConfig *model.ItemConfig) error {
+	return r.refreshSomaFilesByPath(ctx, viewConfig.DpDomain, viewConfig.Path)
 }
-func (r *dpRepo) refreshSomaFilesByPath(dpDomain, path string) error {
+func (r *dpRepo) refreshSomaFilesByPath(ctx context.Context, dpDomain, path string) error {
 	if r.dataPowerAppliance.SomaUrl != "" {
 		filestoreEndIdx := strings.Index(path, ":")
 		if filestoreEndIdx == -1 {
@@ -3053,7 +3942,7 @@ func (r *dpRepo) refreshSomaFilesByPath(dpDomain, path string) error {
 		}
 
 		dpFilestoreLocation := path[:filestoreEndIdx] + ":"
-		err := r.fetchFilestoreIfNeeded(dpDomain, dpFilestoreLocation, true)
+		err := r.fetchFilestoreIfNeeded(ctx, dpDomain, dpFilestoreLocation, true)
 		return err
 	}
 
@@ -3071,21 +3960,21 @@ func (r *dpRepo) findItemConfigParentDomain(itemConfig *model.ItemConfig) *model
 	return r.findItemConfigParentDomain(itemConfig.Parent)
 }
 
-func (r *dpRepo) fetchDpDomains() ([]dpDomainInfo, error) {
+func (r *dpRepo) fetchDpDomains(ctx context.Context) ([]dpDomainInfo, error) {
 	logging.LogDebug("repo/dp/fetchDpDomains()")
 	domains := make([]dpDomainInfo, 0)
 
 	switch r.dataPowerAppliance.DpManagmentInterface() {
 	case config.DpInterfaceRest:
 		// Fetch config of all domains (so we can show if domain is enabled)
-		domainsConfigDoc, err := r.restGetDoc("/mgmt/config/default/Domain")
+		domainsConfigDoc, err := r.restGetDoc(ctx, "/mgmt/config/default/Domain")
 		if err != nil {
 			return nil, err
 		}
 		domainConfigList := jsonquery.Find(domainsConfigDoc, "/Domain/*")
 
 		// Fetch status of all domains (so we can show if domain is saved)
-		domainsStatusDoc, err := r.restGetDoc("/mgmt/status/default/DomainStatus")
+		domainsStatusDoc, err := r.restGetDoc(ctx, "/mgmt/status/default/DomainStatus")
 		if err != nil {
 			return nil, err
 		}
@@ -3114,7 +4003,7 @@ func (r *dpRepo) fetchDpDomains() ([]dpDomainInfo, error) {
 		</man:request>
 	</soapenv:Body>
 </soapenv:Envelope>`
-		domainsConfigDoc, err := r.somaGetDoc(somaConfigRequest)
+		domainsConfigDoc, err := r.somaGetDoc(ctx, somaConfigRequest)
 		if err != nil {
 			return nil, err
 		}
@@ -3130,7 +4019,7 @@ func (r *dpRepo) fetchDpDomains() ([]dpDomainInfo, error) {
 		</man:request>
 	</soapenv:Body>
 </soapenv:Envelope>`
-		domainsStatusDoc, err := r.somaGetDoc(somaStatusRequest)
+		domainsStatusDoc, err := r.somaGetDoc(ctx, somaStatusRequest)
 		if err != nil {
 			return nil, err
 		}
@@ -3160,8 +4049,8 @@ func (r *dpRepo) fetchDpDomains() ([]dpDomainInfo, error) {
 	return domains, nil
 }
 
-func (r *dpRepo) restPostForResult(urlPath, postBody, checkQuery, checkExpected, resultQuery string) (result, responseJSON string, err error) {
-	responseJSON, err = r.rest(urlPath, "POST", postBody)
+func (r *dpRepo) restPostForResult(ctx context.Context, urlPath, postBody, checkQuery, checkExpected, resultQuery string) (result, responseJSON string, err error) {
+	responseJSON, err = r.rest(ctx, urlPath, "POST", postBody)
 	if err != nil {
 		return "", "", err
 	}
@@ -3194,8 +4083,8 @@ func (r *dpRepo) restPostForResult(urlPath, postBody, checkQuery, checkExpected,
 	return result, responseJSON, nil
 }
 
-func (r *dpRepo) restGetForOneResult(urlPath, resultQuery string) (result, responseJSON string, err error) {
-	responseJSON, err = r.restGet(urlPath)
+func (r *dpRepo) restGetForOneResult(ctx context.Context, urlPath, resultQuery string) (result, responseJSON string, err error) {
+	responseJSON, err = r.restGet(ctx, urlPath)
 	if err != nil {
 		return "", "", err
 	}
@@ -3223,8 +4112,8 @@ func parseJSONFindOne(json, query string) (string, error) {
 }
 
 // restGetForListResult makes REST call and parses JSON response.
-func (r *dpRepo) restGetForListResult(urlPath, resultQuery string) (result []string, responseJSON string, err error) {
-	responseJSON, err = r.restGet(urlPath)
+func (r *dpRepo) restGetForListResult(ctx context.Context, urlPath, resultQuery string) (result []string, responseJSON string, err error) {
+	responseJSON, err = r.restGet(ctx, urlPath)
 	if err != nil {
 		return nil, "", err
 	}
@@ -3235,8 +4124,8 @@ func (r *dpRepo) restGetForListResult(urlPath, resultQuery string) (result []str
 }
 
 // restGetForListsResult makes REST call and parses JSON response multiple times.
-func (r *dpRepo) restGetForListsResult(urlPath string, resultQueries ...string) (results [][]string, responseJSON string, err error) {
-	responseJSON, err = r.restGet(urlPath)
+func (r *dpRepo) restGetForListsResult(ctx context.Context, urlPath string, resultQueries ...string) (results [][]string, responseJSON string, err error) {
+	responseJSON, err = r.restGet(ctx, urlPath)
 	if err != nil {
 		return nil, "", err
 	}
@@ -3519,28 +4408,24 @@ func (r *dpRepo) InitNetworkSettings(applianceName string,
 	dpa config.DataPowerAppliance) error {
 	logging.LogDebugf("repo/dp/InitNetworkSettings(%v)", dpa)
 	r.dataPowerAppliance = dpApplicance{name: applianceName, DataPowerAppliance: dpa}
-	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-	if r.dataPowerAppliance.Proxy != "" {
-		proxyURL, err := url.Parse(r.dataPowerAppliance.Proxy)
-		if err != nil {
-			logging.LogDebug("Couldn't initialize network settings to access DataPower.", err)
-			return err
-		}
-		http.DefaultTransport.(*http.Transport).Proxy = http.ProxyURL(proxyURL)
+	// Prepare HTTP client (with its proxy & TLS settings) used for the appliance.
+	_, err := httpClient(r.dataPowerAppliance)
+	if err != nil {
+		logging.LogDebug("Couldn't initialize network settings to access DataPower.", err)
 	}
-	return nil
+	return err
 }
 
 // rest makes http request from relative URL path given, method and body.
-func (r *dpRepo) rest(urlPath, method, body string) (string, error) {
+func (r *dpRepo) rest(ctx context.Context, urlPath, method, body string) (string, error) {
 	fullURL := r.dataPowerAppliance.RestUrl + urlPath
-	return r.httpRequest(fullURL, method, body)
+	return r.httpRequest(ctx, fullURL, method, body)
 }
 
 // restGetDoc makes DataPower REST GET request and returns parsed JSON doc.
-func (r *dpRepo) restGetDoc(urlPath string) (*jsonquery.Node, error) {
+func (r *dpRepo) restGetDoc(ctx context.Context, urlPath string) (*jsonquery.Node, error) {
 	logging.LogDebugf("repo/dp/restGetDoc('%s')", urlPath)
-	bodyString, err := r.restGet(urlPath)
+	bodyString, err := r.restGet(ctx, urlPath)
 	if err != nil {
 		return nil, err
 	}
@@ -3554,24 +4439,24 @@ func (r *dpRepo) restGetDoc(urlPath string) (*jsonquery.Node, error) {
 }
 
 // restGet makes DataPower REST GET request.
-func (r *dpRepo) restGet(urlPath string) (string, error) {
-	return r.rest(urlPath, "GET", "")
+func (r *dpRepo) restGet(ctx context.Context, urlPath string) (string, error) {
+	return r.rest(ctx, urlPath, "GET", "")
 }
 
 // amp makes DataPower AMP request.
-func (r *dpRepo) amp(body string) (string, error) {
-	return r.httpRequest(r.dataPowerAppliance.SomaUrl+"/service/mgmt/amp/1.0", "POST", body)
+func (r *dpRepo) amp(ctx context.Context, body string) (string, error) {
+	return r.httpRequest(ctx, r.dataPowerAppliance.SomaUrl+"/service/mgmt/amp/1.0", "POST", body)
 }
 
 // soma makes DataPower SOMA request.
-func (r *dpRepo) soma(body string) (string, error) {
-	return r.httpRequest(r.dataPowerAppliance.SomaUrl+"/service/mgmt/current", "POST", body)
+func (r *dpRepo) soma(ctx context.Context, body string) (string, error) {
+	return r.httpRequest(ctx, r.dataPowerAppliance.SomaUrl+"/service/mgmt/current", "POST", body)
 }
 
 // somaGetDoc makes DataPower SOMA request and returns parsed XML doc.
-func (r *dpRepo) somaGetDoc(body string) (*xmlquery.Node, error) {
+func (r *dpRepo) somaGetDoc(ctx context.Context, body string) (*xmlquery.Node, error) {
 	logging.LogDebugf("repo/dp/somaGetDoc('%s')", body)
-	bodyString, err := r.soma(body)
+	bodyString, err := r.soma(ctx, body)
 	if err != nil {
 		return nil, err
 	}
@@ -3589,22 +4474,52 @@ func (r *dpRepo) somaGetDoc(body string) (*xmlquery.Node, error) {
 }
 
 type requester interface {
-	httpRequest(dpa dpApplicance, urlFullPath, method, body string) (string, error)
+	httpRequest(ctx context.Context, dpa dpApplicance, urlFullPath, method, body string) (string, error)
 }
 
 type netRequester struct{}
 
-func (nr netRequester) httpRequest(dpa dpApplicance, urlFullPath, method, body string) (string, error) {
-	logging.LogTracef("repo/dp/httpRequest(%s, %s, '%s')", urlFullPath, method, body)
+// retryDelay is time waited before first retry of failed GET request, each
+// next retry waits longer.
+var retryDelay = 1 * time.Second
+
+// httpRequest makes HTTP request, GET requests which fail because of network
+// errors or because DataPower is temporarily unavailable are retried up to
+// configured number of times.
+func (nr netRequester) httpRequest(ctx context.Context, dpa dpApplicance, urlFullPath, method, body string) (string, error) {
+	retries := 0
+	if method == "GET" {
+		retries = config.Conf.Net.Retries
+	}
+	for attempt := 0; ; attempt++ {
+		result, err := nr.httpRequestOnce(ctx, dpa, urlFullPath, method, body)
+		if err == nil || attempt >= retries || !retryableError(err) {
+			return result, err
+		}
+		logging.LogDebugf("repo/dp/httpRequest() - Retrying HTTP %s call to '%s' (attempt %d) after error: %v",
+			method, urlFullPath, attempt+1, err)
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(retryDelay * time.Duration(attempt+1)):
+		}
+	}
+}
 
-	client := &http.Client{}
+func (nr netRequester) httpRequestOnce(ctx context.Context, dpa dpApplicance, urlFullPath, method, body string) (string, error) {
+	logging.LogTracef("repo/dp/httpRequestOnce(%s, %s, '%s')", urlFullPath, method, body)
+
+	client, err := httpClient(dpa)
+	if err != nil {
+		return "", err
+	}
 	var bodyReader io.Reader
 	if body != "" {
 		bodyReader = strings.NewReader(body)
 	}
-	req, err := http.NewRequest(method, urlFullPath, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, urlFullPath, bodyReader)
 	if err != nil {
-		logging.LogDebug("repo/dp/httpRequest() - Can't prepare request: ", err)
+		logging.LogDebug("repo/dp/httpRequestOnce() - Can't prepare request: ", err)
 		return "", err
 	}
 
@@ -3612,7 +4527,18 @@ func (nr netRequester) httpRequest(dpa dpApplicance, urlFullPath, method, body s
 	resp, err := client.Do(req)
 
 	if err != nil {
-		logging.LogDebug("repo/dp/httpRequest() - Can't send request: ", err)
+		logging.LogDebug("repo/dp/httpRequestOnce() - Can't send request: ", err)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		var certErr CertificateError
+		if errors.As(err, &certErr) {
+			return "", certErr
+		}
+		var verifyErr *tls.CertificateVerificationError
+		if errors.As(err, &verifyErr) {
+			return "", verificationError(dpa, verifyErr)
+		}
 		return "", err
 		// 2019/10/22 08:39:14 dp Can't send request: Post https://10.123.56.55:5550/service/mgmt/current: dial tcp 10.123.56.55:5550: i/o timeout
 		//exit status 1
@@ -3623,24 +4549,44 @@ func (nr netRequester) httpRequest(dpa dpApplicance, urlFullPath, method, body s
 		resp.StatusCode == http.StatusCreated {
 		bodyBytes, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
-			logging.LogDebug("repo/dp/httpRequest() - Can't read response: ", err)
+			logging.LogDebug("repo/dp/httpRequestOnce() - Can't read response: ", err)
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
 			return "", err
 		}
-		logging.LogTracef("repo/dp/httpRequest() - httpResponse: '%s'", string(bodyBytes))
+		logging.LogTracef("repo/dp/httpRequestOnce() - httpResponse: '%s'", string(bodyBytes))
 		return string(bodyBytes), nil
 	}
-	// logging.LogTracef("repo/dp/httpRequest() - resp.StatusCode: '%d'", resp.StatusCode)
+	// logging.LogTracef("repo/dp/httpRequestOnce() - resp.StatusCode: '%d'", resp.StatusCode)
 	// if resp.StatusCode == 403 || resp.StatusCode == 404 {
 	// 	return ""
 	// }
-	logging.LogDebugf("repo/dp/httpRequest() - HTTP %s call to '%s' returned HTTP StatusCode %v (%s)",
+	logging.LogDebugf("repo/dp/httpRequestOnce() - HTTP %s call to '%s' returned HTTP StatusCode %v (%s)",
 		method, urlFullPath, resp.StatusCode, resp.Status)
 	return "", errs.UnexpectedHTTPResponse{StatusCode: resp.StatusCode, Status: resp.Status}
 }
 
+// retryableError checks if failed request should be retried - it can succeed
+// if network or DataPower is temporarily unavailable.
+func retryableError(err error) bool {
+	switch err := err.(type) {
+	case CertificateError:
+		return false
+	case errs.UnexpectedHTTPResponse:
+		return err.StatusCode == http.StatusBadGateway ||
+			err.StatusCode == http.StatusServiceUnavailable ||
+			err.StatusCode == http.StatusGatewayTimeout
+	case *url.Error:
+		return true
+	default:
+		return false
+	}
+}
+
 // httpRequest makes DataPower HTTP request.
-func (r *dpRepo) httpRequest(urlFullPath, method, body string) (string, error) {
-	return r.req.httpRequest(r.dataPowerAppliance, urlFullPath, method, body)
+func (r *dpRepo) httpRequest(ctx context.Context, urlFullPath, method, body string) (string, error) {
+	return r.req.httpRequest(ctx, r.dataPowerAppliance, urlFullPath, method, body)
 }
 
 // makeRestPath creates DataPower REST path to given domain.
@@ -2,6 +2,7 @@ package dp
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"io/ioutil"
@@ -31,7 +32,42 @@ func TestString(t *testing.T) {
 	clearRepo()
 
 	assert.Equals(t, "Normal DataPower repo", Repo.String(), "DataPower")
-	assert.Equals(t, "Sync DataPower repo", SyncRepo.String(), "SyncDataPower")
+	assert.Equals(t, "Sync DataPower repo", SyncRepo("xsl").String(), "SyncDataPower")
+}
+
+func TestSyncRepo(t *testing.T) {
+	xslRepo := SyncRepo("xsl")
+	assert.True(t, "SyncRepo same session", SyncRepo("xsl") == xslRepo)
+	assert.True(t, "SyncRepo other session", SyncRepo("gws") != xslRepo)
+	DeleteSyncRepo("xsl")
+	assert.True(t, "SyncRepo after delete", SyncRepo("xsl") != xslRepo)
+}
+
+func TestCopy(t *testing.T) {
+	clearRepo()
+	Repo.dataPowerAppliance.RestUrl = testRestURL
+	Repo.DpViewMode = model.DpObjectMode
+	Repo.dpFilestoreXmls["local:"] = "<xml/>"
+
+	repoCopy := Repo.Copy()
+	assert.True(t, "Copy", repoCopy != &Repo)
+	assert.Equals(t, "Copy name", repoCopy.String(), "DataPower")
+	assert.Equals(t, "Copy appliance", repoCopy.dataPowerAppliance.RestUrl, testRestURL)
+	assert.Equals(t, "Copy view mode", repoCopy.DpViewMode, model.DpObjectMode)
+	assert.Equals(t, "Copy cache", len(repoCopy.dpFilestoreXmls), 0)
+	repoCopy.DpViewMode = model.DpStatusMode
+	assert.Equals(t, "Original view mode", Repo.DpViewMode, model.DpObjectMode)
+}
+
+func TestCopyRepo(t *testing.T) {
+	clearRepo()
+	Repo.dataPowerAppliance.RestUrl = testRestURL
+
+	repoCopy, ok := CopyRepo(&Repo).(*dpRepo)
+	assert.True(t, "CopyRepo type", ok)
+	assert.True(t, "CopyRepo", repoCopy != &Repo)
+	assert.Equals(t, "CopyRepo appliance", repoCopy.dataPowerAppliance.RestUrl, testRestURL)
+	assert.Equals(t, "CopyRepo other repo", CopyRepo(nil), nil)
 }
 
 func TestGetInitialItem(t *testing.T) {
@@ -147,7 +183,7 @@ func TestGetList(t *testing.T) {
 		Repo.req = mockRequester{}
 
 		itemToShow := model.ItemConfig{Type: model.ItemDpObjectClassList}
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("Internal error showing object config mode - missing dp appliance."))
@@ -155,7 +191,7 @@ func TestGetList(t *testing.T) {
 
 		itemToShow.DpAppliance = "MyApplianceName"
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("Internal error showing object config mode - missing domain."))
@@ -163,7 +199,7 @@ func TestGetList(t *testing.T) {
 
 		itemToShow.DpDomain = "MyDomain"
 		itemToShow.Type = model.ItemDirectory
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("Internal error showing object config mode - wrong view type."))
@@ -187,7 +223,7 @@ func TestGetList(t *testing.T) {
 			DpFilestore: "local:",
 			Path:        "Object classes"}
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 43)
@@ -220,7 +256,7 @@ func TestGetList(t *testing.T) {
 		}
 
 		itemToShow.Type = model.ItemDirectory
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("Internal error showing object config mode - wrong view type."))
@@ -242,7 +278,7 @@ func TestGetList(t *testing.T) {
 			DpAppliance: "MyApplianceName",
 			DpDomain:    "MyDomain", DpFilestore: "local:", Path: "Object classes"}
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 43)
@@ -290,7 +326,7 @@ func TestGetList(t *testing.T) {
 			DpAppliance: "MyApplianceName",
 			DpDomain:    "MyDomain", DpFilestore: "local:", Path: "XMLFirewallService"}
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 9)
@@ -346,7 +382,7 @@ func TestGetList(t *testing.T) {
 			DpAppliance: "MyApplianceName",
 			DpDomain:    "MyDomain", DpFilestore: "local:", Path: "XMLFirewallService"}
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 9)
@@ -399,7 +435,7 @@ func TestGetList(t *testing.T) {
 		Repo.req = mockRequester{}
 
 		itemToShow := model.ItemConfig{Type: model.ItemDpStatusClassList}
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("Internal error showing status config mode - missing dp appliance."))
@@ -407,7 +443,7 @@ func TestGetList(t *testing.T) {
 
 		itemToShow.DpAppliance = "MyApplianceName"
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("Internal error showing status config mode - missing domain."))
@@ -415,7 +451,7 @@ func TestGetList(t *testing.T) {
 
 		itemToShow.DpDomain = "MyDomain"
 		itemToShow.Type = model.ItemDirectory
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("Internal error showing status config mode - wrong view type: s."))
@@ -440,7 +476,7 @@ func TestGetList(t *testing.T) {
 			Path:        "Status classes"}
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
 
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 147)
@@ -480,7 +516,7 @@ func TestGetList(t *testing.T) {
 		}
 
 		itemToShow.Type = model.ItemDirectory
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("Internal error showing status config mode - wrong view type: s."))
@@ -502,7 +538,7 @@ func TestGetList(t *testing.T) {
 			DpAppliance: "MyApplianceName",
 			DpDomain:    "MyDomain", DpFilestore: "local:", Path: "Status classes"}
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 64)
@@ -578,7 +614,7 @@ func TestGetList(t *testing.T) {
 			DpAppliance: "MyApplianceName",
 			DpDomain:    "MyDomain", DpFilestore: "local:", Path: "StylesheetCachingSummary"}
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 4)
@@ -624,7 +660,7 @@ func TestGetList(t *testing.T) {
 			DpAppliance: "MyApplianceName",
 			DpDomain:    "MyDomain", DpFilestore: "local:", Path: "ActiveUsers"}
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 9)
@@ -664,7 +700,7 @@ func TestGetList(t *testing.T) {
 			DpAppliance: "MyApplianceName",
 			DpDomain:    "MyDomain", DpFilestore: "local:", Path: "StylesheetCachingSummary"}
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 4)
@@ -710,7 +746,7 @@ func TestGetList(t *testing.T) {
 			DpAppliance: "MyApplianceName",
 			DpDomain:    "MyDomain", DpFilestore: "local:", Path: "CryptoEngineStatus2"}
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 2)
@@ -744,7 +780,7 @@ func TestGetList(t *testing.T) {
 			DpAppliance: "MyApplianceName",
 			DpDomain:    "MyDomain", DpFilestore: "local:", Path: "ActiveUsers"}
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 9)
@@ -778,7 +814,7 @@ func TestGetList(t *testing.T) {
 		}
 
 		itemToShow := model.ItemConfig{Type: model.ItemNone}
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("No appliances found, have to configure dpcmder with command line params first."))
@@ -796,7 +832,7 @@ func TestGetList(t *testing.T) {
 		config.Conf.DataPowerAppliances["dpa1"] = dpa1
 		config.Conf.DataPowerAppliances["dpa2"] = dpa2
 
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 2)
@@ -825,7 +861,7 @@ func TestGetList(t *testing.T) {
 		Repo.req = mockRequester{}
 
 		itemToShow := model.ItemConfig{Type: model.ItemDpConfiguration}
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("DataPower management interface not set."))
@@ -839,7 +875,7 @@ func TestGetList(t *testing.T) {
 		itemToShow.DpAppliance = "MyApplianceName"
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
 
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 3)
@@ -872,7 +908,7 @@ func TestGetList(t *testing.T) {
 		Repo.req = mockRequester{}
 
 		itemToShow := model.ItemConfig{Type: model.ItemDpConfiguration}
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("DataPower management interface not set."))
@@ -886,7 +922,7 @@ func TestGetList(t *testing.T) {
 		itemToShow.DpAppliance = "MyApplianceName"
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
 
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 3)
@@ -920,7 +956,7 @@ func TestGetList(t *testing.T) {
 
 		itemToShow := model.ItemConfig{Type: model.ItemDpConfiguration,
 			DpDomain: "test"}
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("DataPower management interface not set."))
@@ -934,7 +970,7 @@ func TestGetList(t *testing.T) {
 		itemToShow.DpAppliance = "MyApplianceName"
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
 
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 13)
@@ -978,7 +1014,7 @@ func TestGetList(t *testing.T) {
 
 		itemToShow := model.ItemConfig{Type: model.ItemDpConfiguration,
 			DpDomain: "test"}
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("DataPower management interface not set."))
@@ -992,7 +1028,7 @@ func TestGetList(t *testing.T) {
 		itemToShow.DpAppliance = "MyApplianceName"
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
 
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 13)
@@ -1036,7 +1072,7 @@ func TestGetList(t *testing.T) {
 
 		itemToShow := model.ItemConfig{Type: model.ItemDpDomain,
 			DpDomain: "test"}
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("DataPower management interface not set."))
@@ -1050,7 +1086,7 @@ func TestGetList(t *testing.T) {
 		itemToShow.DpAppliance = "MyApplianceName"
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
 
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 13)
@@ -1094,7 +1130,7 @@ func TestGetList(t *testing.T) {
 
 		itemToShow := model.ItemConfig{Type: model.ItemDpDomain,
 			DpDomain: "test"}
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("DataPower management interface not set."))
@@ -1108,7 +1144,7 @@ func TestGetList(t *testing.T) {
 		itemToShow.DpAppliance = "MyApplianceName"
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
 
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 13)
@@ -1152,7 +1188,7 @@ func TestGetList(t *testing.T) {
 
 		itemToShow := model.ItemConfig{Type: model.ItemDpFilestore,
 			DpDomain: "test", DpFilestore: "store:", Path: "store:"}
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("DataPower management interface not set."))
@@ -1166,7 +1202,7 @@ func TestGetList(t *testing.T) {
 		itemToShow.DpAppliance = "MyApplianceName"
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
 
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 189)
@@ -1215,7 +1251,7 @@ func TestGetList(t *testing.T) {
 
 		itemToShow := model.ItemConfig{Type: model.ItemDpFilestore,
 			DpDomain: "test", DpFilestore: "store:", Path: "store:"}
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("DataPower management interface not set."))
@@ -1229,7 +1265,7 @@ func TestGetList(t *testing.T) {
 		itemToShow.DpAppliance = "MyApplianceName"
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
 
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 191)
@@ -1278,7 +1314,7 @@ func TestGetList(t *testing.T) {
 
 		itemToShow := model.ItemConfig{Type: model.ItemDirectory,
 			DpDomain: "test", DpFilestore: "store:", Path: "store:/gatewayscript"}
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("DataPower management interface not set."))
@@ -1292,7 +1328,7 @@ func TestGetList(t *testing.T) {
 		itemToShow.DpAppliance = "MyApplianceName"
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
 
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 30)
@@ -1334,7 +1370,7 @@ func TestGetList(t *testing.T) {
 
 		itemToShow := model.ItemConfig{Type: model.ItemDirectory,
 			DpDomain: "test", DpFilestore: "store:", Path: "store:/gatewayscript"}
-		itemList, err := Repo.GetList(&itemToShow)
+		itemList, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("DataPower management interface not set."))
@@ -1348,7 +1384,7 @@ func TestGetList(t *testing.T) {
 		itemToShow.DpAppliance = "MyApplianceName"
 		config.Conf.DataPowerAppliances[itemToShow.DpAppliance] = dpa
 
-		itemList, err = Repo.GetList(&itemToShow)
+		itemList, err = Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err, nil)
 		assert.Equals(t, "GetList", len(itemList), 31)
@@ -1390,7 +1426,7 @@ func TestGetList(t *testing.T) {
 
 		itemToShow := model.ItemConfig{Type: model.ItemDpObjectClassList,
 			DpDomain: "test", DpFilestore: "store:", Path: "store:/gatewayscript"}
-		_, err := Repo.GetList(&itemToShow)
+		_, err := Repo.GetList(context.Background(), &itemToShow)
 
 		assert.Equals(t, "GetList", err,
 			errs.Error("Internal error showing filestore mode - wrong view type."))
@@ -1421,7 +1457,7 @@ func TestGetFile(t *testing.T) {
 		dpa := config.DataPowerAppliance{}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		fileBytesGot, err := Repo.GetFile(&currentView, "non-existing-file.js")
+		fileBytesGot, err := Repo.GetFile(context.Background(), &currentView, "non-existing-file.js")
 		assert.Equals(t, "GetFile", err, errs.Error("DataPower management interface not set."))
 		assert.Nil(t, "GetFile", fileBytesGot)
 	})
@@ -1433,7 +1469,7 @@ func TestGetFile(t *testing.T) {
 		fileBytesWant, err := ioutil.ReadFile("testdata/example-context.js")
 		assert.Nil(t, "GetFile", err)
 		assert.NotNil(t, "GetFile/Setup", fileBytesWant)
-		fileBytesGot, err := Repo.GetFile(&currentView, "example-context.js")
+		fileBytesGot, err := Repo.GetFile(context.Background(), &currentView, "example-context.js")
 		assert.Nil(t, "GetFile", err)
 		assert.Equals(t, "GetFile", fileBytesGot, fileBytesWant)
 	})
@@ -1442,7 +1478,7 @@ func TestGetFile(t *testing.T) {
 		dpa := config.DataPowerAppliance{RestUrl: testRestURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		fileBytesGot, err := Repo.GetFile(&currentView, "non-existing-file.js")
+		fileBytesGot, err := Repo.GetFile(context.Background(), &currentView, "non-existing-file.js")
 		assert.NotNil(t, "GetFile", err)
 		assert.Equals(t, "GetFile", err, errs.Error("Unexpected JSON, can't find '/file'."))
 		assert.Nil(t, "GetFile", fileBytesGot)
@@ -1452,7 +1488,7 @@ func TestGetFile(t *testing.T) {
 		dpa := config.DataPowerAppliance{RestUrl: testRestURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		fileBytesGot, err := Repo.GetFile(&currentView, "b64-err-file.txt")
+		fileBytesGot, err := Repo.GetFile(context.Background(), &currentView, "b64-err-file.txt")
 		assert.NotNil(t, "GetFile", err)
 		assert.DeepEqual(t, "GetFile", err, base64.CorruptInputError(3))
 		assert.Nil(t, "GetFile", fileBytesGot)
@@ -1465,7 +1501,7 @@ func TestGetFile(t *testing.T) {
 		fileBytesWant, err := ioutil.ReadFile("testdata/example-context.js")
 		assert.Nil(t, "GetFile", err)
 		assert.NotNil(t, "GetFile/Setup", fileBytesWant)
-		fileBytesGot, err := Repo.GetFile(&currentView, "example-context.js")
+		fileBytesGot, err := Repo.GetFile(context.Background(), &currentView, "example-context.js")
 		assert.Nil(t, "GetFile", err)
 		assert.Equals(t, "GetFile", fileBytesGot, fileBytesWant)
 	})
@@ -1474,7 +1510,7 @@ func TestGetFile(t *testing.T) {
 		dpa := config.DataPowerAppliance{SomaUrl: testSomaURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		fileBytesGot, err := Repo.GetFile(&currentView, "non-existing-file.js")
+		fileBytesGot, err := Repo.GetFile(context.Background(), &currentView, "non-existing-file.js")
 		assert.NotNil(t, "GetFile", err)
 		assert.Equals(t, "GetFile", err, errs.Error("Can't find file 'store:/gatewayscript/non-existing-file.js' from SOMA response."))
 		assert.Nil(t, "GetFile", fileBytesGot)
@@ -1484,7 +1520,7 @@ func TestGetFile(t *testing.T) {
 		dpa := config.DataPowerAppliance{SomaUrl: testSomaURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		fileBytesGot, err := Repo.GetFile(&currentView, "b64-err-file.txt")
+		fileBytesGot, err := Repo.GetFile(context.Background(), &currentView, "b64-err-file.txt")
 		assert.NotNil(t, "GetFile", err)
 		assert.DeepEqual(t, "GetFile", err, base64.CorruptInputError(3))
 		assert.Nil(t, "GetFile", fileBytesGot)
@@ -1500,7 +1536,7 @@ func TestUpdateFile(t *testing.T) {
 		dpa := config.DataPowerAppliance{}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		res, err := Repo.UpdateFile(&currentView, "test-file.txt", []byte("Hello World!"))
+		res, err := Repo.UpdateFile(context.Background(), &currentView, "test-file.txt", []byte("Hello World!"))
 		assert.Equals(t, "UpdateFile", err, errs.Error("DataPower management interface not set."))
 		assert.False(t, "UpdateFile", res)
 	})
@@ -1509,7 +1545,7 @@ func TestUpdateFile(t *testing.T) {
 		dpa := config.DataPowerAppliance{RestUrl: testRestURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		res, err := Repo.UpdateFile(&currentView, "test-new-file.txt", []byte("Hello World!"))
+		res, err := Repo.UpdateFile(context.Background(), &currentView, "test-new-file.txt", []byte("Hello World!"))
 		assert.Nil(t, "UpdateFile", err)
 		assert.True(t, "UpdateFile", res)
 	})
@@ -1518,7 +1554,7 @@ func TestUpdateFile(t *testing.T) {
 		dpa := config.DataPowerAppliance{RestUrl: testRestURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		res, err := Repo.UpdateFile(&currentView, "test-existing-file.txt", []byte("Hello World!"))
+		res, err := Repo.UpdateFile(context.Background(), &currentView, "test-existing-file.txt", []byte("Hello World!"))
 		assert.Nil(t, "UpdateFile", err)
 		assert.True(t, "UpdateFile", res)
 	})
@@ -1527,7 +1563,7 @@ func TestUpdateFile(t *testing.T) {
 		dpa := config.DataPowerAppliance{RestUrl: testRestURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		res, err := Repo.UpdateFile(&currentView, "test-existing-dir", []byte("Hello World!"))
+		res, err := Repo.UpdateFile(context.Background(), &currentView, "test-existing-dir", []byte("Hello World!"))
 		assert.Equals(t, "UpdateFile", err,
 			errs.Error("Can't upload file 'local:/upload/test-existing-dir', directory with same name exists."))
 		assert.False(t, "UpdateFile", res)
@@ -1537,7 +1573,7 @@ func TestUpdateFile(t *testing.T) {
 		dpa := config.DataPowerAppliance{SomaUrl: testSomaURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		res, err := Repo.UpdateFile(&currentView, "test-new-file.txt", []byte("Hello World!"))
+		res, err := Repo.UpdateFile(context.Background(), &currentView, "test-new-file.txt", []byte("Hello World!"))
 		assert.Nil(t, "UpdateFile", err)
 		assert.True(t, "UpdateFile", res)
 	})
@@ -1546,7 +1582,7 @@ func TestUpdateFile(t *testing.T) {
 		dpa := config.DataPowerAppliance{SomaUrl: testSomaURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		res, err := Repo.UpdateFile(&currentView, "test-existing-dir", []byte("Hello World!"))
+		res, err := Repo.UpdateFile(context.Background(), &currentView, "test-existing-dir", []byte("Hello World!"))
 		assert.Equals(t, "UpdateFile", err,
 			errs.Error("Can't upload file 'local:/upload/test-existing-dir', directory with same name exists."))
 		assert.False(t, "UpdateFile", res)
@@ -1563,7 +1599,7 @@ func TestGetFileType(t *testing.T) {
 		dpa := config.DataPowerAppliance{}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		itemType, err := Repo.GetFileType(&currentView, "local:", "test-file.txt")
+		itemType, err := Repo.GetFileType(context.Background(), &currentView, "local:", "test-file.txt")
 		assert.Equals(t, "GetFileType", err, errs.Error("DataPower management interface not set."))
 		assert.Equals(t, "GetFileType", itemType, model.ItemNone)
 	})
@@ -1572,7 +1608,7 @@ func TestGetFileType(t *testing.T) {
 		dpa := config.DataPowerAppliance{RestUrl: testRestURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		itemType, err := Repo.GetFileType(&currentView, "store:/gatewayscript", "non-existing-file.js")
+		itemType, err := Repo.GetFileType(context.Background(), &currentView, "store:/gatewayscript", "non-existing-file.js")
 		assert.Nil(t, "GetFileType", err)
 		assert.Equals(t, "GetFileType", itemType, model.ItemNone)
 	})
@@ -1581,7 +1617,7 @@ func TestGetFileType(t *testing.T) {
 		dpa := config.DataPowerAppliance{RestUrl: testRestURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		itemType, err := Repo.GetFileType(&currentView, "local:/upload", "test-existing-file.txt")
+		itemType, err := Repo.GetFileType(context.Background(), &currentView, "local:/upload", "test-existing-file.txt")
 		assert.Nil(t, "GetFileType", err)
 		assert.Equals(t, "GetFileType", itemType, model.ItemFile)
 	})
@@ -1590,7 +1626,7 @@ func TestGetFileType(t *testing.T) {
 		dpa := config.DataPowerAppliance{RestUrl: testRestURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		itemType, err := Repo.GetFileType(&currentView, "local:/upload", "test-existing-dir")
+		itemType, err := Repo.GetFileType(context.Background(), &currentView, "local:/upload", "test-existing-dir")
 		assert.Nil(t, "GetFileType", err)
 		assert.Equals(t, "GetFileType", itemType, model.ItemDirectory)
 	})
@@ -1599,7 +1635,7 @@ func TestGetFileType(t *testing.T) {
 		dpa := config.DataPowerAppliance{RestUrl: testRestURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		itemType, err := Repo.GetFileType(&currentView, "", "store:")
+		itemType, err := Repo.GetFileType(context.Background(), &currentView, "", "store:")
 		assert.Nil(t, "GetFileType", err)
 		assert.Equals(t, "GetFileType", itemType, model.ItemDpFilestore)
 	})
@@ -1608,7 +1644,7 @@ func TestGetFileType(t *testing.T) {
 		dpa := config.DataPowerAppliance{RestUrl: testRestURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		itemType, err := Repo.GetFileType(&currentView, "store:/gatewayscript", "non-existing-file-404.js")
+		itemType, err := Repo.GetFileType(context.Background(), &currentView, "store:/gatewayscript", "non-existing-file-404.js")
 		assert.Nil(t, "GetFileType", err)
 		assert.Equals(t, "GetFileType", itemType, model.ItemNone)
 	})
@@ -1617,7 +1653,7 @@ func TestGetFileType(t *testing.T) {
 		dpa := config.DataPowerAppliance{SomaUrl: testSomaURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		itemType, err := Repo.GetFileType(&currentView, "store:/gatewayscript", "non-existing-file.js")
+		itemType, err := Repo.GetFileType(context.Background(), &currentView, "store:/gatewayscript", "non-existing-file.js")
 		assert.Nil(t, "GetFileType", err)
 		assert.Equals(t, "GetFileType", itemType, model.ItemNone)
 	})
@@ -1626,7 +1662,7 @@ func TestGetFileType(t *testing.T) {
 		dpa := config.DataPowerAppliance{SomaUrl: testSomaURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		itemType, err := Repo.GetFileType(&currentView, "local:/upload", "test-existing-file.txt")
+		itemType, err := Repo.GetFileType(context.Background(), &currentView, "local:/upload", "test-existing-file.txt")
 		assert.Nil(t, "GetFileType", err)
 		assert.Equals(t, "GetFileType", itemType, model.ItemFile)
 	})
@@ -1635,7 +1671,7 @@ func TestGetFileType(t *testing.T) {
 		dpa := config.DataPowerAppliance{SomaUrl: testSomaURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		itemType, err := Repo.GetFileType(&currentView, "local:/upload", "test-existing-dir")
+		itemType, err := Repo.GetFileType(context.Background(), &currentView, "local:/upload", "test-existing-dir")
 		assert.Nil(t, "GetFileType", err)
 		assert.Equals(t, "GetFileType", itemType, model.ItemDirectory)
 	})
@@ -1644,7 +1680,7 @@ func TestGetFileType(t *testing.T) {
 		dpa := config.DataPowerAppliance{SomaUrl: testSomaURL}
 		config.Conf.DataPowerAppliances[currentView.DpAppliance] = dpa
 
-		itemType, err := Repo.GetFileType(&currentView, "", "store:")
+		itemType, err := Repo.GetFileType(context.Background(), &currentView, "", "store:")
 		assert.Nil(t, "GetFileType", err)
 		assert.Equals(t, "GetFileType", itemType, model.ItemDpFilestore)
 	})
@@ -1671,14 +1707,14 @@ func TestSecureBackupAppliance(t *testing.T) {
 	t.Run("SecureBackupAppliance no REST/SOMA", func(t *testing.T) {
 		clearRepo()
 
-		err := Repo.SecureBackupAppliance("dpa0", "cert1", "temporary:///test_secure_backup")
+		err := Repo.SecureBackupAppliance(context.Background(), "dpa0", "cert1", "temporary:///test_secure_backup")
 		assert.Equals(t, "SecureBackupAppliance", err, errs.Error("DataPower management interface Unknown not supported."))
 	})
 
 	t.Run("SecureBackupAppliance REST", func(t *testing.T) {
 		clearRepo()
 
-		err := Repo.SecureBackupAppliance("dpa1", "cert1", "temporary:///test_secure_backup")
+		err := Repo.SecureBackupAppliance(context.Background(), "dpa1", "cert1", "temporary:///test_secure_backup")
 		assert.Equals(t, "SecureBackupAppliance", err, errs.Error("DataPower management interface REST not supported for secure backup of the appliance."))
 	})
 
@@ -1686,7 +1722,7 @@ func TestSecureBackupAppliance(t *testing.T) {
 		clearRepo()
 		Repo.dataPowerAppliance.SomaUrl = testSomaURL
 
-		err := Repo.SecureBackupAppliance("dpa2", "cert1", "temporary:///test_secure_backup_error")
+		err := Repo.SecureBackupAppliance(context.Background(), "dpa2", "cert1", "temporary:///test_secure_backup_error")
 		assert.Equals(t, "SecureBackupAppliance", err, errs.Error("DataPower secure backup error: 'Error creating secure backup.'"))
 	})
 
@@ -1694,17 +1730,133 @@ func TestSecureBackupAppliance(t *testing.T) {
 		clearRepo()
 		Repo.dataPowerAppliance.SomaUrl = testSomaURL
 
-		err := Repo.SecureBackupAppliance("dpa2", "cert1", "temporary:///test_secure_backup_ok")
+		err := Repo.SecureBackupAppliance(context.Background(), "dpa2", "cert1", "temporary:///test_secure_backup_ok")
 		assert.Nil(t, "SecureBackupAppliance", err)
 		// assert.Equals(t, "SecureBackupAppliance", string(policyBytes), string(expectedPolicyBytes))
 	})
 }
 
+func TestImportAppliance(t *testing.T) {
+	dpa0 := config.DataPowerAppliance{
+		Username: "user",
+	}
+	dpa1 := config.DataPowerAppliance{
+		RestUrl:  testRestURL,
+		Username: "user",
+	}
+	dpa2 := config.DataPowerAppliance{
+		SomaUrl:  testSomaURL,
+		Username: "user",
+	}
+	config.Conf.DataPowerAppliances["dpa0"] = dpa0
+	config.Conf.DataPowerAppliances["dpa1"] = dpa1
+	config.Conf.DataPowerAppliances["dpa2"] = dpa2
+
+	t.Run("ImportAppliance no REST/SOMA", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+
+		importResults, err := Repo.ImportAppliance(context.Background(), "dpa0", []byte("backup"), true, false, false)
+		assert.Equals(t, "ImportAppliance", err, errs.Error("DataPower management interface Unknown not supported."))
+		assert.Equals(t, "ImportAppliance", len(importResults), 0)
+	})
+
+	t.Run("ImportAppliance REST", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+
+		importResults, err := Repo.ImportAppliance(context.Background(), "dpa1", []byte("backup"), true, false, false)
+		assert.Equals(t, "ImportAppliance", err, errs.Error("DataPower management interface REST not supported for appliance import."))
+		assert.Equals(t, "ImportAppliance", len(importResults), 0)
+	})
+
+	t.Run("ImportAppliance SOMA", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+
+		importResults, err := Repo.ImportAppliance(context.Background(), "dpa2", []byte("backup"), true, false, false)
+		assert.Nil(t, "ImportAppliance", err)
+		assert.Equals(t, "ImportAppliance", len(importResults), 3)
+		assert.Equals(t, "ImportAppliance", Repo.dataPowerAppliance, dpApplicance{})
+	})
+}
+
+func TestImportDomain(t *testing.T) {
+	expectedImportResults := []ImportResult{
+		{Type: "object", Class: "XMLFirewallService", Name: "test_xmlfw", Status: "new"},
+		{Type: "object", Class: "XMLManager", Name: "default", Status: "unchanged"},
+		{Type: "file", Name: "local:///test.xsl", Status: "overwritten"},
+	}
+
+	t.Run("ImportDomain no REST/SOMA", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+
+		importResults, err := Repo.ImportDomain(context.Background(), "MyImportDomain", []byte("export"), true, true, false)
+		assert.Equals(t, "ImportDomain", err, errs.Error("DataPower management interface not set."))
+		assert.Equals(t, "ImportDomain", len(importResults), 0)
+	})
+
+	t.Run("ImportDomain REST", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+		Repo.dataPowerAppliance.RestUrl = testRestURL
+
+		importResults, err := Repo.ImportDomain(context.Background(), "MyImportDomain", []byte("export"), true, true, false)
+		assert.Nil(t, "ImportDomain", err)
+		assert.DeepEqual(t, "ImportDomain", importResults, expectedImportResults)
+	})
+
+	t.Run("ImportDomain SOMA", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+		Repo.dataPowerAppliance.SomaUrl = testSomaURL
+
+		importResults, err := Repo.ImportDomain(context.Background(), "MyImportDomain", []byte("export"), false, true, false)
+		assert.Nil(t, "ImportDomain", err)
+		assert.DeepEqual(t, "ImportDomain", importResults, expectedImportResults)
+	})
+
+	t.Run("ImportDomain SOMA dry-run", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+		Repo.dataPowerAppliance.SomaUrl = testSomaURL
+
+		importResults, err := Repo.ImportDomain(context.Background(), "MyImportDomain", []byte("export"), false, true, true)
+		assert.Nil(t, "ImportDomain", err)
+		assert.DeepEqual(t, "ImportDomain", importResults,
+			[]ImportResult{
+				{Type: "object", Class: "XMLFirewallService", Name: "test_xmlfw", Status: "new"},
+				{Type: "object", Class: "XMLManager", Name: "default", Status: "modified"},
+				{Type: "file", Name: "local:///test.xsl", Status: "skipped"},
+			})
+	})
+
+	t.Run("ImportDomain SOMA Error", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+		Repo.dataPowerAppliance.SomaUrl = testSomaURL
+
+		importResults, err := Repo.ImportDomain(context.Background(), "MyImportErrDomain", []byte("export"), false, true, false)
+		assert.Equals(t, "ImportDomain", err, errs.Error("DataPower import error: 'Import package is corrupt.'"))
+		assert.Equals(t, "ImportDomain", len(importResults), 0)
+	})
+}
+
+func TestImportResultString(t *testing.T) {
+	assert.Equals(t, "String",
+		ImportResult{Type: "object", Class: "XMLManager", Name: "default", Status: "unchanged"}.String(),
+		"object unchanged default (XMLManager)")
+	assert.Equals(t, "String",
+		ImportResult{Type: "file", Name: "local:///test.xsl", Status: "new"}.String(),
+		"file   new       local:///test.xsl")
+}
+
 func TestGetObjectDetails(t *testing.T) {
 	t.Run("GetObjectDetails no REST/SOMA", func(t *testing.T) {
 		clearRepo()
 
-		policyBytes, err := Repo.GetObjectDetails("tmp", "XMLFirewallService", "parse-cert")
+		policyBytes, err := Repo.GetObjectDetails(context.Background(), "tmp", "XMLFirewallService", "parse-cert")
 		assert.Equals(t, "GetObjectDetails", err, errs.Error("DataPower management interface Unknown not supported."))
 		assert.Equals(t, "GetObjectDetails", policyBytes, []byte(nil))
 	})
@@ -1713,7 +1865,7 @@ func TestGetObjectDetails(t *testing.T) {
 		clearRepo()
 		Repo.dataPowerAppliance.RestUrl = testRestURL
 
-		policyBytes, err := Repo.GetObjectDetails("tmp", "XMLFirewallService", "parse-cert")
+		policyBytes, err := Repo.GetObjectDetails(context.Background(), "tmp", "XMLFirewallService", "parse-cert")
 		assert.Nil(t, "GetObjectDetails", err)
 		expectedPolicyBytes, err := ioutil.ReadFile("testdata/details-svc-xmlfw.txt")
 		assert.Nil(t, "GetObjectDetails error reading expected policy info", err)
@@ -1724,7 +1876,7 @@ func TestGetObjectDetails(t *testing.T) {
 		clearRepo()
 		Repo.dataPowerAppliance.SomaUrl = testSomaURL
 
-		policyBytes, err := Repo.GetObjectDetails("tmp", "XMLFirewallService", "parse-cert")
+		policyBytes, err := Repo.GetObjectDetails(context.Background(), "tmp", "XMLFirewallService", "parse-cert")
 		assert.Nil(t, "GetObjectDetails", err)
 		expectedPolicyBytes, err := ioutil.ReadFile("testdata/details-svc-xmlfw.txt")
 		assert.Nil(t, "GetObjectDetails error reading expected policy info", err)
@@ -1791,6 +1943,25 @@ func TestGetObjectDetailsFromExportXML(t *testing.T) {
 	})
 }
 
+func TestListFilesRecursive(t *testing.T) {
+	clearRepo()
+	syncFiles, err := Repo.ListFilesRecursive(context.Background(), "test", "store:/gatewayscript")
+	assert.Equals(t, "ListFilesRecursive", err, errs.Error("DataPower management interface not set."))
+	assert.Equals(t, "ListFilesRecursive", len(syncFiles), 0)
+
+	Repo.req = mockRequester{}
+	Repo.dataPowerAppliance = dpApplicance{name: "MyApplianceName",
+		DataPowerAppliance: config.DataPowerAppliance{RestUrl: testRestURL, Username: "user"}}
+	syncFiles, err = Repo.ListFilesRecursive(context.Background(), "test", "store:/gatewayscript")
+	assert.Nil(t, "ListFilesRecursive", err)
+	assert.Equals(t, "ListFilesRecursive", len(syncFiles), 29)
+	if len(syncFiles) == 29 {
+		assert.DeepEqual(t, "ListFilesRecursive", syncFiles[0],
+			SyncFile{PathFromRoot: "example-b2b-routing.js",
+				Path: "store:/gatewayscript/example-b2b-routing.js", Size: "2413", Modified: "2019-08-09 15:24:42"})
+	}
+}
+
 func TestGetFilePath(t *testing.T) {
 	testDataMatrix := [][]string{
 		{"local:/dir1/dir2", "myfile", "local:/dir1/dir2/myfile"},
@@ -2122,6 +2293,277 @@ func TestRenameObject(t *testing.T) {
 	assert.DeepEqual(t, "XML object configuration rename", string(objectXMLGot), objectXMLExpected)
 }
 
+func TestListObjectClassNames(t *testing.T) {
+	expectedClassNames := []string{"AAAPolicy", "B2BGateway", "XMLFirewallService"}
+
+	t.Run("ListObjectClassNames no REST/SOMA", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+
+		classNames, err := Repo.ListObjectClassNames(context.Background())
+		assert.Equals(t, "ListObjectClassNames", err, errs.Error("DataPower management interface not set."))
+		assert.Equals(t, "ListObjectClassNames", len(classNames), 0)
+	})
+
+	t.Run("ListObjectClassNames REST", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+		Repo.dataPowerAppliance.RestUrl = testRestURL
+
+		classNames, err := Repo.ListObjectClassNames(context.Background())
+		assert.Nil(t, "ListObjectClassNames", err)
+		assert.DeepEqual(t, "ListObjectClassNames", classNames, expectedClassNames)
+	})
+
+	t.Run("ListObjectClassNames SOMA", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+		Repo.dataPowerAppliance.SomaUrl = testSomaURL
+
+		classNames, err := Repo.ListObjectClassNames(context.Background())
+		assert.Nil(t, "ListObjectClassNames", err)
+		assert.DeepEqual(t, "ListObjectClassNames", classNames, expectedClassNames)
+	})
+}
+
+func TestCreateObjectSkeleton(t *testing.T) {
+	t.Run("CreateObjectSkeleton no REST/SOMA", func(t *testing.T) {
+		clearRepo()
+
+		skeleton, err := Repo.CreateObjectSkeleton("XMLFirewallService", "NewFirewall")
+		assert.Equals(t, "CreateObjectSkeleton", err, errs.Error("DataPower management interface not set."))
+		assert.Equals(t, "CreateObjectSkeleton", skeleton, []byte(nil))
+	})
+
+	for _, dpa := range []dpApplicance{
+		{DataPowerAppliance: config.DataPowerAppliance{RestUrl: testRestURL}},
+		{DataPowerAppliance: config.DataPowerAppliance{SomaUrl: testSomaURL}}} {
+		t.Run("CreateObjectSkeleton "+dpa.DpManagmentInterface(), func(t *testing.T) {
+			clearRepo()
+			Repo.dataPowerAppliance = dpa
+
+			skeleton, err := Repo.CreateObjectSkeleton("XMLFirewallService", "NewFirewall")
+			assert.Nil(t, "CreateObjectSkeleton", err)
+			objectClass, objectName, err := Repo.ParseObjectClassAndName(skeleton)
+			assert.Nil(t, "CreateObjectSkeleton", err)
+			assert.Equals(t, "CreateObjectSkeleton", objectClass, "XMLFirewallService")
+			assert.Equals(t, "CreateObjectSkeleton", objectName, "NewFirewall")
+		})
+	}
+}
+
+func TestGetDomainObjects(t *testing.T) {
+	t.Run("GetDomainObjects no REST/SOMA", func(t *testing.T) {
+		clearRepo()
+		objects, err := Repo.GetDomainObjects(context.Background(), "tmp")
+		assert.Equals(t, "GetDomainObjects", err, errs.Error("DataPower management interface not set."))
+		assert.Equals(t, "GetDomainObjects", len(objects), 0)
+	})
+
+	t.Run("GetDomainObjects REST", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+		Repo.dataPowerAppliance.RestUrl = testRestURL
+
+		objects, err := Repo.GetDomainObjects(context.Background(), "MyDiffDomain")
+		assert.Nil(t, "GetDomainObjects", err)
+		want := []DomainObject{
+			{Class: "XMLFirewallService", Name: "fw-a", Config: []byte(`{
+  "XMLFirewallService": {
+    "name": "fw-a",
+    "mAdminState": "enabled",
+    "LocalPort": 20001,
+    "XMLManager": {
+      "value": "default"
+    }
+  }
+}`)},
+			{Class: "XMLFirewallService", Name: "fw-b", Config: []byte(`{
+  "XMLFirewallService": {
+    "name": "fw-b",
+    "mAdminState": "disabled",
+    "LocalPort": 20002,
+    "XMLManager": {
+      "value": "default"
+    }
+  }
+}`)},
+			{Class: "XMLManager", Name: "default", Config: []byte(`{
+  "XMLManager": {
+    "name": "default",
+    "mAdminState": "enabled",
+    "CacheSize": 256
+  }
+}`)},
+		}
+		assert.DeepEqual(t, "GetDomainObjects", objects, want)
+	})
+
+	t.Run("GetDomainObjects SOMA", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+		Repo.dataPowerAppliance.SomaUrl = testSomaURL
+
+		objects, err := Repo.GetDomainObjects(context.Background(), "MyDiffDomain")
+		assert.Nil(t, "GetDomainObjects", err)
+		want := []DomainObject{
+			{Class: "XMLFirewallService", Name: "fw-a", Config: []byte(`<XMLFirewallService name="fw-a">
+  <mAdminState>enabled</mAdminState>
+  <LocalPort>20001</LocalPort>
+  <DebugMode>off</DebugMode>
+  <XMLManager class="XMLManager">default</XMLManager>
+</XMLFirewallService>`)},
+			{Class: "XMLFirewallService", Name: "fw-b", Config: []byte(`<XMLFirewallService name="fw-b">
+  <mAdminState>disabled</mAdminState>
+  <LocalPort>20002</LocalPort>
+  <DebugMode>off</DebugMode>
+  <XMLManager class="XMLManager">default</XMLManager>
+</XMLFirewallService>`)},
+			{Class: "XMLManager", Name: "default", Config: []byte(`<XMLManager name="default">
+  <mAdminState>enabled</mAdminState>
+  <CacheSize>256</CacheSize>
+</XMLManager>`)},
+		}
+		assert.DeepEqual(t, "GetDomainObjects", objects, want)
+	})
+}
+
+func TestDiffDomainObjects(t *testing.T) {
+	leftObjects := []DomainObject{
+		{Class: "XMLManager", Name: "default", Config: []byte(`{"XMLManager": {"name": "default", "CacheSize": 256}}`)},
+		{Class: "XMLFirewallService", Name: "fw-same", Config: []byte(`{"XMLFirewallService": {"name": "fw-same", "LocalPort": 8080}}`)},
+		{Class: "XMLFirewallService", Name: "fw-changed", Config: []byte(`{"XMLFirewallService": {"name": "fw-changed", "LocalPort": 8081}}`)},
+		{Class: "XMLFirewallService", Name: "fw-left", Config: []byte(`{"XMLFirewallService": {"name": "fw-left"}}`)},
+	}
+	rightObjects := []DomainObject{
+		{Class: "XMLFirewallService", Name: "fw-right", Config: []byte(`{"XMLFirewallService": {"name": "fw-right"}}`)},
+		{Class: "XMLFirewallService", Name: "fw-changed", Config: []byte(`{"XMLFirewallService": {"name": "fw-changed", "LocalPort": 9081}}`)},
+		{Class: "XMLFirewallService", Name: "fw-same", Config: []byte(`{
+  "XMLFirewallService": {
+    "LocalPort": 8080,
+    "name": "fw-same"
+  }
+}
+`)},
+		{Class: "XMLManager", Name: "default", Config: []byte(`{"XMLManager": {"CacheSize": 256, "name": "default"}}`)},
+	}
+
+	diffs := DiffDomainObjects(leftObjects, rightObjects)
+	want := []ObjectDiff{
+		{Class: "XMLFirewallService", Name: "fw-changed", Status: ObjectDiffChanged,
+			Left: leftObjects[2].Config, Right: rightObjects[1].Config},
+		{Class: "XMLFirewallService", Name: "fw-left", Status: ObjectDiffOnlyLeft,
+			Left: leftObjects[3].Config},
+		{Class: "XMLFirewallService", Name: "fw-right", Status: ObjectDiffOnlyRight,
+			Right: rightObjects[0].Config},
+	}
+	assert.DeepEqual(t, "DiffDomainObjects", diffs, want)
+	assert.Equals(t, "DiffDomainObjects", diffs[0].String(), "changed    fw-changed (XMLFirewallService)")
+
+	diffs = DiffDomainObjects(
+		[]DomainObject{{Class: "XMLManager", Name: "default", Config: []byte("<XMLManager name=\"default\"/>\n")}},
+		[]DomainObject{{Class: "XMLManager", Name: "default", Config: []byte("<XMLManager name=\"default\"/>")}})
+	assert.Equals(t, "DiffDomainObjects", len(diffs), 0)
+}
+
+func TestCompareObjects(t *testing.T) {
+	t.Run("CompareObjects JSON", func(t *testing.T) {
+		oldObject := []byte(`{"XMLFirewallService": {"name": "fw", "LocalPort": 8080, "UserSummary": "old",
+  "HTTPVersion": {"Front": "HTTP/1.1", "Back": "HTTP/1.1"},
+  "DebugTrigger": [{"value": "a"}, {"value": "b"}, {"value": "c"}]}}`)
+		newObject := []byte(`{
+  "XMLFirewallService": {
+    "DebugTrigger": [{"value": "c"}, {"value": "d"}, {"value": "a"}],
+    "HTTPVersion": {"Back": "HTTP/1.0", "Front": "HTTP/1.1"},
+    "LocalPort": 8080,
+    "name": "fw",
+    "Priority": "normal"
+  }
+}`)
+		diffs, err := CompareObjects(oldObject, newObject)
+		assert.Nil(t, "CompareObjects", err)
+		assert.DeepEqual(t, "CompareObjects", diffs, []PropertyDiff{
+			{Path: "XMLFirewallService/DebugTrigger[]", Status: PropertyRemoved, OldValue: `{"value":"b"}`},
+			{Path: "XMLFirewallService/DebugTrigger[]", Status: PropertyAdded, NewValue: `{"value":"d"}`},
+			{Path: "XMLFirewallService/HTTPVersion/Back", Status: PropertyChanged, OldValue: "HTTP/1.1", NewValue: "HTTP/1.0"},
+			{Path: "XMLFirewallService/Priority", Status: PropertyAdded, NewValue: "normal"},
+			{Path: "XMLFirewallService/UserSummary", Status: PropertyRemoved, OldValue: "old"},
+		})
+		assert.Equals(t, "CompareObjects", diffs[2].String(), "~ XMLFirewallService/HTTPVersion/Back: HTTP/1.1 -> HTTP/1.0")
+	})
+
+	t.Run("CompareObjects XML", func(t *testing.T) {
+		oldObject := []byte(`<XMLFirewallService name="fw">
+  <mAdminState>enabled</mAdminState>
+  <LocalPort>8080</LocalPort>
+  <DebugTrigger>a</DebugTrigger>
+  <XMLManager class="XMLManager">default</XMLManager>
+</XMLFirewallService>`)
+		newObject := []byte(`<XMLFirewallService name="fw"><LocalPort>8080</LocalPort>
+<XMLManager class="XMLManager">custom</XMLManager><mAdminState>disabled</mAdminState>
+<DebugTrigger>b</DebugTrigger><DebugTrigger>a</DebugTrigger></XMLFirewallService>`)
+		diffs, err := CompareObjects(oldObject, newObject)
+		assert.Nil(t, "CompareObjects", err)
+		assert.DeepEqual(t, "CompareObjects", diffs, []PropertyDiff{
+			{Path: "XMLFirewallService/DebugTrigger[]", Status: PropertyAdded, NewValue: "b"},
+			{Path: "XMLFirewallService/XMLManager", Status: PropertyChanged, OldValue: "default", NewValue: "custom"},
+			{Path: "XMLFirewallService/mAdminState", Status: PropertyChanged, OldValue: "enabled", NewValue: "disabled"},
+		})
+	})
+
+	t.Run("CompareObjects same", func(t *testing.T) {
+		diffs, err := CompareObjects([]byte(`<XMLManager name="default"><CacheSize>256</CacheSize></XMLManager>`),
+			[]byte("<XMLManager name=\"default\">\n  <CacheSize>256</CacheSize>\n</XMLManager>\n"))
+		assert.Nil(t, "CompareObjects", err)
+		assert.Equals(t, "CompareObjects", len(diffs), 0)
+	})
+
+	t.Run("CompareObjects missing object", func(t *testing.T) {
+		diffs, err := CompareObjects(nil, []byte(`<XMLManager name="default"/>`))
+		assert.Nil(t, "CompareObjects", err)
+		assert.DeepEqual(t, "CompareObjects", diffs, []PropertyDiff{
+			{Path: "XMLManager", Status: PropertyAdded, NewValue: `{"-name":"default"}`},
+		})
+	})
+}
+
+func TestDeleteDomain(t *testing.T) {
+	t.Run("DeleteDomain no REST/SOMA", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+
+		err := Repo.DeleteDomain(context.Background(), "MyDeletedDomain")
+		assert.Equals(t, "DeleteDomain", err, errs.Error("DataPower management interface not set."))
+	})
+
+	t.Run("DeleteDomain default", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+		Repo.dataPowerAppliance.RestUrl = testRestURL
+
+		err := Repo.DeleteDomain(context.Background(), "default")
+		assert.Equals(t, "DeleteDomain", err, errs.Error("Can't delete default domain."))
+	})
+
+	t.Run("DeleteDomain REST", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+		Repo.dataPowerAppliance.RestUrl = testRestURL
+
+		err := Repo.DeleteDomain(context.Background(), "MyDeletedDomain")
+		assert.Nil(t, "DeleteDomain", err)
+	})
+
+	t.Run("DeleteDomain SOMA", func(t *testing.T) {
+		clearRepo()
+		Repo.req = mockRequester{}
+		Repo.dataPowerAppliance.SomaUrl = testSomaURL
+
+		err := Repo.DeleteDomain(context.Background(), "MyDeletedDomain")
+		assert.Nil(t, "DeleteDomain", err)
+	})
+}
+
 func TestGetStatus(t *testing.T) {
 	t.Run("DpStatusMode/GetStatus * REST", func(t *testing.T) {
 		clearRepo()
@@ -2135,7 +2577,7 @@ func TestGetStatus(t *testing.T) {
 		Repo.req = mockRequester{}
 		Repo.dataPowerAppliance = dpApplicance{"", dpa}
 
-		statusBytes, err := Repo.GetStatus(
+		statusBytes, err := Repo.GetStatus(context.Background(),
 			dpa.Domain, "StylesheetCachingSummary", 1)
 
 		assert.Equals(t, "GetStatus", err, nil)
@@ -2167,7 +2609,7 @@ func TestGetStatus(t *testing.T) {
 		Repo.req = mockRequester{}
 		Repo.dataPowerAppliance = dpApplicance{"", dpa}
 
-		statusBytes, err := Repo.GetStatus(
+		statusBytes, err := Repo.GetStatus(context.Background(),
 			dpa.Domain, "CryptoEngineStatus2", 0)
 
 		assert.Equals(t, "GetStatus", err, nil)
@@ -2194,7 +2636,7 @@ func TestGetStatus(t *testing.T) {
 		Repo.req = mockRequester{}
 		Repo.dataPowerAppliance = dpApplicance{"", dpa}
 
-		statusBytes, err := Repo.GetStatus(
+		statusBytes, err := Repo.GetStatus(context.Background(),
 			dpa.Domain, "StylesheetCachingSummary", 1)
 
 		assert.Equals(t, "GetStatus", err, nil)
@@ -2220,7 +2662,7 @@ func TestExecConfig(t *testing.T) {
 	t.Run("ExecConfig no REST/SOMA", func(t *testing.T) {
 		clearRepo()
 
-		err := Repo.ExecConfig(&itemToExec)
+		err := Repo.ExecConfig(context.Background(), &itemToExec)
 		assert.Equals(t, "ExecConfig", err, errs.Error("DataPower management interface not set."))
 	})
 
@@ -2229,7 +2671,7 @@ func TestExecConfig(t *testing.T) {
 		clearRepo()
 		Repo.dataPowerAppliance.RestUrl = testRestURL
 
-		err := Repo.ExecConfig(&itemToExec)
+		err := Repo.ExecConfig(context.Background(), &itemToExec)
 		assert.Equals(t, "ExecConfig", err, errs.Error("Unexpected response from server."))
 	})
 
@@ -2238,7 +2680,7 @@ func TestExecConfig(t *testing.T) {
 		clearRepo()
 		Repo.dataPowerAppliance.RestUrl = testRestURL
 
-		err := Repo.ExecConfig(&itemToExec)
+		err := Repo.ExecConfig(context.Background(), &itemToExec)
 		assert.Nil(t, "ExecConfig", err)
 	})
 
@@ -2247,7 +2689,7 @@ func TestExecConfig(t *testing.T) {
 		clearRepo()
 		Repo.dataPowerAppliance.SomaUrl = testSomaURL
 
-		err := Repo.ExecConfig(&itemToExec)
+		err := Repo.ExecConfig(context.Background(), &itemToExec)
 		assert.Equals(t, "ExecConfig", err, errs.Error("DataPower exec config error: 'Unable to execute local:/config-err.cfg - must be text file.'"))
 	})
 
@@ -2256,7 +2698,7 @@ func TestExecConfig(t *testing.T) {
 		clearRepo()
 		Repo.dataPowerAppliance.SomaUrl = testSomaURL
 
-		err := Repo.ExecConfig(&itemToExec)
+		err := Repo.ExecConfig(context.Background(), &itemToExec)
 		assert.Nil(t, "ExecConfig", err)
 	})
 }
@@ -1,6 +1,7 @@
 package dp
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"regexp"
@@ -11,7 +12,7 @@ import (
 
 type mockRequester struct{}
 
-func (nr mockRequester) httpRequest(dpa dpApplicance, urlFullPath, method, body string) (string, error) {
+func (nr mockRequester) httpRequest(ctx context.Context, dpa dpApplicance, urlFullPath, method, body string) (string, error) {
 	// fmt.Printf("%s %s\n", method, urlFullPath)
 	var content []byte
 	var err error
@@ -21,6 +22,12 @@ func (nr mockRequester) httpRequest(dpa dpApplicance, urlFullPath, method, body
 		content, err = ioutil.ReadFile("testdata/object_class_status_list.json")
 	case "https://my_dp_host:5554/mgmt/config/MyDomain/XMLFirewallService":
 		content, err = ioutil.ReadFile("testdata/object_xmlfwsvc_config_list.json")
+	case "https://my_dp_host:5554/mgmt/status/MyDiffDomain/ObjectStatus":
+		content, err = ioutil.ReadFile("testdata/diff_object_status_list.json")
+	case "https://my_dp_host:5554/mgmt/config/MyDiffDomain/XMLFirewallService":
+		content, err = ioutil.ReadFile("testdata/diff_xmlfwsvc_config_list.json")
+	case "https://my_dp_host:5554/mgmt/config/MyDiffDomain/XMLManager":
+		content, err = ioutil.ReadFile("testdata/diff_xmlmgr_config_list.json")
 	case "https://my_dp_host:5554/mgmt/config/default/Domain":
 		content, err = ioutil.ReadFile("testdata/domain_config_list.json")
 	case "https://my_dp_host:5554/mgmt/status/default/DomainStatus":
@@ -80,8 +87,26 @@ func (nr mockRequester) httpRequest(dpa dpApplicance, urlFullPath, method, body
 		default:
 			return "", errs.Errorf("dpmock_test: Unrecognized method '%s'", method)
 		}
+	case "https://my_dp_host:5554/mgmt/actionqueue/MyImportDomain":
+		switch method {
+		case "POST":
+			content, err = ioutil.ReadFile("testdata/import-post-response.json")
+		default:
+			return "", errs.Errorf("dpmock_test: Unrecognized method '%s'", method)
+		}
+	case "https://my_dp_host:5554/mgmt/actionqueue/MyImportDomain/pending/Import-20200302T091531Z-3":
+		content, err = ioutil.ReadFile("testdata/import-pending-get.json")
 	case "https://my_dp_host:5554/mgmt/actionqueue/tmp/pending/Export-20200228T061406Z-2":
 		content, err = ioutil.ReadFile("testdata/export-svc-pending-get.json")
+	case "https://my_dp_host:5554/mgmt/config/default/Domain/MyDeletedDomain":
+		switch method {
+		case "DELETE":
+			content, err = ioutil.ReadFile("testdata/domain_delete.json")
+		default:
+			return "", errs.Errorf("dpmock_test: Unrecognized method '%s'", method)
+		}
+	case "https://my_dp_host:5554/mgmt/config/":
+		content, err = ioutil.ReadFile("testdata/config_class_list.json")
 	case "https://my_dp_host:5554/mgmt/status/":
 		content, err = ioutil.ReadFile("testdata/status_class_list.json")
 	case "https://my_dp_host:5554/mgmt/status/MyDomain/StylesheetCachingSummary":
@@ -106,6 +131,14 @@ func (nr mockRequester) httpRequest(dpa dpApplicance, urlFullPath, method, body
 			opObjClass = matches[5]
 		}
 
+		if strings.Contains(body, "<man:get-schema/>") {
+			opTag = "get-schema"
+		}
+		if strings.Contains(body, "<man:del-config><Domain ") {
+			opTag = "del-config"
+			opClass = "Domain"
+		}
+
 		if len(matches) == 0 {
 			r = regexp.MustCompile(`.*<man:(get-filestore) layout-only="([^ ]+)".*`)
 			matches = r.FindStringSubmatch(body)
@@ -132,6 +165,14 @@ func (nr mockRequester) httpRequest(dpa dpApplicance, urlFullPath, method, body
 			}
 		}
 
+		if len(matches) == 0 {
+			r = regexp.MustCompile(`.*<man:(do-import|do-restore) .*`)
+			matches = r.FindStringSubmatch(body)
+			if len(matches) == 2 {
+				opTag = matches[1]
+			}
+		}
+
 		if len(matches) == 0 {
 			r = regexp.MustCompile(`.*<man:(do-action)>.*`)
 			matches = r.FindStringSubmatch(body)
@@ -153,13 +194,20 @@ func (nr mockRequester) httpRequest(dpa dpApplicance, urlFullPath, method, body
 			}
 		}
 
-		if len(matches) == 0 {
+		if len(matches) == 0 && opTag == "" {
 			fmt.Printf("dpmock_test: Unrecognized body of SOMA request:\n'%s'\n", body)
 			return "", errs.Error("dpmock_test: Unrecognized body of SOMA request")
 		}
 		// fmt.Printf(" opTag: '%s', opClass: '%s', opObjClass: '%s', opLayoutOnly: '%s', opFilePath: '%s'.\n",
 		// 	opTag, opClass, opObjClass, opLayoutOnly, opFilePath)
+		diffDomain := strings.Contains(body, `domain="MyDiffDomain"`)
 		switch {
+		case diffDomain && opTag == "get-status" && opClass == "ObjectStatus":
+			content, err = ioutil.ReadFile("testdata/diff_object_status_list.xml")
+		case diffDomain && opTag == "get-config" && opClass == "XMLFirewallService":
+			content, err = ioutil.ReadFile("testdata/diff_xmlfwsvc_config_list.xml")
+		case diffDomain && opTag == "get-config" && opClass == "XMLManager":
+			content, err = ioutil.ReadFile("testdata/diff_xmlmgr_config_list.xml")
 		case opTag == "get-status" && opClass == "ObjectStatus" && opObjClass == "":
 			content, err = ioutil.ReadFile("testdata/object_class_status_list.xml")
 		case opTag == "get-status" && opClass == "ObjectStatus" && opObjClass == "XMLFirewallService":
@@ -176,6 +224,10 @@ func (nr mockRequester) httpRequest(dpa dpApplicance, urlFullPath, method, body
 			content, err = ioutil.ReadFile("testdata/domain_status_list.xml")
 		case opTag == "get-status" && opClass == "" && opObjClass == "":
 			content, err = ioutil.ReadFile("testdata/status_class_list.xml")
+		case opTag == "del-config" && opClass == "Domain":
+			content, err = ioutil.ReadFile("testdata/domain_delete.xml")
+		case opTag == "get-schema":
+			content, err = ioutil.ReadFile("testdata/schema.xml")
 		case opTag == "get-filestore" && opLayoutOnly == "true":
 			content, err = ioutil.ReadFile("testdata/filestore_layout_list.xml")
 		case opTag == "get-filestore" && opLayoutOnly == "false":
@@ -192,6 +244,18 @@ func (nr mockRequester) httpRequest(dpa dpApplicance, urlFullPath, method, body
 			content, err = ioutil.ReadFile("testdata/update_file_existing_dir.xml")
 		case opTag == "do-export":
 			content, err = ioutil.ReadFile("testdata/export.soap")
+		case opTag == "do-import", opTag == "do-restore":
+			r = regexp.MustCompile(`.*MyImportErrDomain.*`)
+			matches = r.FindStringSubmatch(body)
+			dryRunMatches := regexp.MustCompile(`.*dry-run="true".*`).FindStringSubmatch(body)
+			switch {
+			case len(matches) == 1:
+				content, err = ioutil.ReadFile("testdata/import_error.xml")
+			case len(dryRunMatches) == 1:
+				content, err = ioutil.ReadFile("testdata/import_dry_run.xml")
+			default:
+				content, err = ioutil.ReadFile("testdata/import.xml")
+			}
 		case opTag == "do-action" && opAction == "SecureBackup":
 			r = regexp.MustCompile(`.*test_secure_backup_error.*`)
 			matches = r.FindStringSubmatch(body)
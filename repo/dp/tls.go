@@ -0,0 +1,114 @@
+package dp
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/croz-ltd/dpcmder/utils/errs"
+	"github.com/croz-ltd/dpcmder/utils/logging"
+)
+
+// CertificateError is returned when TLS certificate of the DataPower appliance
+// can't be verified - fingerprint of the certificate can be saved to trust it.
+type CertificateError struct {
+	Appliance   string
+	Subject     string
+	Fingerprint string
+	Reason      string
+}
+
+func (ce CertificateError) Error() string {
+	return fmt.Sprintf("TLS certificate of DataPower appliance '%s' (%s) not trusted: %s (SHA-256 fingerprint %s)",
+		ce.Appliance, ce.Subject, ce.Reason, ce.Fingerprint)
+}
+
+// CertificateFingerprint returns SHA-256 fingerprint of the certificate
+// formatted as colon separated hex bytes.
+func CertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	hexBytes := make([]string, len(sum))
+	for idx, b := range sum {
+		hexBytes[idx] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(hexBytes, ":")
+}
+
+// normalizeFingerprint removes separators from the fingerprint so
+// fingerprints written in different formats can be compared.
+func normalizeFingerprint(fingerprint string) string {
+	return strings.ToUpper(strings.NewReplacer(":", "", " ", "").Replace(fingerprint))
+}
+
+// tlsConfig creates TLS configuration used to connect to the appliance. Trusted
+// certificate fingerprint is checked in VerifyConnection (instead of the
+// standard verification) so changed certificate can be reported with its
+// fingerprint.
+func tlsConfig(dpa dpApplicance) (*tls.Config, error) {
+	tlsConf := &tls.Config{InsecureSkipVerify: true}
+
+	if dpa.TLSClientCertFile != "" {
+		keyFile := dpa.TLSClientKeyFile
+		if keyFile == "" {
+			keyFile = dpa.TLSClientCertFile
+		}
+		clientCert, err := tls.LoadX509KeyPair(dpa.TLSClientCertFile, keyFile)
+		if err != nil {
+			return nil, errs.Errorf("Can't load TLS client certificate for DataPower appliance '%s': %v",
+				dpa.name, err)
+		}
+		tlsConf.Certificates = []tls.Certificate{clientCert}
+	}
+
+	switch {
+	case dpa.TLSFingerprint != "":
+		trustedFingerprint := normalizeFingerprint(dpa.TLSFingerprint)
+		tlsConf.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errs.Error("No TLS certificate received from DataPower.")
+			}
+			fingerprint := CertificateFingerprint(cs.PeerCertificates[0])
+			if normalizeFingerprint(fingerprint) != trustedFingerprint {
+				return CertificateError{Appliance: dpa.name, Subject: cs.PeerCertificates[0].Subject.String(),
+					Fingerprint: fingerprint, Reason: "certificate fingerprint changed"}
+			}
+			return nil
+		}
+	case dpa.TLSVerify:
+		// Standard verification checks the host name (or IP address) too,
+		// verification errors are converted to CertificateError in
+		// verificationError.
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			logging.LogDebug("repo/dp/tlsConfig() - Can't load system CAs: ", err)
+			roots = x509.NewCertPool()
+		}
+		if dpa.TLSCAFile != "" {
+			caBytes, err := ioutil.ReadFile(dpa.TLSCAFile)
+			if err != nil {
+				return nil, errs.Errorf("Can't read CA file for DataPower appliance '%s': %v", dpa.name, err)
+			}
+			if !roots.AppendCertsFromPEM(caBytes) {
+				return nil, errs.Errorf("No CA certificates found in '%s'.", dpa.TLSCAFile)
+			}
+		}
+		tlsConf.InsecureSkipVerify = false
+		tlsConf.RootCAs = roots
+	}
+
+	return tlsConf, nil
+}
+
+// verificationError converts error of the standard TLS certificate
+// verification to CertificateError so certificate can be trusted.
+func verificationError(dpa dpApplicance, verifyErr *tls.CertificateVerificationError) CertificateError {
+	certErr := CertificateError{Appliance: dpa.name, Reason: verifyErr.Err.Error()}
+	if len(verifyErr.UnverifiedCertificates) != 0 {
+		certErr.Subject = verifyErr.UnverifiedCertificates[0].Subject.String()
+		certErr.Fingerprint = CertificateFingerprint(verifyErr.UnverifiedCertificates[0])
+	}
+	return certErr
+}
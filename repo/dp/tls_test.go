@@ -0,0 +1,125 @@
+package dp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/croz-ltd/dpcmder/config"
+	"github.com/croz-ltd/dpcmder/utils/assert"
+)
+
+// startTLSTestServer starts TLS test server, if cert is nil default
+// httptest certificate (valid for 127.0.0.1 and example.com) is used.
+func startTLSTestServer(cert *tls.Certificate) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	// Rejected handshakes are expected - don't log them.
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	if cert != nil {
+		server.TLS = &tls.Config{Certificates: []tls.Certificate{*cert}}
+	}
+	server.StartTLS()
+	return server
+}
+
+// selfSignedCertificate creates self-signed CA certificate valid only for the
+// given host name.
+func selfSignedCertificate(t *testing.T, dnsName string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, "GenerateKey", err)
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: dnsName},
+		DNSNames:              []string{dnsName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	assert.Nil(t, "CreateCertificate", err)
+	cert, err := x509.ParseCertificate(certBytes)
+	assert.Nil(t, "ParseCertificate", err)
+	return tls.Certificate{Certificate: [][]byte{certBytes}, PrivateKey: key, Leaf: cert}
+}
+
+// writeCAFile writes certificate to the PEM file in the test temp dir.
+func writeCAFile(t *testing.T, cert *x509.Certificate) string {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	err := ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0644)
+	assert.Nil(t, "WriteFile", err)
+	return caFile
+}
+
+func TestNetRequesterTLS(t *testing.T) {
+	server := startTLSTestServer(nil)
+	defer server.Close()
+	serverCert := server.Certificate()
+	fingerprint := CertificateFingerprint(serverCert)
+	caFile := writeCAFile(t, serverCert)
+
+	otherCert := selfSignedCertificate(t, "dp.example.com")
+	otherServer := startTLSTestServer(&otherCert)
+	defer otherServer.Close()
+	otherFingerprint := CertificateFingerprint(otherCert.Leaf)
+	otherCAFile := writeCAFile(t, otherCert.Leaf)
+
+	testDataMatrix := []struct {
+		name        string
+		url         string
+		dpa         config.DataPowerAppliance
+		fingerprint string
+		reason      string
+	}{
+		{"insecure", server.URL, config.DataPowerAppliance{}, "", ""},
+		{"verify", server.URL, config.DataPowerAppliance{TLSVerify: true}, fingerprint, "x509"},
+		{"verify with CA", server.URL, config.DataPowerAppliance{TLSVerify: true, TLSCAFile: caFile}, "", ""},
+		{"verify with CA host mismatch", strings.Replace(server.URL, "127.0.0.1", "localhost", 1),
+			config.DataPowerAppliance{TLSVerify: true, TLSCAFile: caFile}, fingerprint, "localhost"},
+		{"verify with CA IP mismatch", otherServer.URL,
+			config.DataPowerAppliance{TLSVerify: true, TLSCAFile: otherCAFile}, otherFingerprint, "127.0.0.1"},
+		{"fingerprint", server.URL, config.DataPowerAppliance{TLSVerify: true, TLSFingerprint: strings.ToLower(fingerprint)}, "", ""},
+		{"wrong fingerprint", server.URL, config.DataPowerAppliance{TLSFingerprint: "AA:BB"}, fingerprint, "certificate fingerprint changed"},
+	}
+	for _, testCase := range testDataMatrix {
+		t.Run(testCase.name, func(t *testing.T) {
+			dpa := dpApplicance{name: "MyDp", DataPowerAppliance: testCase.dpa}
+			res, err := netRequester{}.httpRequest(context.Background(), dpa, testCase.url, "GET", "")
+			if testCase.reason == "" {
+				assert.Nil(t, "httpRequest", err)
+				assert.Equals(t, "httpRequest", res, "ok")
+				return
+			}
+			certErr, ok := err.(CertificateError)
+			assert.True(t, "httpRequest CertificateError", ok)
+			assert.Equals(t, "httpRequest", certErr.Appliance, "MyDp")
+			assert.Equals(t, "httpRequest", certErr.Fingerprint, testCase.fingerprint)
+			assert.True(t, "httpRequest", strings.Contains(certErr.Reason, testCase.reason))
+		})
+	}
+
+	_, err := tlsConfig(dpApplicance{name: "MyDp",
+		DataPowerAppliance: config.DataPowerAppliance{TLSClientCertFile: caFile + ".missing"}})
+	assert.NotNil(t, "tlsConfig", err)
+	_, err = tlsConfig(dpApplicance{name: "MyDp",
+		DataPowerAppliance: config.DataPowerAppliance{TLSVerify: true, TLSCAFile: caFile + ".missing"}})
+	assert.NotNil(t, "tlsConfig", err)
+}
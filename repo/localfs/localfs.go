@@ -2,10 +2,12 @@
 package localfs
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -54,7 +56,7 @@ func (r localRepo) GetTitle(itemToShow *model.ItemConfig) string {
 }
 
 // GetList returns list of items for current directory.
-func (r localRepo) GetList(itemToShow *model.ItemConfig) (model.ItemList, error) {
+func (r localRepo) GetList(ctx context.Context, itemToShow *model.ItemConfig) (model.ItemList, error) {
 	logging.LogDebugf("repo/localfs/GetList('%s')", itemToShow)
 	currPath := itemToShow.Path
 	currName := paths.GetFileName(currPath)
@@ -79,7 +81,7 @@ func (r localRepo) GetList(itemToShow *model.ItemConfig) (model.ItemList, error)
 
 func (r localRepo) InvalidateCache() {}
 
-func (r localRepo) GetFile(currentView *model.ItemConfig, fileName string) ([]byte, error) {
+func (r localRepo) GetFile(ctx context.Context, currentView *model.ItemConfig, fileName string) ([]byte, error) {
 	logging.LogDebugf("repo/localfs/GetFile(%v, '%s')", currentView, fileName)
 	parentPath := currentView.Path
 	filePath := paths.GetFilePath(parentPath, fileName)
@@ -87,7 +89,7 @@ func (r localRepo) GetFile(currentView *model.ItemConfig, fileName string) ([]by
 	return GetFileByPath(filePath)
 }
 
-func (r localRepo) UpdateFile(currentView *model.ItemConfig, fileName string, newFileContent []byte) (bool, error) {
+func (r localRepo) UpdateFile(ctx context.Context, currentView *model.ItemConfig, fileName string, newFileContent []byte) (bool, error) {
 	logging.LogDebugf("repo/localfs/UpdateFile(%v, '%s', ..)", currentView, fileName)
 	parentPath := currentView.Path
 	filePath := paths.GetFilePath(parentPath, fileName)
@@ -101,7 +103,7 @@ func (r localRepo) UpdateFile(currentView *model.ItemConfig, fileName string, ne
 	return true, nil
 }
 
-func (r localRepo) GetFileType(viewConfig *model.ItemConfig, parentPath, fileName string) (model.ItemType, error) {
+func (r localRepo) GetFileType(ctx context.Context, viewConfig *model.ItemConfig, parentPath, fileName string) (model.ItemType, error) {
 	logging.LogDebugf("repo/localfs/GetFileType(%v, '%s', '%s')", viewConfig, parentPath, fileName)
 	filePath := r.GetFilePath(parentPath, fileName)
 	return getFileTypeFromPath(filePath)
@@ -112,7 +114,7 @@ func (r localRepo) GetFilePath(parentPath, fileName string) string {
 	return paths.GetFilePath(parentPath, fileName)
 }
 
-func (r localRepo) CreateDir(viewConfig *model.ItemConfig, parentPath, dirName string) (bool, error) {
+func (r localRepo) CreateDir(ctx context.Context, viewConfig *model.ItemConfig, parentPath, dirName string) (bool, error) {
 	logging.LogDebugf("repo/localfs/CreateDir(%v, '%s', '%s')", viewConfig, parentPath, dirName)
 	fi, err := os.Lstat(parentPath)
 	if err != nil {
@@ -128,9 +130,9 @@ func (r localRepo) CreateDir(viewConfig *model.ItemConfig, parentPath, dirName s
 	return true, nil
 }
 
-func (r localRepo) Delete(currentView *model.ItemConfig, itemType model.ItemType, parentPath, fileName string) (bool, error) {
+func (r localRepo) Delete(ctx context.Context, currentView *model.ItemConfig, itemType model.ItemType, parentPath, fileName string) (bool, error) {
 	logging.LogDebugf("repo/localfs/Delete(%v, '%s', '%s' (%s))", currentView, parentPath, fileName, itemType)
-	fileType, err := r.GetFileType(currentView, parentPath, fileName)
+	fileType, err := r.GetFileType(ctx, currentView, parentPath, fileName)
 	if err != nil {
 		logging.LogDebugf("repo/localfs/Delete(), err: %v", err)
 		return false, err
@@ -150,7 +152,7 @@ func (r localRepo) Delete(currentView *model.ItemConfig, itemType model.ItemType
 			return false, err
 		}
 		for _, subFile := range subFiles {
-			r.Delete(currentView, model.ItemAny, filePath, subFile.Name())
+			r.Delete(ctx, currentView, model.ItemAny, filePath, subFile.Name())
 		}
 		os.Remove(filePath)
 	default:
@@ -212,7 +214,7 @@ file mode: %s`,
 	return []byte(result), nil
 }
 
-func (r localRepo) ExecConfig(itemConfig *model.ItemConfig) error {
+func (r localRepo) ExecConfig(ctx context.Context, itemConfig *model.ItemConfig) error {
 	return errs.Error("Can't exec configuration on local machine.")
 }
 
@@ -241,13 +243,212 @@ func (t Tree) FindChild(searchChild *Tree) *Tree {
 	return nil
 }
 
+// FindPath finds dir/file in this hierarchy with given path relative to the
+// root of the hierarchy (returned Tree can be used to replace found subtree).
+func (t *Tree) FindPath(pathFromRoot string) *Tree {
+	if t.PathFromRoot == pathFromRoot {
+		return t
+	}
+	for idx := range t.Children {
+		child := &t.Children[idx]
+		if child.PathFromRoot == pathFromRoot {
+			return child
+		}
+		if child.Dir && strings.HasPrefix(pathFromRoot, child.PathFromRoot+string(os.PathSeparator)) {
+			return child.FindPath(pathFromRoot)
+		}
+	}
+
+	return nil
+}
+
+// Files returns all files (not directories) from the directory hierarchy.
+func (t *Tree) Files() []*Tree {
+	if !t.Dir {
+		return []*Tree{t}
+	}
+	files := make([]*Tree, 0)
+	for idx := range t.Children {
+		files = append(files, t.Children[idx].Files()...)
+	}
+
+	return files
+}
+
+// RemovedChildren finds children from saved info which are not children of
+// this dir anymore (removed, renamed or changed from file to dir or vice versa).
+func (t Tree) RemovedChildren(anotherTree *Tree) []Tree {
+	removedChildren := make([]Tree, 0)
+	if anotherTree == nil {
+		return removedChildren
+	}
+	for _, oldChild := range anotherTree.Children {
+		if t.FindChild(&oldChild) == nil {
+			removedChildren = append(removedChildren, oldChild)
+		}
+	}
+
+	return removedChildren
+}
+
 // FileChanged check if this file is new or changed file comparing to saved info.
 func (t Tree) FileChanged(anotherTree *Tree) bool {
 	return anotherTree == nil || t.ModTime != anotherTree.ModTime
 }
 
-// LoadTree loads directory hierarchy information into Tree object.
-func LoadTree(pathFromRoot, filePath string) (Tree, error) {
+// IgnoreFileName is name of the file in the synced directory containing
+// gitignore-style patterns of files & directories which are not synced.
+const IgnoreFileName = ".dpcmderignore"
+
+// IgnoreRules contains gitignore-style rules used to skip files & directories
+// when loading Tree.
+type IgnoreRules struct {
+	patterns []ignorePattern
+}
+
+// ignorePattern is one compiled gitignore-style pattern.
+type ignorePattern struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// NewIgnoreRules creates ignore rules from gitignore-style patterns - blank
+// lines and lines starting with '#' are skipped.
+func NewIgnoreRules(patterns []string) *IgnoreRules {
+	rules := IgnoreRules{patterns: make([]ignorePattern, 0, len(patterns))}
+	for _, pattern := range patterns {
+		if ip, ok := compileIgnorePattern(pattern); ok {
+			rules.patterns = append(rules.patterns, ip)
+		}
+	}
+
+	return &rules
+}
+
+// LoadIgnoreRules creates ignore rules from given patterns and patterns read
+// from the IgnoreFileName file in the dirPath directory (if file exists).
+func LoadIgnoreRules(dirPath string, patterns []string) (*IgnoreRules, error) {
+	allPatterns := append([]string{}, patterns...)
+	ignoreFileBytes, err := ioutil.ReadFile(paths.GetFilePath(dirPath, IgnoreFileName))
+	switch {
+	case err == nil:
+		allPatterns = append(allPatterns, strings.Split(string(ignoreFileBytes), "\n")...)
+	case !os.IsNotExist(err):
+		logging.LogDebug("repo/localfs/LoadIgnoreRules(), err: ", err)
+		return NewIgnoreRules(patterns), err
+	}
+
+	return NewIgnoreRules(allPatterns), nil
+}
+
+// Ignored checks if file or directory with given path (relative to the root
+// directory) should be ignored - last pattern matching path wins.
+func (ir *IgnoreRules) Ignored(pathFromRoot string, dir bool) bool {
+	if ir == nil || pathFromRoot == "" {
+		return false
+	}
+	slashPath := filepath.ToSlash(pathFromRoot)
+	ignored := false
+	for _, pattern := range ir.patterns {
+		if pattern.dirOnly && !dir {
+			continue
+		}
+		if pattern.re.MatchString(slashPath) {
+			ignored = !pattern.negate
+		}
+	}
+
+	return ignored
+}
+
+// IgnoredFile checks if file with given path (relative to the root directory)
+// or any of its parent directories should be ignored.
+func (ir *IgnoreRules) IgnoredFile(pathFromRoot string) bool {
+	dirPath := ""
+	for _, name := range strings.Split(filepath.ToSlash(filepath.Dir(pathFromRoot)), "/") {
+		if name == "." {
+			break
+		}
+		dirPath = paths.GetFilePath(dirPath, name)
+		if ir.Ignored(dirPath, true) {
+			return true
+		}
+	}
+
+	return ir.Ignored(pathFromRoot, false)
+}
+
+// compileIgnorePattern converts gitignore-style pattern to regular expression.
+func compileIgnorePattern(pattern string) (ignorePattern, bool) {
+	result := ignorePattern{}
+	pattern = strings.TrimRight(pattern, " \t\r")
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return result, false
+	}
+	if strings.HasPrefix(pattern, "!") {
+		result.negate = true
+		pattern = pattern[1:]
+	}
+	pattern = strings.TrimPrefix(pattern, "\\")
+	if strings.HasSuffix(pattern, "/") {
+		result.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	if pattern == "" {
+		return result, false
+	}
+
+	// Pattern containing separator is relative to the root directory,
+	// otherwise it matches name on any level.
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var reBuilder strings.Builder
+	if anchored {
+		reBuilder.WriteString("^")
+	} else {
+		reBuilder.WriteString("^(?:.*/)?")
+	}
+	for idx := 0; idx < len(pattern); idx++ {
+		switch {
+		case strings.HasPrefix(pattern[idx:], "**/"):
+			reBuilder.WriteString("(?:.*/)?")
+			idx += 2
+		case strings.HasPrefix(pattern[idx:], "**"):
+			reBuilder.WriteString(".*")
+			idx++
+		case pattern[idx] == '*':
+			reBuilder.WriteString("[^/]*")
+		case pattern[idx] == '?':
+			reBuilder.WriteString("[^/]")
+		case pattern[idx] == '[' && strings.Contains(pattern[idx+1:], "]"):
+			classEndIdx := idx + 1 + strings.Index(pattern[idx+1:], "]")
+			class := pattern[idx+1 : classEndIdx]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			reBuilder.WriteString("[" + strings.ReplaceAll(class, "\\", "\\\\") + "]")
+			idx = classEndIdx
+		default:
+			reBuilder.WriteString(regexp.QuoteMeta(pattern[idx : idx+1]))
+		}
+	}
+	reBuilder.WriteString("$")
+
+	re, err := regexp.Compile(reBuilder.String())
+	if err != nil {
+		logging.LogDebugf("repo/localfs/compileIgnorePattern('%s'), err: %v", pattern, err)
+		return result, false
+	}
+	result.re = re
+
+	return result, true
+}
+
+// LoadTree loads directory hierarchy information into Tree object, files &
+// directories matching ignoreRules (if given) are skipped.
+func LoadTree(pathFromRoot, filePath string, ignoreRules *IgnoreRules) (Tree, error) {
 	tree := Tree{}
 	var errorMsg string
 
@@ -255,6 +456,7 @@ func LoadTree(pathFromRoot, filePath string) (Tree, error) {
 	if err != nil {
 		errorMsg = fmt.Sprintf("repo.localfs.LoadTree('%s', '%s'): %s", pathFromRoot, filePath, err.Error())
 		logging.LogDebug("repo.localfs.LoadTree(), err: ", err)
+		return tree, errs.Error(errorMsg)
 	}
 
 	if fi.IsDir() {
@@ -276,12 +478,17 @@ func LoadTree(pathFromRoot, filePath string) (Tree, error) {
 			logging.LogDebug("repo.localfs.LoadTree(): ", err)
 		}
 
-		tree.Children = make([]Tree, len(files))
+		tree.Children = make([]Tree, 0, len(files))
 		for i := 0; i < len(files); i++ {
 			file := files[i]
 			childPath := paths.GetFilePath(filePath, file.Name())
 			childRelPath := paths.GetFilePath(pathFromRoot, file.Name())
-			tree.Children[i], err = LoadTree(childRelPath, childPath)
+			if ignoreRules.Ignored(childRelPath, file.IsDir()) {
+				logging.LogTracef("repo.localfs.LoadTree(), ignored: '%s'", childRelPath)
+				continue
+			}
+			child, err := LoadTree(childRelPath, childPath, ignoreRules)
+			tree.Children = append(tree.Children, child)
 			if err != nil {
 				errorMsg = fmt.Sprintf("repo.localfs.LoadTree('%s', '%s'): %s", pathFromRoot, filePath, err.Error())
 				logging.LogDebug("repo.localfs.LoadTree(): ", err)
@@ -6,6 +6,9 @@ import (
 	"github.com/croz-ltd/dpcmder/model"
 	"github.com/croz-ltd/dpcmder/utils/assert"
 	"github.com/croz-ltd/dpcmder/utils/errs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -79,6 +82,53 @@ func TestTreeFindChild(t *testing.T) {
 	assert.DeepEqual(t, "FindChild()", treeWithWinner.FindChild(&searchChild), &wantWinner)
 }
 
+func TestTreeFindPath(t *testing.T) {
+	subDir := Tree{Dir: true, Name: "sub", PathFromRoot: "dir/sub"}
+	dir := Tree{Dir: true, Name: "dir", PathFromRoot: "dir",
+		Children: []Tree{{Name: "a.xml", PathFromRoot: "dir/a.xml"}, subDir}}
+	root := Tree{Dir: true, Children: []Tree{{Name: "b.xml", PathFromRoot: "b.xml"}, dir}}
+
+	assert.Equals(t, "FindPath", root.FindPath(""), &root)
+	assert.Equals(t, "FindPath", root.FindPath("dir"), &root.Children[1])
+	assert.Equals(t, "FindPath", root.FindPath(filepath.Join("dir", "sub")), &root.Children[1].Children[1])
+	assert.Equals(t, "FindPath", root.FindPath("b.xml"), &root.Children[0])
+	assert.True(t, "FindPath", root.FindPath("missing") == nil)
+	assert.True(t, "FindPath", root.FindPath(filepath.Join("b.xml", "x")) == nil)
+}
+
+func TestTreeFiles(t *testing.T) {
+	dir := Tree{Dir: true, Name: "dir", PathFromRoot: "dir",
+		Children: []Tree{{Name: "a.xml", PathFromRoot: "dir/a.xml"}, {Dir: true, Name: "empty", PathFromRoot: "dir/empty"}}}
+	root := Tree{Dir: true, Children: []Tree{{Name: "b.xml", PathFromRoot: "b.xml"}, dir}}
+
+	files := root.Files()
+	assert.Equals(t, "Files", len(files), 2)
+	assert.Equals(t, "Files", files[0], &root.Children[0])
+	assert.Equals(t, "Files", files[1], &root.Children[1].Children[0])
+}
+
+func TestTreeRemovedChildren(t *testing.T) {
+	treeOld := Tree{Dir: true, Name: "root", Children: []Tree{
+		Tree{Dir: false, Name: "kept-file"},
+		Tree{Dir: false, Name: "removed-file"},
+		Tree{Dir: true, Name: "removed-dir"},
+		Tree{Dir: false, Name: "file-to-dir"},
+	}}
+	tree := Tree{Dir: true, Name: "root", Children: []Tree{
+		Tree{Dir: false, Name: "kept-file"},
+		Tree{Dir: true, Name: "file-to-dir"},
+		Tree{Dir: false, Name: "new-file"},
+	}}
+	want := []Tree{
+		Tree{Dir: false, Name: "removed-file"},
+		Tree{Dir: true, Name: "removed-dir"},
+		Tree{Dir: false, Name: "file-to-dir"},
+	}
+
+	assert.DeepEqual(t, "RemovedChildren()", tree.RemovedChildren(&treeOld), want)
+	assert.DeepEqual(t, "RemovedChildren()", tree.RemovedChildren(nil), []Tree{})
+}
+
 func TestTreeFileChanged(t *testing.T) {
 	time1 := time.Now()
 	time1a := time1.Add(time.Minute * 0)
@@ -92,3 +142,88 @@ func TestTreeFileChanged(t *testing.T) {
 	assert.DeepEqual(t, "FileChanged()", tree1.FileChanged(&tree2), true)
 	assert.DeepEqual(t, "FileChanged()", tree1.FileChanged(&tree3), true)
 }
+
+func TestIgnoreRulesIgnored(t *testing.T) {
+	rules := NewIgnoreRules([]string{
+		"# comment",
+		"",
+		".git/",
+		"*.swp",
+		"/build",
+		"docs/*.md",
+		"**/tmp/**",
+		"node_modules/",
+		"*.log",
+		"!keep.log",
+		"file[0-9].txt",
+	})
+	testDataMatrix := []struct {
+		path    string
+		dir     bool
+		ignored bool
+	}{
+		{"", true, false},
+		{".git", true, true},
+		{"sub/.git", true, true},
+		{".git", false, false},
+		{"test.xsl.swp", false, true},
+		{"sub/test.xsl.swp", false, true},
+		{"test.xsl", false, false},
+		{"build", true, true},
+		{"sub/build", true, false},
+		{"docs/readme.md", false, true},
+		{"docs/sub/readme.md", false, false},
+		{"a/tmp/b/c.xsl", false, true},
+		{"tmp/c.xsl", false, true},
+		{"node_modules", true, true},
+		{"error.log", false, true},
+		{"keep.log", false, false},
+		{"file1.txt", false, true},
+		{"fileA.txt", false, false},
+		{"# comment", false, false},
+	}
+	for _, testCase := range testDataMatrix {
+		assert.Equals(t, fmt.Sprintf("Ignored('%s', %t)", testCase.path, testCase.dir),
+			rules.Ignored(testCase.path, testCase.dir), testCase.ignored)
+	}
+
+	var nilRules *IgnoreRules
+	assert.False(t, "Ignored() nil", nilRules.Ignored("test.swp", false))
+}
+
+func TestIgnoreRulesIgnoredFile(t *testing.T) {
+	rules := NewIgnoreRules([]string{"node_modules/", "*.swp", "/build"})
+	assert.True(t, "IgnoredFile", rules.IgnoredFile(filepath.Join("node_modules", "lib", "x.js")))
+	assert.True(t, "IgnoredFile", rules.IgnoredFile(filepath.Join("src", "a.xsl.swp")))
+	assert.True(t, "IgnoredFile", rules.IgnoredFile(filepath.Join("build", "a.xsl")))
+	assert.False(t, "IgnoredFile", rules.IgnoredFile(filepath.Join("src", "build", "a.xsl")))
+	assert.False(t, "IgnoredFile", rules.IgnoredFile("a.xsl"))
+}
+
+func TestLoadIgnoreRulesAndTree(t *testing.T) {
+	rootDir := t.TempDir()
+	for _, dir := range []string{"src", ".git", "node_modules/lib"} {
+		assert.Nil(t, "MkdirAll", os.MkdirAll(filepath.Join(rootDir, dir), 0755))
+	}
+	for _, file := range []string{IgnoreFileName, "src/a.xsl", "src/a.xsl.swp", ".git/HEAD", "node_modules/lib/x.js"} {
+		assert.Nil(t, "WriteFile", ioutil.WriteFile(filepath.Join(rootDir, file), []byte("node_modules/\n"), 0644))
+	}
+
+	rules, err := LoadIgnoreRules(rootDir, []string{".git/", "*.swp"})
+	assert.Nil(t, "LoadIgnoreRules", err)
+	tree, err := LoadTree("", rootDir, rules)
+	assert.Nil(t, "LoadTree", err)
+
+	childNames := make([]string, 0)
+	for _, child := range tree.Children {
+		childNames = append(childNames, child.Name)
+		for _, grandChild := range child.Children {
+			childNames = append(childNames, child.Name+"/"+grandChild.Name)
+		}
+	}
+	assert.DeepEqual(t, "LoadTree", childNames, []string{IgnoreFileName, "src", "src/a.xsl"})
+
+	tree, err = LoadTree("", rootDir, nil)
+	assert.Nil(t, "LoadTree", err)
+	assert.Equals(t, "LoadTree", len(tree.Children), 4)
+}
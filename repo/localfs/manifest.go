@@ -0,0 +1,192 @@
+package localfs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/croz-ltd/dpcmder/utils/errs"
+	"github.com/croz-ltd/dpcmder/utils/logging"
+)
+
+// Manifest contains content hashes of synced local files as they were last
+// uploaded to (or found on) the DataPower. It is persisted between dpcmder runs
+// so unchanged files don't have to be uploaded or compared again.
+type Manifest struct {
+	SyncID string
+	Files  map[string]ManifestEntry
+	path   string
+	dirty  bool
+}
+
+// ManifestEntry contains content hash of the synced file and its modification
+// time when the hash was calculated. When two-way sync is used size and
+// modification time of the DataPower file are saved too.
+type ManifestEntry struct {
+	Hash       string
+	ModTime    time.Time
+	DpSize     string `json:",omitempty"`
+	DpModified string `json:",omitempty"`
+}
+
+// ManifestFileName returns name of the manifest file for the given sync id
+// (string identifying synced local and DataPower directories).
+func ManifestFileName(syncID string) string {
+	sum := sha256.Sum256([]byte(syncID))
+	return hex.EncodeToString(sum[:8]) + ".json"
+}
+
+// LoadManifest reads manifest from the file. If file doesn't exist, can't be
+// read or belongs to another sync id empty manifest (saved to the same file)
+// is returned.
+func LoadManifest(filePath, syncID string) (*Manifest, error) {
+	logging.LogDebugf("repo/localfs/LoadManifest('%s', '%s')", filePath, syncID)
+	emptyManifest := &Manifest{SyncID: syncID, Files: make(map[string]ManifestEntry), path: filePath}
+	manifestBytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return emptyManifest, nil
+		}
+		return emptyManifest, err
+	}
+
+	manifest := &Manifest{}
+	err = json.Unmarshal(manifestBytes, manifest)
+	if err != nil {
+		return emptyManifest, errs.Errorf("Can't parse sync manifest '%s': %v", filePath, err)
+	}
+	if manifest.SyncID != syncID || manifest.Files == nil {
+		return emptyManifest, nil
+	}
+	manifest.path = filePath
+
+	return manifest, nil
+}
+
+// Save writes manifest to its file if it was changed since loaded or saved.
+func (m *Manifest) Save() error {
+	if !m.dirty || m.path == "" {
+		return nil
+	}
+	logging.LogDebugf("repo/localfs/Manifest.Save('%s'), files: %d", m.path, len(m.Files))
+	manifestBytes, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write to temporary file first so manifest is never left half-written.
+	tmpPath := m.path + ".tmp"
+	err = ioutil.WriteFile(tmpPath, manifestBytes, 0644)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmpPath, m.path)
+	if err != nil {
+		return err
+	}
+	m.dirty = false
+
+	return nil
+}
+
+// ContentUnchanged checks if content of the local file is the same as recorded
+// in the manifest. File hash is calculated only if modification time of the
+// file changed. Returns content hash of the file to be recorded with Set.
+func (m *Manifest) ContentUnchanged(tree *Tree) (string, bool, error) {
+	key := manifestKey(tree.PathFromRoot)
+	entry, found := m.Files[key]
+	if found && entry.ModTime.Equal(tree.ModTime) {
+		return entry.Hash, true, nil
+	}
+
+	hash, err := FileHash(tree.Path)
+	if err != nil {
+		return "", false, err
+	}
+	if found && entry.Hash == hash {
+		// File is touched but not changed - remember new modification time.
+		entry.ModTime = tree.ModTime
+		m.Files[key] = entry
+		m.dirty = true
+		return hash, true, nil
+	}
+
+	return hash, false, nil
+}
+
+// Set records content hash of the local file synced to the DataPower.
+func (m *Manifest) Set(tree *Tree, hash string) {
+	m.Files[manifestKey(tree.PathFromRoot)] = ManifestEntry{Hash: hash, ModTime: tree.ModTime}
+	m.dirty = true
+}
+
+// SetSynced records content hash of the local file and size & modification
+// time of the DataPower file after file is synced in two-way sync.
+func (m *Manifest) SetSynced(pathFromRoot string, modTime time.Time, hash, dpSize, dpModified string) {
+	m.Files[manifestKey(pathFromRoot)] = ManifestEntry{Hash: hash, ModTime: modTime,
+		DpSize: dpSize, DpModified: dpModified}
+	m.dirty = true
+}
+
+// Entry returns manifest entry of the file.
+func (m *Manifest) Entry(pathFromRoot string) (ManifestEntry, bool) {
+	entry, found := m.Files[manifestKey(pathFromRoot)]
+	return entry, found
+}
+
+// Paths returns sorted paths of all files from the manifest.
+func (m *Manifest) Paths() []string {
+	filePaths := make([]string, 0, len(m.Files))
+	for fileKey := range m.Files {
+		filePaths = append(filePaths, filepath.FromSlash(fileKey))
+	}
+	sort.Strings(filePaths)
+
+	return filePaths
+}
+
+// Remove removes file or directory (with all files in it) from the manifest.
+func (m *Manifest) Remove(pathFromRoot string) {
+	key := manifestKey(pathFromRoot)
+	for fileKey := range m.Files {
+		if fileKey == key || strings.HasPrefix(fileKey, key+"/") {
+			delete(m.Files, fileKey)
+			m.dirty = true
+		}
+	}
+}
+
+// FileHash calculates SHA-256 hash of the local file content.
+func FileHash(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// ContentHash calculates SHA-256 hash of the file content.
+func ContentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// manifestKey converts path relative to the synced directory to the key used
+// in the manifest (same on all platforms).
+func manifestKey(pathFromRoot string) string {
+	return filepath.ToSlash(pathFromRoot)
+}
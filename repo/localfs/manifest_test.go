@@ -0,0 +1,86 @@
+package localfs
+
+import (
+	"github.com/croz-ltd/dpcmder/utils/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestManifestFileName(t *testing.T) {
+	assert.Equals(t, "ManifestFileName", ManifestFileName("a"), ManifestFileName("a"))
+	assert.True(t, "ManifestFileName", ManifestFileName("a") != ManifestFileName("b"))
+	assert.Equals(t, "ManifestFileName", filepath.Ext(ManifestFileName("a")), ".json")
+}
+
+func TestManifestContentUnchanged(t *testing.T) {
+	rootDir := t.TempDir()
+	filePath := filepath.Join(rootDir, "a.xsl")
+	assert.Nil(t, "WriteFile", ioutil.WriteFile(filePath, []byte("content"), 0644))
+	tree, err := LoadTree("a.xsl", filePath, nil)
+	assert.Nil(t, "LoadTree", err)
+
+	manifest, err := LoadManifest(filepath.Join(rootDir, "manifest.json"), "sync")
+	assert.Nil(t, "LoadManifest", err)
+	hash, unchanged, err := manifest.ContentUnchanged(&tree)
+	assert.Nil(t, "ContentUnchanged", err)
+	assert.False(t, "ContentUnchanged", unchanged)
+	assert.Equals(t, "ContentUnchanged", hash,
+		"ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73")
+	manifest.Set(&tree, hash)
+
+	_, unchanged, err = manifest.ContentUnchanged(&tree)
+	assert.Nil(t, "ContentUnchanged", err)
+	assert.True(t, "ContentUnchanged", unchanged)
+
+	// Touched file with the same content.
+	touchTime := tree.ModTime.Add(time.Minute)
+	assert.Nil(t, "Chtimes", os.Chtimes(filePath, touchTime, touchTime))
+	tree, _ = LoadTree("a.xsl", filePath, nil)
+	_, unchanged, err = manifest.ContentUnchanged(&tree)
+	assert.Nil(t, "ContentUnchanged", err)
+	assert.True(t, "ContentUnchanged", unchanged)
+	assert.True(t, "ContentUnchanged", manifest.Files["a.xsl"].ModTime.Equal(touchTime))
+
+	assert.Nil(t, "WriteFile", ioutil.WriteFile(filePath, []byte("changed"), 0644))
+	tree, _ = LoadTree("a.xsl", filePath, nil)
+	_, unchanged, err = manifest.ContentUnchanged(&tree)
+	assert.Nil(t, "ContentUnchanged", err)
+	assert.False(t, "ContentUnchanged", unchanged)
+}
+
+func TestManifestSaveLoadRemove(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), "manifest.json")
+	manifest, err := LoadManifest(manifestPath, "sync")
+	assert.Nil(t, "LoadManifest", err)
+	modTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, pathFromRoot := range []string{"a.xsl", filepath.Join("dir", "b.xsl"), filepath.Join("dir2", "c.xsl")} {
+		manifest.Set(&Tree{PathFromRoot: pathFromRoot, ModTime: modTime}, "hash-"+pathFromRoot)
+	}
+	manifest.Remove("dir")
+	manifest.SetSynced("d.xsl", modTime, "hash-d.xsl", "12", "2020-01-02 03:04:05")
+	entry, found := manifest.Entry("d.xsl")
+	assert.True(t, "Entry", found)
+	assert.Equals(t, "Entry", entry.DpModified, "2020-01-02 03:04:05")
+	assert.DeepEqual(t, "Paths", manifest.Paths(), []string{"a.xsl", "d.xsl", filepath.Join("dir2", "c.xsl")})
+	assert.Nil(t, "Save", manifest.Save())
+
+	loaded, err := LoadManifest(manifestPath, "sync")
+	assert.Nil(t, "LoadManifest", err)
+	assert.DeepEqual(t, "LoadManifest", loaded.Files, map[string]ManifestEntry{
+		"a.xsl":      {Hash: "hash-a.xsl", ModTime: modTime},
+		"dir2/c.xsl": {Hash: "hash-" + filepath.Join("dir2", "c.xsl"), ModTime: modTime},
+		"d.xsl":      {Hash: "hash-d.xsl", ModTime: modTime, DpSize: "12", DpModified: "2020-01-02 03:04:05"},
+	})
+
+	loaded, err = LoadManifest(manifestPath, "another-sync")
+	assert.Nil(t, "LoadManifest", err)
+	assert.Equals(t, "LoadManifest", len(loaded.Files), 0)
+
+	assert.Nil(t, "WriteFile", ioutil.WriteFile(manifestPath, []byte("{"), 0644))
+	loaded, err = LoadManifest(manifestPath, "sync")
+	assert.NotNil(t, "LoadManifest", err)
+	assert.Equals(t, "LoadManifest", len(loaded.Files), 0)
+}
@@ -0,0 +1,82 @@
+package localfs
+
+// SyncAction is an action required to sync one file in two-way sync.
+type SyncAction string
+
+// Actions required to sync one file in two-way sync.
+const (
+	// SyncNone - file is not changed on either side.
+	SyncNone = SyncAction("none")
+	// SyncPush - local file should be uploaded to the DataPower.
+	SyncPush = SyncAction("push")
+	// SyncPull - DataPower file should be downloaded to the local directory.
+	SyncPull = SyncAction("pull")
+	// SyncDeleteDp - file removed locally should be deleted from the DataPower.
+	SyncDeleteDp = SyncAction("delete-dp")
+	// SyncDeleteLocal - file removed from the DataPower should be deleted locally.
+	SyncDeleteLocal = SyncAction("delete-local")
+	// SyncForget - file removed from both sides should be removed from manifest.
+	SyncForget = SyncAction("forget")
+	// SyncCompare - file changed on both sides, conflict if content differs.
+	SyncCompare = SyncAction("compare")
+	// SyncConflict - file changed on one side and removed from the other side.
+	SyncConflict = SyncAction("conflict")
+)
+
+// FileSyncState contains state of one file on both sides of the two-way sync
+// compared to the state saved in the manifest on the last sync.
+type FileSyncState struct {
+	Known        bool
+	LocalExists  bool
+	LocalChanged bool
+	DpExists     bool
+	DpChanged    bool
+}
+
+// TwoWayAction finds action required to sync the file in two-way sync. Files
+// removed on one side are deleted from the other side only if deleteRemoved
+// is set, otherwise they are copied back.
+func TwoWayAction(state FileSyncState, deleteRemoved bool) SyncAction {
+	switch {
+	case !state.LocalExists && !state.DpExists:
+		if state.Known {
+			return SyncForget
+		}
+		return SyncNone
+	case !state.Known && state.LocalExists && state.DpExists:
+		return SyncCompare
+	case !state.Known && state.LocalExists:
+		return SyncPush
+	case !state.Known:
+		return SyncPull
+	case state.LocalExists && state.DpExists:
+		switch {
+		case state.LocalChanged && state.DpChanged:
+			return SyncCompare
+		case state.LocalChanged:
+			return SyncPush
+		case state.DpChanged:
+			return SyncPull
+		default:
+			return SyncNone
+		}
+	case state.LocalExists:
+		switch {
+		case state.LocalChanged:
+			return SyncConflict
+		case deleteRemoved:
+			return SyncDeleteLocal
+		default:
+			return SyncPush
+		}
+	default:
+		switch {
+		case state.DpChanged:
+			return SyncConflict
+		case deleteRemoved:
+			return SyncDeleteDp
+		default:
+			return SyncPull
+		}
+	}
+}
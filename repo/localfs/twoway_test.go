@@ -0,0 +1,36 @@
+package localfs
+
+import (
+	"fmt"
+	"github.com/croz-ltd/dpcmder/utils/assert"
+	"testing"
+)
+
+func TestTwoWayAction(t *testing.T) {
+	testDataMatrix := []struct {
+		state         FileSyncState
+		deleteRemoved bool
+		want          SyncAction
+	}{
+		{FileSyncState{Known: true, LocalExists: true, DpExists: true}, false, SyncNone},
+		{FileSyncState{Known: true, LocalExists: true, LocalChanged: true, DpExists: true}, false, SyncPush},
+		{FileSyncState{Known: true, LocalExists: true, DpExists: true, DpChanged: true}, false, SyncPull},
+		{FileSyncState{Known: true, LocalExists: true, LocalChanged: true, DpExists: true, DpChanged: true}, false, SyncCompare},
+		{FileSyncState{LocalExists: true, DpExists: true}, false, SyncCompare},
+		{FileSyncState{LocalExists: true}, true, SyncPush},
+		{FileSyncState{DpExists: true}, true, SyncPull},
+		{FileSyncState{}, false, SyncNone},
+		{FileSyncState{Known: true}, false, SyncForget},
+		{FileSyncState{Known: true, LocalExists: true}, false, SyncPush},
+		{FileSyncState{Known: true, LocalExists: true}, true, SyncDeleteLocal},
+		{FileSyncState{Known: true, LocalExists: true, LocalChanged: true}, true, SyncConflict},
+		{FileSyncState{Known: true, DpExists: true}, false, SyncPull},
+		{FileSyncState{Known: true, DpExists: true}, true, SyncDeleteDp},
+		{FileSyncState{Known: true, DpExists: true, DpChanged: true}, true, SyncConflict},
+	}
+	for _, testCase := range testDataMatrix {
+		got := TwoWayAction(testCase.state, testCase.deleteRemoved)
+		assert.Equals(t, fmt.Sprintf("TwoWayAction(%+v, %t)", testCase.state, testCase.deleteRemoved),
+			got, testCase.want)
+	}
+}
@@ -0,0 +1,124 @@
+package localfs
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/croz-ltd/dpcmder/utils/logging"
+)
+
+// Watcher watches local directory hierarchy using filesystem notifications
+// and sends paths of directories with changes (relative to the watched
+// directory) to the Changes channel. Changes are debounced - paths are sent
+// when no new change is detected for the debounce period. Changes channel is
+// closed if notifications stop working (so caller can fall back to polling).
+type Watcher struct {
+	Changes  chan []string
+	dirPath  string
+	debounce time.Duration
+	notifier *notifier
+	done     chan struct{}
+}
+
+// maxDebounceDelays limits number of debounce periods changes can be delayed
+// when changes are detected continuously.
+const maxDebounceDelays = 10
+
+// NewWatcher starts watching local directory hierarchy (skipping directories
+// matching ignoreRules). Returns error if filesystem notifications are not
+// supported or can't be set up.
+func NewWatcher(dirPath string, debounce time.Duration, ignoreRules *IgnoreRules) (*Watcher, error) {
+	logging.LogDebugf("repo/localfs/NewWatcher('%s', %v)", dirPath, debounce)
+	n, err := newNotifier(dirPath, ignoreRules)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{Changes: make(chan []string),
+		dirPath: dirPath, debounce: debounce, notifier: n, done: make(chan struct{})}
+	go w.run()
+
+	return w, nil
+}
+
+// Close stops watching local directory hierarchy.
+func (w *Watcher) Close() error {
+	logging.LogDebugf("repo/localfs/Watcher.Close('%s')", w.dirPath)
+	close(w.done)
+	return w.notifier.close()
+}
+
+// run collects changed directories reported by notifier and sends them to the
+// Changes channel after debounce period.
+func (w *Watcher) run() {
+	defer close(w.Changes)
+	pending := make(map[string]bool)
+	var firstChange time.Time
+	var timer <-chan time.Time
+	for {
+		select {
+		case changedDir, ok := <-w.notifier.events:
+			if !ok {
+				logging.LogDebugf("repo/localfs/Watcher.run('%s') - notifications stopped.", w.dirPath)
+				return
+			}
+			if len(pending) == 0 {
+				firstChange = time.Now()
+			}
+			pending[w.relativePath(changedDir)] = true
+			delay := w.debounce
+			if maxDelay := time.Until(firstChange.Add(w.debounce * maxDebounceDelays)); maxDelay < delay {
+				delay = maxDelay
+			}
+			timer = time.After(delay)
+		case <-timer:
+			changedDirs := make([]string, 0, len(pending))
+			for changedDir := range pending {
+				changedDirs = append(changedDirs, changedDir)
+			}
+			pending = make(map[string]bool)
+			timer = nil
+			select {
+			case w.Changes <- compactDirPaths(changedDirs):
+			case <-w.done:
+				return
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// relativePath converts path of directory to path relative to the watched
+// directory ("" for the watched directory itself).
+func (w *Watcher) relativePath(dirPath string) string {
+	relPath, err := filepath.Rel(w.dirPath, dirPath)
+	if err != nil || relPath == "." {
+		return ""
+	}
+	return relPath
+}
+
+// compactDirPaths sorts directory paths and removes paths of directories
+// contained in other directories from the list.
+func compactDirPaths(dirPaths []string) []string {
+	sort.Strings(dirPaths)
+	result := make([]string, 0, len(dirPaths))
+	for _, dirPath := range dirPaths {
+		contained := false
+		for _, parentPath := range result {
+			if parentPath == "" || dirPath == parentPath ||
+				strings.HasPrefix(dirPath, parentPath+string(filepath.Separator)) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			result = append(result, dirPath)
+		}
+	}
+
+	return result
+}
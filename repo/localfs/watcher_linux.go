@@ -0,0 +1,149 @@
+package localfs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/croz-ltd/dpcmder/utils/logging"
+	"github.com/croz-ltd/dpcmder/utils/paths"
+	"golang.org/x/sys/unix"
+)
+
+// notifierMask contains inotify events which signal change of the watched
+// directory content.
+const notifierMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_MODIFY | unix.IN_CLOSE_WRITE |
+	unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_ATTRIB
+
+// notifier sends path of the directory whenever content of any directory in
+// the watched hierarchy changes (uses Linux inotify).
+type notifier struct {
+	fd          int
+	file        *os.File
+	rootPath    string
+	ignoreRules *IgnoreRules
+	mu          sync.Mutex
+	watches     map[int]string
+	events      chan string
+	done        chan struct{}
+}
+
+// newNotifier creates inotify instance and adds watches for all directories
+// in the hierarchy.
+func newNotifier(rootPath string, ignoreRules *IgnoreRules) (*notifier, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		logging.LogDebug("repo/localfs/newNotifier(), err: ", err)
+		return nil, err
+	}
+
+	// File.Fd() shouldn't be used since it switches file to the blocking mode
+	// and Read() couldn't be interrupted by Close().
+	n := &notifier{fd: fd, file: os.NewFile(uintptr(fd), "inotify"),
+		rootPath: rootPath, ignoreRules: ignoreRules,
+		watches: make(map[int]string), events: make(chan string, 100), done: make(chan struct{})}
+	err = n.addWatches(rootPath)
+	if err != nil {
+		n.file.Close()
+		return nil, err
+	}
+	go n.readEvents()
+
+	return n, nil
+}
+
+// addWatches adds inotify watch for the directory and all its subdirectories
+// which are not ignored.
+func (n *notifier) addWatches(dirPath string) error {
+	wd, err := unix.InotifyAddWatch(n.fd, dirPath, notifierMask)
+	if err != nil {
+		logging.LogDebugf("repo/localfs/notifier.addWatches('%s'), err: %v", dirPath, err)
+		return err
+	}
+	n.mu.Lock()
+	n.watches[wd] = dirPath
+	n.mu.Unlock()
+
+	files, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+		childPath := paths.GetFilePath(dirPath, file.Name())
+		relPath, _ := filepath.Rel(n.rootPath, childPath)
+		if n.ignoreRules.Ignored(relPath, true) {
+			continue
+		}
+		err = n.addWatches(childPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readEvents reads inotify events and sends path of the changed directory to
+// the events channel until inotify file is closed.
+func (n *notifier) readEvents() {
+	defer close(n.events)
+	var buf [unix.SizeofInotifyEvent * 4096]byte
+	for {
+		readLen, err := n.file.Read(buf[:])
+		if err != nil {
+			logging.LogDebug("repo/localfs/notifier.readEvents(), err: ", err)
+			return
+		}
+
+		for offset := 0; offset+unix.SizeofInotifyEvent <= readLen; {
+			wd := int(int32(binary.NativeEndian.Uint32(buf[offset:])))
+			mask := binary.NativeEndian.Uint32(buf[offset+4:])
+			nameLen := int(binary.NativeEndian.Uint32(buf[offset+12:]))
+			nameStart := offset + unix.SizeofInotifyEvent
+			name := string(bytes.TrimRight(buf[nameStart:nameStart+nameLen], "\x00"))
+			offset = nameStart + nameLen
+
+			n.mu.Lock()
+			dirPath, found := n.watches[wd]
+			if mask&unix.IN_IGNORED != 0 {
+				delete(n.watches, wd)
+			}
+			n.mu.Unlock()
+
+			switch {
+			case mask&unix.IN_Q_OVERFLOW != 0:
+				// Events were lost - whole hierarchy should be checked.
+				dirPath, found = n.rootPath, true
+			case !found:
+				continue
+			case mask&unix.IN_ISDIR != 0 && mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0:
+				childPath := paths.GetFilePath(dirPath, name)
+				relPath, _ := filepath.Rel(n.rootPath, childPath)
+				if !n.ignoreRules.Ignored(relPath, true) {
+					if err := n.addWatches(childPath); err != nil {
+						logging.LogDebugf("repo/localfs/notifier.readEvents() - can't watch '%s': %v", childPath, err)
+					}
+				}
+			case mask&notifierMask == 0:
+				continue
+			}
+			select {
+			case n.events <- dirPath:
+			case <-n.done:
+				return
+			}
+		}
+	}
+}
+
+// close stops reading inotify events.
+func (n *notifier) close() error {
+	close(n.done)
+	return n.file.Close()
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package localfs
+
+import (
+	"github.com/croz-ltd/dpcmder/utils/errs"
+)
+
+// notifier is not implemented on this platform - polling is used instead.
+type notifier struct {
+	events chan string
+}
+
+// newNotifier returns error since filesystem notifications are not supported.
+func newNotifier(rootPath string, ignoreRules *IgnoreRules) (*notifier, error) {
+	return nil, errs.Error("Filesystem notifications are not supported on this platform.")
+}
+
+func (n *notifier) close() error {
+	return nil
+}
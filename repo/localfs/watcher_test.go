@@ -0,0 +1,64 @@
+package localfs
+
+import (
+	"github.com/croz-ltd/dpcmder/utils/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCompactDirPaths(t *testing.T) {
+	testDataMatrix := []struct {
+		dirPaths []string
+		want     []string
+	}{
+		{[]string{}, []string{}},
+		{[]string{"b", "a"}, []string{"a", "b"}},
+		{[]string{"a", filepath.Join("a", "b"), "ab"}, []string{"a", "ab"}},
+		{[]string{"a", "", "b"}, []string{""}},
+	}
+	for _, testCase := range testDataMatrix {
+		got := compactDirPaths(testCase.dirPaths)
+		assert.DeepEqual(t, "compactDirPaths", got, testCase.want)
+	}
+}
+
+func TestWatcherChanges(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Filesystem notifications are not supported on", runtime.GOOS)
+	}
+	rootDir := t.TempDir()
+	for _, dir := range []string{"src", ".git"} {
+		assert.Nil(t, "Mkdir", os.Mkdir(filepath.Join(rootDir, dir), 0755))
+	}
+	rules := NewIgnoreRules([]string{".git/"})
+	watcher, err := NewWatcher(rootDir, 50*time.Millisecond, rules)
+	assert.Nil(t, "NewWatcher", err)
+	defer watcher.Close()
+
+	waitChanges := func() []string {
+		t.Helper()
+		select {
+		case changedDirs := <-watcher.Changes:
+			return changedDirs
+		case <-time.After(5 * time.Second):
+			t.Fatal("Changes not received.")
+			return nil
+		}
+	}
+
+	assert.Nil(t, "WriteFile", ioutil.WriteFile(filepath.Join(rootDir, "src", "a.xsl"), []byte("a"), 0644))
+	assert.DeepEqual(t, "Changes", waitChanges(), []string{"src"})
+
+	assert.Nil(t, "Mkdir", os.Mkdir(filepath.Join(rootDir, "src", "sub"), 0755))
+	assert.DeepEqual(t, "Changes", waitChanges(), []string{"src"})
+	assert.Nil(t, "WriteFile", ioutil.WriteFile(filepath.Join(rootDir, "src", "sub", "b.xsl"), []byte("b"), 0644))
+	assert.DeepEqual(t, "Changes", waitChanges(), []string{filepath.Join("src", "sub")})
+
+	assert.Nil(t, "WriteFile", ioutil.WriteFile(filepath.Join(rootDir, ".git", "HEAD"), []byte("x"), 0644))
+	assert.Nil(t, "WriteFile", ioutil.WriteFile(filepath.Join(rootDir, "c.xsl"), []byte("c"), 0644))
+	assert.DeepEqual(t, "Changes", waitChanges(), []string{""})
+}
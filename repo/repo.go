@@ -3,23 +3,26 @@
 package repo
 
 import (
+	"context"
+
 	"github.com/croz-ltd/dpcmder/model"
 )
 
-// Repo is a common repository methods implemented by local filesystem and DataPower
+// Repo is a common repository methods implemented by local filesystem and DataPower,
+// context passed to methods can be used to cancel long running operations.
 type Repo interface {
 	String() string
 	GetInitialItem() (model.Item, error)
 	GetTitle(currentView *model.ItemConfig) string
-	GetList(currentView *model.ItemConfig) (model.ItemList, error)
+	GetList(ctx context.Context, currentView *model.ItemConfig) (model.ItemList, error)
 	InvalidateCache()
-	GetFile(currentView *model.ItemConfig, fileName string) ([]byte, error)
-	UpdateFile(currentView *model.ItemConfig, fileName string, newFileContent []byte) (bool, error)
-	GetFileType(currentView *model.ItemConfig, parentPath, fileName string) (model.ItemType, error)
+	GetFile(ctx context.Context, currentView *model.ItemConfig, fileName string) ([]byte, error)
+	UpdateFile(ctx context.Context, currentView *model.ItemConfig, fileName string, newFileContent []byte) (bool, error)
+	GetFileType(ctx context.Context, currentView *model.ItemConfig, parentPath, fileName string) (model.ItemType, error)
 	GetFilePath(parentPath, fileName string) string
-	CreateDir(viewConfig *model.ItemConfig, parentPath, dirName string) (bool, error)
-	Delete(currentView *model.ItemConfig, itemType model.ItemType, parentPath, fileName string) (bool, error)
+	CreateDir(ctx context.Context, viewConfig *model.ItemConfig, parentPath, dirName string) (bool, error)
+	Delete(ctx context.Context, currentView *model.ItemConfig, itemType model.ItemType, parentPath, fileName string) (bool, error)
 	GetViewConfigByPath(currentView *model.ItemConfig, dirPath string) (*model.ItemConfig, error)
 	GetItemInfo(itemConfig *model.ItemConfig) ([]byte, error)
-	ExecConfig(itemConfig *model.ItemConfig) error
+	ExecConfig(ctx context.Context, itemConfig *model.ItemConfig) error
 }
@@ -0,0 +1,273 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/croz-ltd/dpcmder/extprogs"
+	"github.com/croz-ltd/dpcmder/utils/errs"
+	"github.com/croz-ltd/dpcmder/utils/logging"
+)
+
+// jobState is the state of the background job.
+type jobState int
+
+const (
+	jobRunning jobState = iota
+	jobDone
+	jobFailed
+	jobCanceled
+)
+
+func (s jobState) String() string {
+	switch s {
+	case jobRunning:
+		return "running"
+	case jobDone:
+		return "done"
+	case jobFailed:
+		return "failed"
+	case jobCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// jobFunc runs the job and returns its result (nil if job has no result to
+// show), job progress is updated using updateProgressf with the given context.
+type jobFunc func(ctx context.Context) ([]byte, error)
+
+// job is long running DataPower action run in the background so user can
+// continue to work while it runs.
+type job struct {
+	id         int
+	name       string
+	resultName string
+	run        jobFunc
+	mutex      sync.Mutex
+	state      jobState
+	progress   string
+	result     []byte
+	err        error
+	started    time.Time
+	finished   time.Time
+	cancel     context.CancelFunc
+}
+
+// jobContextKey is the key of the job value in the context given to jobFunc.
+type jobContextKey struct{}
+
+var jobs []*job
+var jobsMutex sync.Mutex
+var lastJobID int
+
+// startJob adds new job to the job list and starts it, resultName is the file
+// name used when job result is shown (enables syntax highlighting in viewer).
+func startJob(name, resultName string, run jobFunc) *job {
+	jobsMutex.Lock()
+	lastJobID++
+	j := &job{id: lastJobID, name: name, resultName: resultName, run: run}
+	jobs = append(jobs, j)
+	jobsMutex.Unlock()
+	j.start()
+
+	return j
+}
+
+// jobList returns copy of the job list.
+func jobList() []*job {
+	jobsMutex.Lock()
+	defer jobsMutex.Unlock()
+	return append([]*job{}, jobs...)
+}
+
+// deleteJob removes finished job from the job list.
+func deleteJob(j *job) error {
+	if j.currentState() == jobRunning {
+		return errs.Errorf("Can't delete running job %d '%s'.", j.id, j.name)
+	}
+	jobsMutex.Lock()
+	defer jobsMutex.Unlock()
+	for idx, listJob := range jobs {
+		if listJob == j {
+			jobs = append(jobs[:idx], jobs[idx+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (j *job) start() {
+	logging.LogDebugf("ui/job.start(%d, '%s')", j.id, j.name)
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = context.WithValue(ctx, jobContextKey{}, j)
+	j.mutex.Lock()
+	j.state = jobRunning
+	j.progress = ""
+	j.result = nil
+	j.err = nil
+	j.started = time.Now()
+	j.finished = time.Time{}
+	j.cancel = cancel
+	j.mutex.Unlock()
+	updateStatusf("Job %d '%s' started, press 'b' to show jobs.", j.id, j.name)
+
+	go func() {
+		result, err := j.run(ctx)
+		cancel()
+		j.finish(result, err)
+	}()
+}
+
+func (j *job) finish(result []byte, err error) {
+	logging.LogDebugf("ui/job.finish(%d, '%s'), err: %v", j.id, j.name, err)
+	j.mutex.Lock()
+	j.result = result
+	j.err = err
+	j.finished = time.Now()
+	switch {
+	case err == nil:
+		j.state = jobDone
+	case errors.Is(err, context.Canceled):
+		j.state = jobCanceled
+	default:
+		j.state = jobFailed
+	}
+	state := j.state
+	j.mutex.Unlock()
+
+	if err != nil && state == jobFailed {
+		postStatusf("Job %d '%s' failed: %v", j.id, j.name, err)
+		return
+	}
+	postStatusf("Job %d '%s' %s.", j.id, j.name, state)
+}
+
+// stop cancels context of the running job.
+func (j *job) stop() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.cancel()
+}
+
+func (j *job) setProgress(progress string) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.progress = progress
+}
+
+func (j *job) currentState() jobState {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.state
+}
+
+func (j *job) String() string {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	info := j.progress
+	if j.err != nil {
+		info = j.err.Error()
+	}
+	return fmt.Sprintf("%3d %-8s %-8s %s - %s",
+		j.id, j.state, j.started.Format("15:04:05"), j.name, info)
+}
+
+// details returns job result or (when job has no result) job information.
+func (j *job) details() (string, []byte) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.result != nil {
+		return j.resultName, j.result
+	}
+	details := fmt.Sprintf("Job %d '%s'\n\nState: %s\nStarted: %s\n",
+		j.id, j.name, j.state, j.started.Format("2006-01-02 15:04:05"))
+	if !j.finished.IsZero() {
+		details = details + fmt.Sprintf("Finished: %s (%s)\n",
+			j.finished.Format("2006-01-02 15:04:05"), j.finished.Sub(j.started).Round(time.Second))
+	}
+	details = details + fmt.Sprintf("Progress: %s\n", j.progress)
+	if j.err != nil {
+		details = details + fmt.Sprintf("Error: %v\n", j.err)
+	}
+	return "Job_Details", []byte(details)
+}
+
+// updateProgressf updates progress of the job running with the given context
+// or message of the progress dialog if action is not run as a job.
+func updateProgressf(ctx context.Context, format string, v ...interface{}) {
+	if j, ok := ctx.Value(jobContextKey{}).(*job); ok {
+		j.setProgress(fmt.Sprintf(format, v...))
+		return
+	}
+	updateProgressDialogMessagef(format, v...)
+}
+
+// showJobs shows list of jobs - each job can be canceled, run again or
+// deleted and its result can be shown.
+func showJobs() error {
+	logging.LogDebug("ui/showJobs()")
+	for {
+		jobs := jobList()
+		if len(jobs) == 0 {
+			return errs.Error("No jobs, copy of directories, export & secure backup are run as jobs.")
+		}
+
+		jobDisplayList := make([]string, len(jobs))
+		for idx, j := range jobs {
+			jobDisplayList[idx] = j.String()
+		}
+		dialogSession := listSelectionDialogSessionInfo{
+			message: "Jobs (Enter - manage job, Esc - close):",
+			list:    jobDisplayList}
+		runListSelectionDialog(&dialogSession)
+		if !dialogSession.dialogSubmitted {
+			return nil
+		}
+
+		err := manageJob(jobs[dialogSession.selectionIdx])
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// manageJob asks user which action to take on the job.
+func manageJob(j *job) error {
+	logging.LogDebugf("ui/manageJob(%d, '%s')", j.id, j.name)
+	dialogResult := askUserInput(
+		fmt.Sprintf("Job %d '%s' - (c)ancel, (r)e-run, (v)iew result, (d)elete: ", j.id, j.name),
+		"", []string{"c", "r", "v", "d"}, false)
+	if !dialogResult.dialogSubmitted {
+		return nil
+	}
+
+	running := j.currentState() == jobRunning
+	switch dialogResult.inputAnswer {
+	case "c":
+		if !running {
+			return errs.Errorf("Job %d '%s' is not running.", j.id, j.name)
+		}
+		j.stop()
+		updateStatusf("Canceling job %d '%s'...", j.id, j.name)
+	case "r":
+		if running {
+			return errs.Errorf("Job %d '%s' is already running.", j.id, j.name)
+		}
+		j.start()
+	case "v":
+		name, content := j.details()
+		return extprogs.View(name, content)
+	case "d":
+		if err := deleteJob(j); err != nil {
+			return err
+		}
+		updateStatusf("Job %d '%s' deleted.", j.id, j.name)
+	}
+
+	return nil
+}
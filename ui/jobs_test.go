@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/croz-ltd/dpcmder/utils/assert"
+	"github.com/croz-ltd/dpcmder/utils/errs"
+)
+
+// waitJobFinished waits until the job is not running any more.
+func waitJobFinished(t *testing.T, j *job) jobState {
+	timeout := time.After(5 * time.Second)
+	for {
+		if state := j.currentState(); state != jobRunning {
+			return state
+		}
+		select {
+		case <-timeout:
+			t.Fatalf("Job %d '%s' still running.", j.id, j.name)
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestJobStates(t *testing.T) {
+	testDataMatrix := []struct {
+		name   string
+		result []byte
+		err    error
+		state  jobState
+	}{
+		{"done", []byte("result"), nil, jobDone},
+		{"failed", nil, errs.Error("Export failed."), jobFailed},
+		{"canceled", nil, context.Canceled, jobCanceled},
+	}
+	for _, testCase := range testDataMatrix {
+		t.Run(testCase.name, func(t *testing.T) {
+			release := make(chan struct{})
+			j := startJob("Test "+testCase.name, "result.txt", func(ctx context.Context) ([]byte, error) {
+				<-release
+				return testCase.result, testCase.err
+			})
+			defer deleteJob(j)
+			assert.Equals(t, "currentState()", j.currentState(), jobRunning)
+			assert.True(t, "jobList()", containsJob(jobList(), j))
+			close(release)
+			assert.Equals(t, "currentState()", waitJobFinished(t, j), testCase.state)
+			assert.Equals(t, "err", j.err, testCase.err)
+			assert.DeepEqual(t, "result", j.result, testCase.result)
+			assert.False(t, "finished", j.finished.IsZero())
+		})
+	}
+}
+
+func TestJobStop(t *testing.T) {
+	j := startJob("Test stop", "", func(ctx context.Context) ([]byte, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	defer deleteJob(j)
+	j.stop()
+	assert.Equals(t, "currentState()", waitJobFinished(t, j), jobCanceled)
+	assert.True(t, "err", errors.Is(j.err, context.Canceled))
+}
+
+func TestJobRerun(t *testing.T) {
+	runCount := 0
+	j := startJob("Test re-run", "result.txt", func(ctx context.Context) ([]byte, error) {
+		runCount++
+		if runCount == 1 {
+			updateProgressf(ctx, "Failing run %d", runCount)
+			return []byte("partial"), errs.Error("First run failed.")
+		}
+		return nil, nil
+	})
+	defer deleteJob(j)
+	assert.Equals(t, "currentState()", waitJobFinished(t, j), jobFailed)
+	assert.Equals(t, "progress", j.progress, "Failing run 1")
+
+	j.start()
+	assert.Equals(t, "currentState()", waitJobFinished(t, j), jobDone)
+	assert.Equals(t, "runCount", runCount, 2)
+	assert.Nil(t, "err", j.err)
+	assert.True(t, "result", j.result == nil)
+	assert.Equals(t, "progress", j.progress, "")
+	name, _ := j.details()
+	assert.Equals(t, "details()", name, "Job_Details")
+}
+
+func TestDeleteJob(t *testing.T) {
+	release := make(chan struct{})
+	j := startJob("Test delete", "", func(ctx context.Context) ([]byte, error) {
+		<-release
+		return nil, nil
+	})
+	assert.NotNil(t, "deleteJob() running", deleteJob(j))
+	assert.True(t, "jobList()", containsJob(jobList(), j))
+
+	close(release)
+	waitJobFinished(t, j)
+	assert.Nil(t, "deleteJob()", deleteJob(j))
+	assert.False(t, "jobList()", containsJob(jobList(), j))
+}
+
+func TestUpdateProgressf(t *testing.T) {
+	progressDialogSession.mutex.Lock()
+	progressDialogSession.msg = "Dialog message"
+	progressDialogSession.mutex.Unlock()
+
+	j := &job{id: 1, name: "Test progress"}
+	ctx := context.WithValue(context.Background(), jobContextKey{}, j)
+	updateProgressf(ctx, "Copied %d files", 3)
+	assert.Equals(t, "job progress", j.progress, "Copied 3 files")
+	assert.Equals(t, "progress dialog", progressDialogSession.msg, "Dialog message")
+
+	updateProgressf(context.Background(), "Copied %d files", 5)
+	assert.Equals(t, "progress dialog", progressDialogSession.msg, "Copied 5 files")
+	assert.Equals(t, "job progress", j.progress, "Copied 3 files")
+}
+
+func containsJob(jobs []*job, j *job) bool {
+	for _, listJob := range jobs {
+		if listJob == j {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"unicode/utf8"
 
 	"github.com/croz-ltd/dpcmder/events"
@@ -38,9 +39,15 @@ var Screen tcell.Screen
 // don't draw on the screen (async sync events can cause draw events)
 var screenActive = false
 
+// screenMutex guards screen state, screen is drawn from the goroutine reading
+// user input and from the progress dialog goroutine.
+var screenMutex sync.Mutex
+
 // Init initializes console screen.
 func Init() {
 	logging.LogDebug("ui/out/Init()")
+	screenMutex.Lock()
+	defer screenMutex.Unlock()
 
 	var err error
 	Screen, err = tcell.NewScreen()
@@ -59,6 +66,8 @@ func Init() {
 // Stop terminates console screen.
 func Stop() {
 	logging.LogDebug("ui/out/Stop()")
+	screenMutex.Lock()
+	defer screenMutex.Unlock()
 	screenActive = false
 	Screen.Fini()
 	logging.LogDebug("ui/out/Stop() end")
@@ -66,6 +75,8 @@ func Stop() {
 
 // GetScreenSize returns size of console screen.
 func GetScreenSize() (width, height int) {
+	screenMutex.Lock()
+	defer screenMutex.Unlock()
 	logging.LogDebugf("ui/out/GetScreenSize(), screenActive: %v", screenActive)
 	if !screenActive {
 		return
@@ -79,6 +90,8 @@ func GetScreenSize() (width, height int) {
 // DrawEvent crates appropriate changes to screen for given event. Usually either
 // refresh whole screen or just update status message.
 func DrawEvent(updateViewEvent events.UpdateViewEvent) {
+	screenMutex.Lock()
+	defer screenMutex.Unlock()
 	logging.LogDebugf("ui/out/DrawEvent(%v), screenActive: %v", updateViewEvent, screenActive)
 
 	if !screenActive {
@@ -103,6 +116,17 @@ func DrawEvent(updateViewEvent events.UpdateViewEvent) {
 	logging.LogDebug("ui/out/drawEvent() finished")
 }
 
+// PostInterrupt wakes up the goroutine reading user input so it can apply view
+// updates posted by background goroutines (when external program is active
+// updates are applied after the next input event).
+func PostInterrupt() {
+	screenMutex.Lock()
+	defer screenMutex.Unlock()
+	if screenActive {
+		Screen.PostEvent(tcell.NewEventInterrupt(nil))
+	}
+}
+
 // refreshScreen refreshes the whole terminal screen.
 func refreshScreen(m model.Model) {
 	logging.LogDebugf("ui/out/refreshScreen('%v')", m)
@@ -301,14 +325,20 @@ func showStatus(m *model.Model, status string) {
 	if m.CurrentFilter() != "" {
 		filterMsg = fmt.Sprintf("Filter: '%s' | ", m.CurrentFilter())
 	}
-	if m.SyncModeOn {
+	if m.SyncModeOn() {
 		var syncStatusSymbol string
 		if syncStatusBlink {
 			syncStatusSymbol = "*"
 		} else {
 			syncStatusSymbol = " "
 		}
-		syncMsg = fmt.Sprintf("%s Sync (%s/'%s' <- '%s') | ", syncStatusSymbol, m.SyncDpDomain, m.SyncDirDp, m.SyncDirLocal)
+		runningSessions := make([]string, 0)
+		for _, session := range m.SyncSessions() {
+			if session.IsOn() {
+				runningSessions = append(runningSessions, session.Name)
+			}
+		}
+		syncMsg = fmt.Sprintf("%s Sync (%s) | ", syncStatusSymbol, strings.Join(runningSessions, ", "))
 	}
 
 	statusMsg := fmt.Sprintf("%s%s%s", syncMsg, filterMsg, status)
@@ -344,6 +374,7 @@ func showProgressDialog(msg string, progress int) {
 	writeLine(x, y+2, buildLine("", "*", "", dialogWidth), 0, stNormal)
 	writeLine(progressX, progressY, "****", 0, stNormal)
 	writeLine(x+4, y, msg, 0, stNormal)
+	writeLine(x+dialogWidth-18, y+2, " Esc - cancel ", 0, stNormal)
 
 	Screen.Show()
 }
@@ -0,0 +1,1014 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/croz-ltd/dpcmder/config"
+	"github.com/croz-ltd/dpcmder/events"
+	"github.com/croz-ltd/dpcmder/extprogs"
+	"github.com/croz-ltd/dpcmder/model"
+	"github.com/croz-ltd/dpcmder/repo/dp"
+	"github.com/croz-ltd/dpcmder/repo/localfs"
+	"github.com/croz-ltd/dpcmder/utils/errs"
+	"github.com/croz-ltd/dpcmder/utils/logging"
+	"github.com/croz-ltd/dpcmder/utils/paths"
+)
+
+// postSyncCommandTimeout is the longest time post sync command can run (sync
+// is not done while the command runs).
+const postSyncCommandTimeout = 5 * time.Minute
+
+// loadSyncSessions adds sync sessions saved in the configuration to the model
+// (sessions are not started).
+func loadSyncSessions(m *model.Model) {
+	for name, sessionConfig := range config.Conf.SyncSessions {
+		m.AddSyncSession(&model.SyncSession{Name: name,
+			DpAppliance: sessionConfig.DpAppliance, DpDomain: sessionConfig.DpDomain,
+			DirDp: sessionConfig.DirDp, DirLocal: sessionConfig.DirLocal})
+	}
+}
+
+// addSyncSession adds new sync session for the DataPower directory shown in
+// one panel and the local directory shown in the other panel and starts it.
+func addSyncSession(m *model.Model) error {
+	logging.LogDebug("ui/addSyncSession()")
+	dpSide := m.CurrSide()
+	if !isDpSide(dpSide) {
+		dpSide = m.OtherSide()
+	}
+	if !isDpSide(dpSide) || isDpSide(oppositeSide(dpSide)) {
+		return errs.Error("Can't sync if DataPower is not shown in one panel and local filesystem in the other panel.")
+	}
+
+	dpViewConfig := m.ViewConfig(dpSide)
+	if dpViewConfig.DpDomain == "" || dpViewConfig.Path == "" {
+		return errs.Errorf("Can't sync if DataPower domain (%s) or path (%s) are not selected.",
+			dpViewConfig.DpDomain, dpViewConfig.Path)
+	}
+	session := model.SyncSession{
+		DpAppliance: dpViewConfig.DpAppliance,
+		DpDomain:    dpViewConfig.DpDomain,
+		DirDp:       dpViewConfig.Path,
+		DirLocal:    m.ViewConfig(oppositeSide(dpSide)).Path}
+
+	dialogResult := askUserInput(
+		fmt.Sprintf("Enter sync session name to sync %s:'%s' <- '%s': ",
+			session.DpDomain, session.DirDp, session.DirLocal),
+		paths.GetFileName(session.DirLocal), nil, false)
+	if !dialogResult.dialogSubmitted || dialogResult.inputAnswer == "" {
+		updateStatus("Adding sync session canceled.")
+		return nil
+	}
+	session.Name = dialogResult.inputAnswer
+	// Existing session (its conflicts, configuration & sync goroutine) is not
+	// replaced, it has to be deleted first.
+	if _, saved := config.Conf.SyncSessions[session.Name]; saved || m.SyncSession(session.Name) != nil {
+		return errs.Errorf("Sync session '%s' already exists, use another name or delete it first ('C').", session.Name)
+	}
+
+	m.AddSyncSession(&session)
+	config.Conf.SetSyncSession(session.Name, config.SyncSession{DpAppliance: session.DpAppliance,
+		DpDomain: session.DpDomain, DirDp: session.DirDp, DirLocal: session.DirLocal})
+
+	return startSyncSession(m, &session)
+}
+
+// syncApplianceConfig returns configuration of the DataPower appliance synced
+// in the session with password set if it is not saved.
+func syncApplianceConfig(s *model.SyncSession) config.DataPowerAppliance {
+	dpa := config.Conf.DataPowerAppliances[s.DpAppliance]
+	if dpa.Password == "" {
+		dpa.SetDpPlaintextPassword(config.DpTransientPassword(s.DpAppliance))
+	}
+	return dpa
+}
+
+// startSyncSession starts syncing in the new goroutine.
+func startSyncSession(m *model.Model, s *model.SyncSession) error {
+	logging.LogDebugf("ui/startSyncSession(%v)", s)
+	err := waitSyncStopped(s)
+	if err != nil {
+		return err
+	}
+	err = dp.SyncRepo(s.Name).InitNetworkSettings(s.DpAppliance, syncApplianceConfig(s))
+	if err != nil {
+		return err
+	}
+	// Post sync actions are configured in the configuration file only.
+	s.PostSyncActions = config.Conf.SyncSessions[s.Name].PostSyncActions
+	stop, done := s.Start()
+	go syncLocalToDp(s, stop, done)
+	syncStatusf(s, "Synchronization started (%s:'%s' <- '%s').", s.DpDomain, s.DirDp, s.DirLocal)
+
+	return nil
+}
+
+// RunSync runs sync session without terminal user interface until the stop
+// channel is closed or the session is stopped because of two-way sync
+// conflicts. Sync status messages are written to the output.
+func RunSync(s *model.SyncSession, dpa config.DataPowerAppliance, output io.Writer, stop <-chan struct{}) error {
+	logging.LogDebugf("ui/RunSync(%v)", s)
+	s.Output = output
+	err := dp.SyncRepo(s.Name).InitNetworkSettings(s.DpAppliance, dpa)
+	if err != nil {
+		return err
+	}
+	runStop, runDone := s.Start()
+	go syncLocalToDp(s, runStop, runDone)
+	syncStatusf(s, "Synchronization started (%s:'%s' <- '%s').", s.DpDomain, s.DirDp, s.DirLocal)
+
+	select {
+	case <-stop:
+		stopSyncSession(s)
+		// Wait for the sync in progress to finish.
+		<-s.Done()
+	case <-s.Done():
+	}
+	if conflicts := s.Conflicts(); len(conflicts) != 0 {
+		for _, conflict := range conflicts {
+			syncStatusf(s, "Conflict: %s", conflict)
+		}
+		return errs.Errorf("Sync session '%s' stopped, %d conflict(s) found.", s.Name, len(conflicts))
+	}
+
+	return nil
+}
+
+// stopSyncSession stops syncing (goroutine stops after current sync is done).
+func stopSyncSession(s *model.SyncSession) {
+	logging.LogDebugf("ui/stopSyncSession(%v)", s)
+	s.Stop()
+	syncStatusf(s, "Synchronization stopped.")
+}
+
+// waitSyncStopped waits for the goroutine of the stopped sync session to
+// finish the sync in progress (progress dialog is shown while waiting).
+func waitSyncStopped(s *model.SyncSession) error {
+	done := s.Done()
+	select {
+	case <-done:
+		return nil
+	default:
+	}
+
+	ctx := showProgressDialogf("Waiting for sync session '%s' to stop...", s.Name)
+	defer hideProgressDialog()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// syncStopped returns true if the stop channel of the sync session run is
+// closed.
+func syncStopped(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// showSyncSessions shows list of sync sessions - each session can be started,
+// stopped or deleted and its last results and conflicts can be shown.
+func showSyncSessions(m *model.Model) error {
+	logging.LogDebug("ui/showSyncSessions()")
+	for {
+		sessions := m.SyncSessions()
+		if len(sessions) == 0 {
+			return errs.Error("No sync sessions, press 's' to add sync session for directories shown in panels.")
+		}
+
+		sessionList := make([]string, len(sessions))
+		for idx, s := range sessions {
+			lastSync := "-"
+			if lastSyncTime := s.LastSyncTime(); !lastSyncTime.IsZero() {
+				lastSync = lastSyncTime.Format("15:04:05")
+			}
+			sessionList[idx] = fmt.Sprintf("%-20s %-9s %-8s %s:%s:'%s' <- '%s'",
+				s.Name, s.State(), lastSync, s.DpAppliance, s.DpDomain, s.DirDp, s.DirLocal)
+		}
+		dialogSession := listSelectionDialogSessionInfo{
+			message: "Sync sessions (Enter - manage session, Esc - close):",
+			list:    sessionList}
+		runListSelectionDialog(&dialogSession)
+		if !dialogSession.dialogSubmitted {
+			return nil
+		}
+
+		err := manageSyncSession(m, sessions[dialogSession.selectionIdx])
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// manageSyncSession asks user which action to take on the sync session.
+func manageSyncSession(m *model.Model, s *model.SyncSession) error {
+	logging.LogDebugf("ui/manageSyncSession(%v)", s)
+	startStop := "(s)tart"
+	if s.IsOn() {
+		startStop = "(s)top"
+	}
+	dialogResult := askUserInput(
+		fmt.Sprintf("Sync session '%s' - %s, show (r)esults, resolve (c)onflicts, (d)elete: ", s.Name, startStop),
+		"", []string{"s", "r", "c", "d"}, false)
+	if !dialogResult.dialogSubmitted {
+		return nil
+	}
+
+	switch dialogResult.inputAnswer {
+	case "s":
+		if s.IsOn() {
+			stopSyncSession(s)
+			return nil
+		}
+		return startSyncSession(m, s)
+	case "r":
+		resultsText := fmt.Sprintf("Sync session %s\n\n", s)
+		for _, result := range s.Results() {
+			resultsText = resultsText + result + "\n"
+		}
+		return extprogs.View("Sync_Results", []byte(resultsText))
+	case "c":
+		return resolveSyncConflicts(s)
+	case "d":
+		if s.IsOn() {
+			return errs.Errorf("Can't delete running sync session '%s'.", s.Name)
+		}
+		if err := waitSyncStopped(s); err != nil {
+			return err
+		}
+		m.DeleteSyncSession(s.Name)
+		config.Conf.DeleteSyncSession(s.Name)
+		dp.DeleteSyncRepo(s.Name)
+		updateStatusf("Sync session '%s' deleted.", s.Name)
+	}
+
+	return nil
+}
+
+// syncStatusf shows status message and saves it to the sync session results.
+func syncStatusf(s *model.SyncSession, format string, v ...interface{}) {
+	status := fmt.Sprintf(format, v...)
+	s.AddResult(time.Now().Format("15:04:05 ") + status)
+	if s.Output != nil {
+		fmt.Fprintf(s.Output, "%s %s\n", time.Now().Format("2006-01-02 15:04:05"), status)
+		return
+	}
+	postStatusf("Sync '%s': %s", s.Name, status)
+}
+
+// refreshSyncViews refreshes panels showing directories synced in the session.
+func refreshSyncViews(m *model.Model, s *model.SyncSession) {
+	for _, side := range []model.Side{model.Left, model.Right} {
+		viewConfig := m.ViewConfig(side)
+		switch {
+		case viewConfig == nil:
+		case isDpSide(side) && viewConfig.DpAppliance == s.DpAppliance && viewConfig.DpDomain == s.DpDomain,
+			!isDpSide(side) && strings.HasPrefix(viewConfig.Path, s.DirLocal):
+			refreshView(m, side)
+		}
+	}
+}
+
+// syncLocalToDp syncs the session directories until the stop channel is
+// closed and closes done channel when it ends. State of the run (initial sync
+// and DataPower paths uploaded since post sync actions were run) is used only
+// by this goroutine.
+func syncLocalToDp(s *model.SyncSession, stop <-chan struct{}, done chan<- struct{}) {
+	logging.LogDebugf("ui/syncLocalToDp(%v)", s)
+	defer close(done)
+	// 1. Fetch dp & local file tree
+	// 2. Initial sync files from local to dp:
+	// 2a. Copy non-existing from local to dp
+	// 2b. Compare existing files and copy different files
+	// 3. Save local file tree (file path + modify timestamp)
+	// 4. Sync files from local to dp (checking only dirs with changes reported
+	//    by filesystem notifications or whole local tree periodically):
+	// 4a. When local modify timestamp changes or new file appears copy to dp
+	// 4b. When local file or dir is removed delete it from dp (if enabled)
+	// Content hashes of files synced are saved to manifest so files with
+	// unchanged content are not copied (or compared) again.
+	var treeOld localfs.Tree
+	syncCheckTime := time.Duration(config.Conf.Sync.Seconds) * time.Second
+	manifest := loadSyncManifest(s)
+	watcher := startSyncWatcher(s)
+	initial := true
+	var uploaded []string
+	for !syncStopped(stop) {
+		var changesMade bool
+		var conflicts []model.SyncConflict
+		ignoreRules, err := localfs.LoadIgnoreRules(s.DirLocal, config.Conf.Sync.IgnorePatterns)
+		if err != nil {
+			syncStatusf(s, "Sync err: %s.", err)
+		}
+
+		if config.Conf.Sync.TwoWay {
+			changesMade, conflicts = syncTwoWay(s, manifest, ignoreRules, &uploaded)
+			initial = false
+		} else if initial {
+			tree, err := localfs.LoadTree("", s.DirLocal, ignoreRules)
+			if err != nil {
+				syncStatusf(s, "Sync err: %s.", err)
+			}
+			logging.LogDebug("ui/syncLocalToDp(), tree: ", tree)
+			changesMade = syncLocalToDpInitial(s, &tree, ignoreRules, manifest, &uploaded)
+			logging.LogDebug("ui/syncLocalToDp(), after initial sync - changesMade: ", changesMade)
+			initial = false
+			treeOld = tree
+		} else {
+			changedDirs := []string{""}
+			if watcher != nil {
+				var ok bool
+				select {
+				case changedDirs, ok = <-watcher.Changes:
+					if !ok {
+						watcher.Close()
+						watcher = nil
+						changedDirs = []string{""}
+						syncStatusf(s, "Sync filesystem notifications stopped, checking for changes every %d seconds.",
+							config.Conf.Sync.Seconds)
+					}
+				case <-stop:
+					continue
+				}
+			}
+			changesMade = syncLocalToDpChanges(s, &treeOld, changedDirs, ignoreRules, manifest, &uploaded)
+			logging.LogDebug("ui/syncLocalToDp(), after later sync - changesMade: ", changesMade)
+		}
+
+		logging.LogDebugf("ui/syncLocalToDp() changesMade: %v.", changesMade)
+		s.SetLastSyncTime(time.Now())
+		if err := manifest.Save(); err != nil {
+			syncStatusf(s, "Sync err: can't save manifest: %s.", err)
+		}
+		if len(uploaded) != 0 {
+			runPostSyncActions(s, uploaded)
+			uploaded = nil
+		}
+		// Session is stopped after the manifest is saved and post sync actions
+		// are run so conflicts resolved are not overwritten by the manifest of
+		// the sync goroutine.
+		if len(conflicts) != 0 {
+			s.StopWithConflicts(conflicts)
+			syncStatusf(s, "Synchronization stopped, %d conflict(s) found - press 'C' to resolve conflicts.",
+				len(conflicts))
+		}
+		switch {
+		case s.Output != nil:
+		case changesMade:
+			postViewUpdate(events.UpdateViewEvent{Type: events.UpdateViewRefreshSyncPanels, SyncSession: s})
+		default:
+			postViewUpdate(events.UpdateViewEvent{Type: events.UpdateViewRefresh})
+		}
+		if watcher == nil {
+			select {
+			case <-stop:
+			case <-time.After(syncCheckTime):
+			}
+		}
+	}
+	if watcher != nil {
+		watcher.Close()
+	}
+	logging.LogDebugf("ui/syncLocalToDp(%v) ending.", s)
+}
+
+// runPostSyncActions runs actions configured for the sync session after the
+// batch of files is uploaded to the DataPower.
+func runPostSyncActions(s *model.SyncSession, uploaded []string) {
+	logging.LogDebugf("ui/runPostSyncActions(%v), uploaded: %v", s, uploaded)
+	syncRepo := dp.SyncRepo(s.Name)
+	for _, action := range s.PostSyncActions {
+		actionFiles := action.MatchingFiles(uploaded)
+		if len(actionFiles) == 0 {
+			continue
+		}
+
+		var err error
+		switch action.Type {
+		case config.PostSyncFlushStylesheetCache, config.PostSyncFlushDocumentCache:
+			statusClass := "StylesheetCachingSummary"
+			if action.Type == config.PostSyncFlushDocumentCache {
+				statusClass = "DocumentCachingSummary"
+			}
+			if action.XMLManager == "" {
+				_, err = syncRepo.FlushCache(context.Background(), s.DpDomain, statusClass, "", model.ItemDpStatusClass)
+			} else {
+				_, err = syncRepo.FlushCache(context.Background(), s.DpDomain, statusClass, action.XMLManager, model.ItemDpStatus)
+			}
+		case config.PostSyncExecConfig:
+			err = syncRepo.ExecConfig(context.Background(), &model.ItemConfig{DpDomain: s.DpDomain, Path: action.Path})
+		case config.PostSyncCommand:
+			var output []byte
+			ctx, cancel := context.WithTimeout(context.Background(), postSyncCommandTimeout)
+			output, err = extprogs.Run(ctx, s.DirLocal, action.Command, actionFiles...)
+			cancel()
+			if len(output) != 0 {
+				s.AddResult(strings.TrimSpace(string(output)))
+			}
+		default:
+			err = errs.Errorf("Unknown post sync action type '%s'", action.Type)
+		}
+
+		if err != nil {
+			syncStatusf(s, "Post sync action %s err: %s.", action.Type, err)
+		} else {
+			syncStatusf(s, "Post sync action %s done.", action.Type)
+		}
+	}
+}
+
+// loadSyncManifest loads manifest of files synced between the local and
+// DataPower directories in the previous sync sessions.
+func loadSyncManifest(s *model.SyncSession) *localfs.Manifest {
+	syncID := fmt.Sprintf("%s:%s:%s <- %s", s.DpAppliance, s.DpDomain, s.DirDp, s.DirLocal)
+	manifestPath := ""
+	syncDirPath, err := config.SyncDirPath()
+	if err == nil {
+		manifestPath = localfs.Repo.GetFilePath(syncDirPath, localfs.ManifestFileName(syncID))
+	}
+	manifest, err := localfs.LoadManifest(manifestPath, syncID)
+	if err != nil {
+		logging.LogDebug("ui/loadSyncManifest(), err: ", err)
+		syncStatusf(s, "Sync err: %s.", err)
+	}
+
+	return manifest
+}
+
+// startSyncWatcher starts watching synced local dir using filesystem
+// notifications, returns nil if polling should be used instead.
+func startSyncWatcher(s *model.SyncSession) *localfs.Watcher {
+	// Changes on DataPower are checked periodically in two-way sync.
+	if config.Conf.Sync.Polling || config.Conf.Sync.TwoWay {
+		return nil
+	}
+	ignoreRules, _ := localfs.LoadIgnoreRules(s.DirLocal, config.Conf.Sync.IgnorePatterns)
+	debounce := time.Duration(config.Conf.Sync.DebounceMillis) * time.Millisecond
+	watcher, err := localfs.NewWatcher(s.DirLocal, debounce, ignoreRules)
+	if err != nil {
+		logging.LogDebug("ui/startSyncWatcher(), err: ", err)
+		syncStatusf(s, "Sync can't use filesystem notifications (%s), checking for changes every %d seconds.",
+			err, config.Conf.Sync.Seconds)
+		return nil
+	}
+
+	return watcher
+}
+
+// syncLocalToDpChanges reloads changed local dirs (paths relative to the
+// synced dir) and syncs changes found comparing to the saved local tree.
+func syncLocalToDpChanges(s *model.SyncSession, tree *localfs.Tree, changedDirs []string,
+	ignoreRules *localfs.IgnoreRules, manifest *localfs.Manifest, uploaded *[]string) bool {
+	logging.LogDebugf("ui/syncLocalToDpChanges(%v)", changedDirs)
+	changesMade := false
+	for _, changedDir := range changedDirs {
+		// New dirs are synced when their parent dir is synced.
+		treeOld := tree.FindPath(changedDir)
+		if treeOld == nil {
+			continue
+		}
+		localPath := localfs.Repo.GetFilePath(s.DirLocal, changedDir)
+		fileType, err := localfs.Repo.GetFileType(context.Background(), nil, localPath, ".")
+		if err != nil || fileType != model.ItemDirectory {
+			// Removed dirs are synced when their parent dir is synced.
+			continue
+		}
+
+		changedTree, err := localfs.LoadTree(changedDir, localPath, ignoreRules)
+		if err != nil {
+			syncStatusf(s, "Sync err: %s.", err)
+		}
+		// Don't delete anything from dp if local tree is not loaded completely.
+		deleteRemoved := config.Conf.Sync.DeleteRemoved && err == nil
+		if syncLocalToDpLater(s, &changedTree, treeOld, ignoreRules, manifest, uploaded, deleteRemoved) {
+			changesMade = true
+		}
+		*treeOld = changedTree
+	}
+
+	return changesMade
+}
+
+func syncLocalToDpInitial(s *model.SyncSession, tree *localfs.Tree,
+	ignoreRules *localfs.IgnoreRules, manifest *localfs.Manifest, uploaded *[]string) bool {
+	changesMade := false
+	logging.LogDebugf("ui/syncLocalToDpInitial(%v)", tree)
+	if ignoreRules.Ignored(tree.PathFromRoot, tree.Dir) {
+		return false
+	}
+
+	if tree.Dir {
+		syncRepo := dp.SyncRepo(s.Name)
+		dpPath := syncRepo.GetFilePath(s.DirDp, tree.PathFromRoot)
+		fileType, err := syncRepo.GetFileTypeByPath(context.Background(), s.DpDomain, dpPath, ".")
+		if err != nil {
+			logging.LogDebug("ui/syncLocalToDpInitial(), err: ", err)
+		}
+
+		if fileType == model.ItemNone {
+			syncRepo.CreateDirByPath(context.Background(), s.DpDomain, dpPath, ".")
+			changesMade = true
+		} else if fileType == model.ItemFile {
+			logging.LogDebugf("ui/syncLocalToDpInitial() - In place of dir there is a file on dp: '%s'", dpPath)
+		}
+		for _, child := range tree.Children {
+			if syncLocalToDpInitial(s, &child, ignoreRules, manifest, uploaded) {
+				changesMade = true
+			}
+		}
+	} else {
+		changesMade = updateDpFile(s, tree, manifest, uploaded)
+	}
+
+	logging.LogDebugf("ui/syncLocalToDpInitial(), changesMade: %v", changesMade)
+	return changesMade
+}
+
+func syncLocalToDpLater(s *model.SyncSession, tree, treeOld *localfs.Tree, ignoreRules *localfs.IgnoreRules,
+	manifest *localfs.Manifest, uploaded *[]string, deleteRemoved bool) bool {
+	changesMade := false
+	logging.LogDebugf("ui/syncLocalToDpLater(%v, %v, %t)", tree, treeOld, deleteRemoved)
+	if ignoreRules.Ignored(tree.PathFromRoot, tree.Dir) {
+		return false
+	}
+
+	if tree.Dir {
+		if treeOld == nil {
+			syncRepo := dp.SyncRepo(s.Name)
+			dpPath := syncRepo.GetFilePath(s.DirDp, tree.PathFromRoot)
+			fileType, err := syncRepo.GetFileTypeByPath(context.Background(), s.DpDomain, dpPath, ".")
+			if err != nil {
+				logging.LogDebug("ui/syncLocalToDpLater(), err: ", err)
+				return false
+			}
+			if fileType == model.ItemNone {
+				syncRepo.CreateDirByPath(context.Background(), s.DpDomain, dpPath, ".")
+				changesMade = true
+			} else if fileType == model.ItemFile {
+				logging.LogDebugf("ui/syncLocalToDpLater() - In place of dir there is a file on dp: '%s'", dpPath)
+				return false
+			}
+		}
+
+		if deleteRemoved {
+			for _, removedChild := range tree.RemovedChildren(treeOld) {
+				// Files which became ignored are not removed locally.
+				if ignoreRules.Ignored(removedChild.PathFromRoot, removedChild.Dir) {
+					continue
+				}
+				if deleteDpFile(s, &removedChild, manifest) {
+					changesMade = true
+				}
+			}
+		}
+
+		for _, child := range tree.Children {
+			var childOld *localfs.Tree
+			if treeOld != nil {
+				childOld = treeOld.FindChild(&child)
+			}
+			if syncLocalToDpLater(s, &child, childOld, ignoreRules, manifest, uploaded, deleteRemoved) {
+				changesMade = true
+			}
+		}
+	} else {
+		if tree.FileChanged(treeOld) {
+			changesMade = updateDpFile(s, tree, manifest, uploaded)
+		}
+	}
+
+	logging.LogDebugf("ui/syncLocalToDpLater()(), changesMade: %v", changesMade)
+	return changesMade
+}
+
+func updateDpFile(s *model.SyncSession, tree *localfs.Tree, manifest *localfs.Manifest, uploaded *[]string) bool {
+	changesMade := false
+	hash, unchanged, err := manifest.ContentUnchanged(tree)
+	if err != nil {
+		logging.LogDebug("ui/updateDpFile(), couldn't hash local file - err: ", err)
+		return false
+	}
+	if unchanged {
+		logging.LogDebugf("ui/updateDpFile(), file '%s' not changed since last sync.", tree.PathFromRoot)
+		return false
+	}
+
+	localBytes, err := localfs.GetFileByPath(tree.Path)
+	if err != nil {
+		logging.LogDebug("ui/updateDpFile(), couldn't get local file - err: ", err)
+		return false
+	}
+	syncRepo := dp.SyncRepo(s.Name)
+	dpPath := syncRepo.GetFilePath(s.DirDp, tree.PathFromRoot)
+	dpBytes, err := syncRepo.GetFileByPath(context.Background(), s.DpDomain, dpPath)
+
+	if err != nil {
+		if respErr, ok := err.(errs.UnexpectedHTTPResponse); !ok || respErr.StatusCode != 404 {
+			logging.LogDebug("ui/updateDpFile(), couldn't get dp file - err: ", err)
+			return false
+		}
+	}
+
+	if bytes.Compare(localBytes, dpBytes) != 0 {
+		changesMade = true
+		res, err := syncRepo.UpdateFileByPath(context.Background(), s.DpDomain, dpPath, localBytes)
+		if err != nil {
+			logging.LogDebug("ui/updateDpFile(), couldn't update dp file - err: ", err)
+		}
+		logging.LogDebugf("ui/updateDpFile(), file '%s' updated: %T", dpPath, res)
+		if res {
+			manifest.Set(tree, hash)
+			*uploaded = append(*uploaded, dpPath)
+			syncStatusf(s, "Dp file '%s' updated.", dpPath)
+		} else {
+			syncStatusf(s, "Error updating file '%s'.", dpPath)
+		}
+	} else {
+		manifest.Set(tree, hash)
+	}
+
+	return changesMade
+}
+
+// deleteDpFile deletes file or dir removed from the local filesystem from
+// the synced DataPower dir.
+func deleteDpFile(s *model.SyncSession, tree *localfs.Tree, manifest *localfs.Manifest) bool {
+	syncRepo := dp.SyncRepo(s.Name)
+	dpPath := syncRepo.GetFilePath(s.DirDp, tree.PathFromRoot)
+	dpParentPath := syncRepo.GetFilePath(dpPath, "..")
+	itemType := model.ItemFile
+	if tree.Dir {
+		itemType = model.ItemDirectory
+	}
+
+	dpParentConfig := model.ItemConfig{Type: model.ItemDirectory,
+		DpDomain: s.DpDomain, Path: dpParentPath}
+	res, err := syncRepo.Delete(context.Background(), &dpParentConfig, itemType, dpParentPath, tree.Name)
+	if err != nil {
+		logging.LogDebug("ui/deleteDpFile(), couldn't delete dp file - err: ", err)
+	}
+	logging.LogDebugf("ui/deleteDpFile(), file '%s' deleted: %t", dpPath, res)
+	if res {
+		manifest.Remove(tree.PathFromRoot)
+		syncStatusf(s, "Dp %s '%s' deleted.", itemType.UserFriendlyString(), dpPath)
+	} else {
+		syncStatusf(s, "Error deleting %s '%s'.", itemType.UserFriendlyString(), dpPath)
+	}
+
+	return res
+}
+
+// syncTwoWay copies files changed in the local directory since the last sync
+// to the DataPower and files changed in the DataPower directory to the local
+// directory. Returns conflicts - files which can't be synced automatically.
+func syncTwoWay(s *model.SyncSession, manifest *localfs.Manifest,
+	ignoreRules *localfs.IgnoreRules, uploaded *[]string) (bool, []model.SyncConflict) {
+	logging.LogDebugf("ui/syncTwoWay(%v)", s)
+	tree, err := localfs.LoadTree("", s.DirLocal, ignoreRules)
+	if err != nil {
+		syncStatusf(s, "Sync err: %s.", err)
+		return false, nil
+	}
+	dpFiles, err := dp.SyncRepo(s.Name).ListFilesRecursive(context.Background(), s.DpDomain, s.DirDp)
+	if err != nil {
+		syncStatusf(s, "Sync err: %s.", err)
+		return false, nil
+	}
+
+	localFilesByPath := make(map[string]*localfs.Tree)
+	filePaths := manifest.Paths()
+	for _, localFile := range tree.Files() {
+		localFilesByPath[localFile.PathFromRoot] = localFile
+		filePaths = append(filePaths, localFile.PathFromRoot)
+	}
+	dpFilesByPath := make(map[string]*dp.SyncFile)
+	for idx := range dpFiles {
+		pathFromRoot := filepath.FromSlash(dpFiles[idx].PathFromRoot)
+		dpFilesByPath[pathFromRoot] = &dpFiles[idx]
+		filePaths = append(filePaths, pathFromRoot)
+	}
+	sort.Strings(filePaths)
+
+	changesMade := false
+	conflicts := make([]model.SyncConflict, 0)
+	for idx, pathFromRoot := range filePaths {
+		if (idx > 0 && filePaths[idx-1] == pathFromRoot) || ignoreRules.IgnoredFile(pathFromRoot) {
+			continue
+		}
+		fileChanged, conflict := syncTwoWayFile(s, manifest, uploaded, pathFromRoot,
+			localFilesByPath[pathFromRoot], dpFilesByPath[pathFromRoot])
+		if fileChanged {
+			changesMade = true
+		}
+		if conflict != "" {
+			conflicts = append(conflicts, model.SyncConflict{PathFromRoot: pathFromRoot, Reason: conflict})
+		}
+	}
+
+	return changesMade, conflicts
+}
+
+// syncTwoWayFile syncs one file in two-way sync, returns reason of the
+// conflict if file can't be synced automatically.
+func syncTwoWayFile(s *model.SyncSession, manifest *localfs.Manifest, uploaded *[]string, pathFromRoot string,
+	localFile *localfs.Tree, dpFile *dp.SyncFile) (bool, string) {
+	logging.LogDebugf("ui/syncTwoWayFile('%s', %v, %v)", pathFromRoot, localFile, dpFile)
+	syncRepo := dp.SyncRepo(s.Name)
+	entry, known := manifest.Entry(pathFromRoot)
+	state := localfs.FileSyncState{Known: known, LocalExists: localFile != nil, DpExists: dpFile != nil}
+
+	var localHash string
+	if localFile != nil {
+		var unchanged bool
+		var err error
+		localHash, unchanged, err = manifest.ContentUnchanged(localFile)
+		if err != nil {
+			syncStatusf(s, "Sync err: %s.", err)
+			return false, ""
+		}
+		state.LocalChanged = !unchanged
+	}
+	var dpContent []byte
+	if dpFile != nil && known {
+		if entry.DpModified != "" {
+			state.DpChanged = entry.DpSize != dpFile.Size || entry.DpModified != dpFile.Modified
+		} else {
+			// DataPower file state is unknown after upload - compare content.
+			var err error
+			dpContent, err = syncRepo.GetFileByPath(context.Background(), s.DpDomain, dpFile.Path)
+			if err != nil {
+				syncStatusf(s, "Sync err: %s.", err)
+				return false, ""
+			}
+			state.DpChanged = localfs.ContentHash(dpContent) != entry.Hash
+		}
+	}
+
+	action := localfs.TwoWayAction(state, config.Conf.Sync.DeleteRemoved)
+	logging.LogDebugf("ui/syncTwoWayFile('%s'), state: %v, action: %s", pathFromRoot, state, action)
+	switch action {
+	case localfs.SyncNone:
+		if localFile != nil && dpFile != nil &&
+			(entry.DpSize != dpFile.Size || entry.DpModified != dpFile.Modified) {
+			manifest.SetSynced(pathFromRoot, localFile.ModTime, localHash, dpFile.Size, dpFile.Modified)
+		}
+		return false, ""
+	case localfs.SyncPush:
+		return pushSyncFile(s, manifest, uploaded, localFile, localHash), ""
+	case localfs.SyncPull:
+		return pullSyncFile(s, manifest, dpFile, dpContent), ""
+	case localfs.SyncDeleteDp:
+		return deleteDpFile(s, &localfs.Tree{Name: filepath.Base(pathFromRoot), PathFromRoot: pathFromRoot}, manifest), ""
+	case localfs.SyncDeleteLocal:
+		err := os.Remove(localFile.Path)
+		if err != nil {
+			syncStatusf(s, "Error deleting local file '%s': %s.", localFile.Path, err)
+			return false, ""
+		}
+		manifest.Remove(pathFromRoot)
+		syncStatusf(s, "Local file '%s' deleted.", localFile.Path)
+		return true, ""
+	case localfs.SyncForget:
+		manifest.Remove(pathFromRoot)
+		return false, ""
+	case localfs.SyncCompare:
+		if dpContent == nil {
+			var err error
+			dpContent, err = syncRepo.GetFileByPath(context.Background(), s.DpDomain, dpFile.Path)
+			if err != nil {
+				syncStatusf(s, "Sync err: %s.", err)
+				return false, ""
+			}
+		}
+		if localfs.ContentHash(dpContent) != localHash {
+			return false, "changed on both sides"
+		}
+		manifest.SetSynced(pathFromRoot, localFile.ModTime, localHash, dpFile.Size, dpFile.Modified)
+		return false, ""
+	default:
+		if localFile == nil {
+			return false, "deleted locally"
+		}
+		return false, "deleted on DataPower"
+	}
+}
+
+// pushSyncFile copies local file to the synced DataPower directory.
+func pushSyncFile(s *model.SyncSession, manifest *localfs.Manifest, uploaded *[]string,
+	localFile *localfs.Tree, hash string) bool {
+	localBytes, err := localfs.GetFileByPath(localFile.Path)
+	if err != nil {
+		syncStatusf(s, "Sync err: %s.", err)
+		return false
+	}
+	syncRepo := dp.SyncRepo(s.Name)
+	dpPath := syncRepo.GetFilePath(s.DirDp, filepath.ToSlash(localFile.PathFromRoot))
+	dpParentPath := syncRepo.GetFilePath(dpPath, "..")
+	if !ensureDpDir(s, dpParentPath) {
+		syncStatusf(s, "Error creating dir '%s'.", dpParentPath)
+		return false
+	}
+	res, err := syncRepo.UpdateFileByPath(context.Background(), s.DpDomain, dpPath, localBytes)
+	if err != nil || !res {
+		logging.LogDebug("ui/pushSyncFile(), couldn't update dp file - err: ", err)
+		syncStatusf(s, "Error updating file '%s'.", dpPath)
+		return false
+	}
+	// DataPower file size and modification time are saved on the next sync.
+	manifest.SetSynced(localFile.PathFromRoot, localFile.ModTime, hash, "", "")
+	*uploaded = append(*uploaded, dpPath)
+	syncStatusf(s, "Dp file '%s' updated.", dpPath)
+
+	return true
+}
+
+// pullSyncFile copies DataPower file to the synced local directory.
+func pullSyncFile(s *model.SyncSession, manifest *localfs.Manifest, dpFile *dp.SyncFile, dpContent []byte) bool {
+	pathFromRoot := filepath.FromSlash(dpFile.PathFromRoot)
+	var err error
+	if dpContent == nil {
+		dpContent, err = dp.SyncRepo(s.Name).GetFileByPath(context.Background(), s.DpDomain, dpFile.Path)
+		if err != nil {
+			syncStatusf(s, "Sync err: %s.", err)
+			return false
+		}
+	}
+	localPath := localfs.Repo.GetFilePath(s.DirLocal, pathFromRoot)
+	err = os.MkdirAll(filepath.Dir(localPath), os.ModePerm)
+	if err == nil {
+		err = ioutil.WriteFile(localPath, dpContent, os.ModePerm)
+	}
+	var localFileInfo os.FileInfo
+	if err == nil {
+		localFileInfo, err = os.Stat(localPath)
+	}
+	if err != nil {
+		syncStatusf(s, "Error updating local file '%s': %s.", localPath, err)
+		return false
+	}
+	manifest.SetSynced(pathFromRoot, localFileInfo.ModTime(), localfs.ContentHash(dpContent),
+		dpFile.Size, dpFile.Modified)
+	syncStatusf(s, "Local file '%s' updated.", localPath)
+
+	return true
+}
+
+// ensureDpDir creates DataPower directory (and its parent directories) inside
+// the synced DataPower directory if it doesn't exist.
+func ensureDpDir(s *model.SyncSession, dpDirPath string) bool {
+	if dpDirPath == s.DirDp || !strings.HasPrefix(dpDirPath, s.DirDp) {
+		return true
+	}
+	syncRepo := dp.SyncRepo(s.Name)
+	dpParentPath := syncRepo.GetFilePath(dpDirPath, "..")
+	if !ensureDpDir(s, dpParentPath) {
+		return false
+	}
+	res, err := syncRepo.CreateDirByPath(context.Background(), s.DpDomain, dpParentPath, paths.GetDpFileName(dpDirPath))
+	if err != nil {
+		logging.LogDebug("ui/ensureDpDir(), err: ", err)
+	}
+
+	return res
+}
+
+// resolveSyncConflicts shows conflicts found by the two-way sync - each
+// conflict can be compared using diff and resolved by choosing local or
+// DataPower version of the file.
+func resolveSyncConflicts(s *model.SyncSession) error {
+	conflicts := s.Conflicts()
+	logging.LogDebugf("ui/resolveSyncConflicts(%v), conflicts: %v", s, conflicts)
+	if len(conflicts) == 0 {
+		return errs.Errorf("No conflicts to resolve for sync session '%s'.", s.Name)
+	}
+	// Sync goroutine saves its manifest after it stops with conflicts.
+	if err := waitSyncStopped(s); err != nil {
+		return err
+	}
+
+	manifest := loadSyncManifest(s)
+	for ; len(conflicts) != 0; conflicts = s.Conflicts() {
+		conflictList := make([]string, len(conflicts))
+		for idx, conflict := range conflicts {
+			conflictList[idx] = conflict.String()
+		}
+		dialogSession := listSelectionDialogSessionInfo{
+			message: fmt.Sprintf("Conflicts of sync session %s (Enter - diff & resolve conflict, Esc - close):", s),
+			list:    conflictList}
+		runListSelectionDialog(&dialogSession)
+		if !dialogSession.dialogSubmitted {
+			break
+		}
+		resolved, err := resolveSyncConflict(s, conflicts[dialogSession.selectionIdx])
+		if err != nil {
+			return err
+		}
+		if resolved {
+			// Both sides are the same now - next sync saves new state to the manifest.
+			manifest.Remove(conflicts[dialogSession.selectionIdx].PathFromRoot)
+			s.RemoveConflict(dialogSession.selectionIdx)
+		}
+	}
+	if err := manifest.Save(); err != nil {
+		return err
+	}
+	if len(conflicts) == 0 {
+		syncStatusf(s, "All sync conflicts resolved, sync session can be started again.")
+	}
+
+	return nil
+}
+
+// resolveSyncConflict compares local and DataPower version of the conflicting
+// file using diff and overwrites one of them with the version user chooses.
+func resolveSyncConflict(s *model.SyncSession, conflict model.SyncConflict) (bool, error) {
+	logging.LogDebugf("ui/resolveSyncConflict(%v)", conflict)
+	syncRepo := dp.SyncRepo(s.Name)
+	err := syncRepo.InitNetworkSettings(s.DpAppliance, syncApplianceConfig(s))
+	if err != nil {
+		return false, err
+	}
+	localPath := localfs.Repo.GetFilePath(s.DirLocal, conflict.PathFromRoot)
+	dpPath := syncRepo.GetFilePath(s.DirDp, filepath.ToSlash(conflict.PathFromRoot))
+	dpFileType, err := syncRepo.GetFileTypeByPath(context.Background(), s.DpDomain, dpPath, ".")
+	if err != nil {
+		return false, err
+	}
+	var dpContent []byte
+	if dpFileType == model.ItemFile {
+		dpContent, err = syncRepo.GetFileByPath(context.Background(), s.DpDomain, dpPath)
+		if err != nil {
+			return false, err
+		}
+	}
+	localContent, err := localfs.GetFileByPath(localPath)
+	localExists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	diffDir := extprogs.CreateTempDir("dp")
+	diffDirConfig := model.ItemConfig{Type: model.ItemDirectory, Path: diffDir}
+	fileName := filepath.Base(conflict.PathFromRoot)
+	diffPaths := make([]string, 2)
+	for idx, content := range [][]byte{localContent, dpContent} {
+		diffFileName := fmt.Sprintf("%d_%s_%s", idx+1, []string{"local", "dp"}[idx], fileName)
+		_, err = localfs.Repo.UpdateFile(context.Background(), &diffDirConfig, diffFileName, content)
+		if err != nil {
+			return false, err
+		}
+		diffPaths[idx] = localfs.Repo.GetFilePath(diffDir, diffFileName)
+	}
+	err = diffFilesWithCleanup(diffDir, diffPaths[0], diffPaths[1])
+	if err != nil {
+		return false, err
+	}
+
+	dialogResult := askUserInput(
+		fmt.Sprintf("Resolve conflict for '%s' - keep (l)ocal or (d)ataPower version, (s)kip: ", conflict.PathFromRoot),
+		"", []string{"l", "d", "s"}, false)
+	if !dialogResult.dialogSubmitted {
+		return false, nil
+	}
+	switch {
+	case dialogResult.inputAnswer == "l" && localExists:
+		if !ensureDpDir(s, syncRepo.GetFilePath(dpPath, "..")) {
+			return false, errs.Errorf("Can't create DataPower dir for '%s'.", dpPath)
+		}
+		_, err = syncRepo.UpdateFileByPath(context.Background(), s.DpDomain, dpPath, localContent)
+	case dialogResult.inputAnswer == "l" && dpContent != nil:
+		dpParentPath := syncRepo.GetFilePath(dpPath, "..")
+		dpParentConfig := model.ItemConfig{Type: model.ItemDirectory, DpDomain: s.DpDomain, Path: dpParentPath}
+		_, err = syncRepo.Delete(context.Background(), &dpParentConfig, model.ItemFile, dpParentPath, paths.GetDpFileName(dpPath))
+	case dialogResult.inputAnswer == "d" && dpContent != nil:
+		err = os.MkdirAll(filepath.Dir(localPath), os.ModePerm)
+		if err == nil {
+			err = ioutil.WriteFile(localPath, dpContent, os.ModePerm)
+		}
+	case dialogResult.inputAnswer == "d" && localExists:
+		err = os.Remove(localPath)
+	default:
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	syncStatusf(s, "Sync conflict for '%s' resolved.", conflict.PathFromRoot)
+
+	return true, nil
+}
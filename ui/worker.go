@@ -1,10 +1,12 @@
 package ui
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unicode/utf8"
 
@@ -70,7 +72,8 @@ func (lsd listSelectionDialogSessionInfo) String() string {
 		lsd.dialogCanceled, lsd.dialogSubmitted)
 }
 
-// repos contains references to DataPower and local filesystem repositories.
+// repos contains references to repositories shown in the left and right panel,
+// each panel can show local filesystem or DataPower (dp.Repos[side]) repository.
 var repos = []repo.Repo{model.Left: &dp.Repo, model.Right: &localfs.Repo}
 
 // workingModel contains Model with all information on current DataPower and
@@ -78,17 +81,37 @@ var repos = []repo.Repo{model.Left: &dp.Repo, model.Right: &localfs.Repo}
 var workingModel model.Model = model.Model{} //{currSide: model.Left}
 
 // progressDialogInfo is structure containing all information needed to show
-// progress dialog for long running actions.
+// progress dialog for long running actions. Context of the progress dialog is
+// canceled when user presses Esc (or when dialog is hidden), other input events
+// received while dialog is shown are processed after dialog is hidden.
 type progressDialogInfo struct {
+	mutex         sync.Mutex
 	visible       bool
 	waitUserInput bool
+	depth         int
 	value         int
 	msg           string
+	ctx           context.Context
+	cancel        context.CancelFunc
+	done          chan struct{}
+	pendingEvents []tcell.Event
 }
 
 // progressDialogSession contains progress dialog info for long running actions.
 var progressDialogSession = progressDialogInfo{}
 
+// postedViewUpdates contains view updates posted by background goroutines
+// (sync sessions & jobs) which are not applied to the model yet.
+var postedViewUpdates []events.UpdateViewEvent
+var postedViewUpdatesMutex sync.Mutex
+
+// progressDialogTick is interval of checking for user canceling the action,
+// progress dialog is redrawn every progressDialogRedrawTicks ticks.
+const (
+	progressDialogTick        = 100 * time.Millisecond
+	progressDialogRedrawTicks = 10
+)
+
 // InitialLoad initializes DataPower and local filesystem access and load initial views.
 func InitialLoad() {
 	logging.LogDebug("ui/InitialLoad()")
@@ -100,6 +123,7 @@ func InitialLoad() {
 	}
 	initialLoadDp()
 	initialLoadLocalfs()
+	loadSyncSessions(&workingModel)
 
 	setScreenSize()
 	out.DrawEvent(events.UpdateViewEvent{Type: events.UpdateViewRefresh, Model: &workingModel})
@@ -120,7 +144,7 @@ func initialLoadRepo(side model.Side, repo repo.Repo) {
 	title := repo.GetTitle(initialItem.Config)
 	workingModel.AddNextView(side, initialItem.Config, title)
 
-	itemList, err := repo.GetList(initialItem.Config)
+	itemList, err := repo.GetList(progressContext(), initialItem.Config)
 	if err != nil {
 		logging.LogDebug("ui/initialLoadRepo(): ", err)
 		updateStatus(err.Error())
@@ -129,6 +153,76 @@ func initialLoadRepo(side model.Side, repo repo.Repo) {
 	workingModel.SetItems(side, itemList)
 }
 
+// dpSideOfRepo returns side of the DataPower repository shown in one of the
+// panels, second result is false if given repository is not DataPower repository.
+func dpSideOfRepo(r repo.Repo) (model.Side, bool) {
+	for _, side := range []model.Side{model.Left, model.Right} {
+		if r == dp.Repos[side] {
+			return side, true
+		}
+	}
+	return model.Left, false
+}
+
+// dpRepository contains DataPower specific operations used to copy, export &
+// import items - repository copied for the background job isn't shown in any
+// panel so these operations can't be called using dp.Repos[side].
+type dpRepository interface {
+	repo.Repo
+	GetManagementInterface() string
+	GetObject(ctx context.Context, dpDomain, objectClass, objectName string, persisted bool) ([]byte, error)
+	SetObject(ctx context.Context, dpDomain, objectClass, objectName string, objectContent []byte, existingObject bool) error
+	ParseObjectClassAndName(objectBytes []byte) (objectClass, objectName string, err error)
+	ExportDomain(ctx context.Context, domainName, exportFileName string) ([]byte, error)
+	ExportAppliance(ctx context.Context, applianceConfigName, exportFileName string) ([]byte, error)
+	ImportDomain(ctx context.Context, domainName string, importFileBytes []byte,
+		overwriteFiles, overwriteObjects, dryRun bool) ([]dp.ImportResult, error)
+	ImportAppliance(ctx context.Context, applianceConfigName string, backupFileBytes []byte,
+		overwriteFiles, overwriteObjects, dryRun bool) ([]dp.ImportResult, error)
+	SecureBackupAppliance(ctx context.Context, applianceConfigName, certName, exportDestPath string) error
+}
+
+// isDpRepo returns true if given repository is DataPower repository.
+func isDpRepo(r repo.Repo) bool {
+	_, ok := r.(dpRepository)
+	return ok
+}
+
+// oppositeSide returns side opposite to the given side.
+func oppositeSide(side model.Side) model.Side {
+	if side == model.Left {
+		return model.Right
+	}
+	return model.Left
+}
+
+// isDpSide returns true if DataPower repository is shown on given side.
+func isDpSide(side model.Side) bool {
+	return isDpRepo(repos[side])
+}
+
+// switchPanelRepo switches repository shown in the current panel between
+// local filesystem and DataPower.
+func switchPanelRepo(m *model.Model) error {
+	side := m.CurrSide()
+	logging.LogDebugf("ui/switchPanelRepo(), side: %v", side)
+	if m.SyncModeOn() {
+		return errs.Error("Can't switch panel repository while sync session is running.")
+	}
+
+	if isDpSide(side) {
+		repos[side] = &localfs.Repo
+	} else {
+		dp.Repos[side].DpViewMode = model.DpFilestoreMode
+		repos[side] = dp.Repos[side]
+	}
+	m.ClearViewHistory(side)
+	initialLoadRepo(side, repos[side])
+	updateStatusf("Showing %s in the current panel.", repos[side])
+
+	return nil
+}
+
 // initialLoadDp loads initial DataPower view on the left side.
 func initialLoadDp() {
 	initialLoadRepo(model.Left, &dp.Repo)
@@ -225,7 +319,9 @@ func ProcessInputEvent(event tcell.Event) error {
 		case c == 'B':
 			err = secureBackupCurrent(&workingModel)
 		case c == 'd':
-			err = diffCurrent(&workingModel)
+			err = diffCurrent(&workingModel, false)
+		case c == 'D':
+			err = diffCurrent(&workingModel, true)
 		case k == tcell.KeyF7, c == '7':
 			err = createDirectoryOrDomain(&workingModel)
 		case k == tcell.KeyF8, c == '8':
@@ -235,11 +331,15 @@ func ProcessInputEvent(event tcell.Event) error {
 		case k == tcell.KeyDelete, c == 'x':
 			err = deleteCurrent(&workingModel)
 		case c == 's':
-			err = syncModeToggle(&workingModel)
+			err = addSyncSession(&workingModel)
 		case c == 'S':
 			err = saveDataPowerConfig(&workingModel)
+		case c == 'C':
+			err = showSyncSessions(&workingModel)
 		case c == 'm':
 			err = showStatusMessages(workingModel.Statuses())
+		case c == 'b':
+			err = showJobs()
 		case c == 'e':
 			err = execConfigFile(&workingModel)
 		case c == '0':
@@ -248,6 +348,8 @@ func ProcessInputEvent(event tcell.Event) error {
 			err = showItemInfo(&workingModel)
 		case c == 'P':
 			err = showObjectDetails(&workingModel)
+		case c == 'R':
+			err = switchPanelRepo(&workingModel)
 		case c == 'h':
 			err = extprogs.ShowHelp()
 
@@ -259,15 +361,43 @@ func ProcessInputEvent(event tcell.Event) error {
 		workingModel.ResizeView()
 	}
 
-	if err != nil {
+	var certErr dp.CertificateError
+	switch {
+	case err == nil:
+	case errors.As(err, &certErr):
+		trustCertificate(certErr)
+	case errors.Is(err, context.Canceled):
+		updateStatus("Action canceled.")
+	default:
 		updateStatus(err.Error())
 	}
 
+	applyPostedViewUpdates()
 	out.DrawEvent(events.UpdateViewEvent{Type: events.UpdateViewRefresh, Model: &workingModel})
 
 	return nil
 }
 
+// trustCertificate asks user to trust TLS certificate of the DataPower
+// appliance which can't be verified (trust on first use) and saves its
+// fingerprint to the configuration.
+func trustCertificate(certErr dp.CertificateError) {
+	logging.LogDebugf("ui/trustCertificate(%v)", certErr)
+	updateStatus(certErr.Error())
+	dialogResult := askUserInput(
+		fmt.Sprintf("Certificate '%s' of appliance '%s' not trusted (%s), trust certificate with SHA-256 fingerprint %s (y/n): ",
+			certErr.Subject, certErr.Appliance, certErr.Reason, certErr.Fingerprint),
+		"", []string{"y", "n"}, false)
+	if !dialogResult.dialogSubmitted || dialogResult.inputAnswer != "y" {
+		return
+	}
+	// DataPower repos read the fingerprint from the configuration for each action
+	// (sync sessions when they are started).
+	config.Conf.TrustDpApplianceCertificate(certErr.Appliance, certErr.Fingerprint)
+	updateStatusf("Certificate of appliance '%s' trusted, repeat the last action (restart sync sessions using the appliance).",
+		certErr.Appliance)
+}
+
 // prepareInputDialog prepares information for input dialog showing on console screen.
 func prepareInputDialog(dialogSession *userDialogInputSessionInfo) events.UpdateViewEvent {
 	answer := dialogSession.inputAnswer
@@ -300,7 +430,7 @@ func sliceContainsString(arr []string, toFind string) bool {
 // given or dialog is canceled.
 func askUserInput(question, answer string, allowedAnswers []string, answerMasked bool) userDialogResult {
 	// When progress dialog is shown we don't won't it to hid our input dialog.
-	progressDialogSession.waitUserInput = true
+	setWaitUserInput(true)
 
 	dialogSession := userDialogInputSessionInfo{inputQuestion: question,
 		inputAnswer:          answer,
@@ -329,7 +459,7 @@ loop:
 	}
 	// When progress dialog was shown we show it after we don't need input dialog
 	// any more.
-	progressDialogSession.waitUserInput = false
+	setWaitUserInput(false)
 
 	return userDialogResult{inputAnswer: dialogSession.inputAnswer,
 		dialogCanceled:  dialogSession.dialogCanceled,
@@ -449,8 +579,8 @@ func showView(side model.Side, itemConfig *model.ItemConfig,
 		applianceName := viewItemName
 		if applianceName != ".." && applianceName != "." && applianceName != "" {
 			applicanceConfig := config.Conf.DataPowerAppliances[applianceName]
-			dpTransientPassword := config.DpTransientPasswordMap[applianceName]
-			logging.LogDebugf("ui/showView(), applicanceConfig: '%s'", applicanceConfig)
+			dpTransientPassword := config.DpTransientPassword(applianceName)
+			logging.LogDebugf("ui/showView(), applicanceConfig: %v", applicanceConfig)
 			if applicanceConfig.Password == "" && dpTransientPassword == "" {
 				return dpMissingPasswordError
 			}
@@ -467,7 +597,7 @@ func showView(side model.Side, itemConfig *model.ItemConfig,
 		model.ItemDpObjectClassList, model.ItemDpObjectClass,
 		model.ItemDpStatusClassList, model.ItemDpStatusClass,
 		model.ItemNone:
-		itemList, err = r.GetList(itemConfig)
+		itemList, err = r.GetList(progressContext(), itemConfig)
 		if err != nil {
 			return err
 		}
@@ -543,14 +673,14 @@ func showPrevView() error {
 
 	// If previous view in history requires filestore/object mode, switch mode.
 	switch {
-	case side == model.Left && dp.Repo.DpViewMode == model.DpObjectMode &&
+	case isDpSide(side) && dp.Repos[side].DpViewMode == model.DpObjectMode &&
 		newView.Type != model.ItemDpObjectClassList &&
 		newView.Type != model.ItemDpObjectClass:
-		dp.Repo.DpViewMode = model.DpFilestoreMode
-	case side == model.Left && dp.Repo.DpViewMode == model.DpStatusMode &&
+		dp.Repos[side].DpViewMode = model.DpFilestoreMode
+	case isDpSide(side) && dp.Repos[side].DpViewMode == model.DpStatusMode &&
 		newView.Type != model.ItemDpStatusClassList &&
 		newView.Type != model.ItemDpStatusClass:
-		dp.Repo.DpViewMode = model.DpObjectMode
+		dp.Repos[side].DpViewMode = model.DpObjectMode
 	}
 
 	if newView == oldView {
@@ -572,12 +702,12 @@ func showNextView() error {
 
 	// If next view in history requires object/status mode, switch mode.
 	switch {
-	case side == model.Left && dp.Repo.DpViewMode == model.DpFilestoreMode &&
+	case isDpSide(side) && dp.Repos[side].DpViewMode == model.DpFilestoreMode &&
 		newView.Type == model.ItemDpObjectClassList:
-		dp.Repo.DpViewMode = model.DpObjectMode
-	case side == model.Left && dp.Repo.DpViewMode == model.DpObjectMode &&
+		dp.Repos[side].DpViewMode = model.DpObjectMode
+	case isDpSide(side) && dp.Repos[side].DpViewMode == model.DpObjectMode &&
 		newView.Type == model.ItemDpStatusClassList:
-		dp.Repo.DpViewMode = model.DpStatusMode
+		dp.Repos[side].DpViewMode = model.DpStatusMode
 	}
 
 	if newView == oldView {
@@ -598,7 +728,7 @@ func showNextView() error {
 func showViewHistory() error {
 	logging.LogDebug("ui/showViewHistory()")
 	// When progress dialog is shown we don't won't it to hid our selection dialog.
-	progressDialogSession.waitUserInput = true
+	setWaitUserInput(true)
 
 	side := workingModel.CurrSide()
 	viewHistory := workingModel.ViewConfigHistoryList(side)
@@ -636,15 +766,8 @@ loop:
 	if dialogSession.dialogSubmitted {
 		newView := workingModel.NavCurrentViewIdx(side, dialogSession.selectionIdx)
 		// If proper mode for new view (object mode vs filestore mode).
-		if side == model.Left {
-			switch newView.Type {
-			case model.ItemDpObjectClassList, model.ItemDpObjectClass:
-				dp.Repo.DpViewMode = model.DpObjectMode
-			case model.ItemDpStatusClassList, model.ItemDpStatusClass:
-				dp.Repo.DpViewMode = model.DpStatusMode
-			default:
-				dp.Repo.DpViewMode = model.DpFilestoreMode
-			}
+		if isDpSide(side) {
+			dp.Repos[side].DpViewMode = newView.DpViewMode()
 		}
 		currentItemName := ""
 		currentItem := workingModel.ViewConfigFromHistory(side, dialogSession.selectionIdx+1)
@@ -656,7 +779,7 @@ loop:
 
 	// When progress dialog was shown we show it after we don't need selection
 	// dialog any more.
-	progressDialogSession.waitUserInput = false
+	setWaitUserInput(false)
 
 	return nil
 }
@@ -688,11 +811,27 @@ func processSelectListDialogInput(dialogSession *listSelectionDialogSessionInfo,
 	}
 }
 
+// askDpPasswordIfNotSet asks user for the DataPower appliance password if it
+// is not saved in the configuration or entered before, returns false if user
+// doesn't enter the password.
+func askDpPasswordIfNotSet(applianceName string) bool {
+	applicanceConfig := config.Conf.DataPowerAppliances[applianceName]
+	if applicanceConfig.Password != "" || config.DpTransientPassword(applianceName) != "" {
+		return true
+	}
+	dialogResult := askUserInput("Please enter DataPower password: ", "", nil, true)
+	if dialogResult.dialogCanceled || dialogResult.inputAnswer == "" {
+		return false
+	}
+	config.SetDpTransientPassword(applianceName, dialogResult.inputAnswer)
+	return true
+}
+
 func setCurrentDpPlainPassword(password string) {
 	item := workingModel.CurrItem()
 	applianceName := item.Config.DpAppliance
 	logging.LogDebugf("ui/setDpPlainPassword() applicanceName: '%s'", applianceName)
-	config.DpTransientPasswordMap[applianceName] = password
+	config.SetDpTransientPassword(applianceName, password)
 }
 
 func setScreenSize() {
@@ -726,12 +865,13 @@ func viewCurrent(m *model.Model) error {
 	}
 
 	var err error
+	side := m.CurrSide()
 	switch ci.Config.Type {
 	case model.ItemFile:
-		if m.CurrSide() == model.Left {
+		if isDpSide(side) {
 			currView := workingModel.ViewConfig(workingModel.CurrSide())
-			showProgressDialogf("Fetching '%s' file from DataPower...", ci.Name)
-			fileContent, err := repos[m.CurrSide()].GetFile(currView, ci.Name)
+			ctx := showProgressDialogf("Fetching '%s' file from DataPower...", ci.Name)
+			fileContent, err := repos[m.CurrSide()].GetFile(ctx, currView, ci.Name)
 			hideProgressDialog()
 			if err != nil {
 				return err
@@ -758,11 +898,11 @@ func viewCurrent(m *model.Model) error {
 			return err
 		}
 	case model.ItemDpObject:
-		objectContent, err := dp.Repo.GetObject(ci.Config.DpDomain, ci.Config.Path, ci.Name, false)
+		objectContent, err := dp.Repos[side].GetObject(progressContext(), ci.Config.DpDomain, ci.Config.Path, ci.Name, false)
 		if err != nil {
 			return err
 		}
-		err = extprogs.View(getObjectTmpName(ci.Name), objectContent)
+		err = extprogs.View(getObjectTmpName(side, ci.Name), objectContent)
 		if err != nil {
 			return err
 		}
@@ -772,21 +912,21 @@ func viewCurrent(m *model.Model) error {
 			return err
 		}
 		statusContent, err :=
-			dp.Repo.GetStatus(ci.Config.DpDomain, ci.Config.Parent.Name, statusIdx)
+			dp.Repos[side].GetStatus(progressContext(), ci.Config.DpDomain, ci.Config.Parent.Name, statusIdx)
 		if err != nil {
 			return err
 		}
-		err = extprogs.View(getObjectTmpName(ci.Name), statusContent)
+		err = extprogs.View(getObjectTmpName(side, ci.Name), statusContent)
 		if err != nil {
 			return err
 		}
 	case model.ItemDpStatusClass:
 		statusesContent, err :=
-			dp.Repo.GetStatuses(ci.Config.DpDomain, ci.Config.Name)
+			dp.Repos[side].GetStatuses(progressContext(), ci.Config.DpDomain, ci.Config.Name)
 		if err != nil {
 			return err
 		}
-		err = extprogs.View(getObjectTmpName(ci.Name), statusesContent)
+		err = extprogs.View(getObjectTmpName(side, ci.Name), statusesContent)
 		if err != nil {
 			return err
 		}
@@ -805,12 +945,13 @@ func editCurrent(m *model.Model) error {
 		return errs.Errorf("Can't edit parent directory '%s'.", ci.Name)
 	}
 	var err error
+	side := m.CurrSide()
 	switch ci.Config.Type {
 	case model.ItemFile:
 		currView := workingModel.ViewConfig(workingModel.CurrSide())
-		if m.CurrSide() == model.Left {
-			showProgressDialogf("Fetching '%s' file from DataPower...", ci.Name)
-			fileContent, err := repos[m.CurrSide()].GetFile(currView, ci.Name)
+		if isDpSide(side) {
+			ctx := showProgressDialogf("Fetching '%s' file from DataPower...", ci.Name)
+			fileContent, err := repos[m.CurrSide()].GetFile(ctx, currView, ci.Name)
 			hideProgressDialog()
 			if err != nil {
 				return err
@@ -820,7 +961,7 @@ func editCurrent(m *model.Model) error {
 				return err
 			}
 			if changed {
-				_, err := repos[m.CurrSide()].UpdateFile(currView, ci.Name, newFileContent)
+				_, err := repos[m.CurrSide()].UpdateFile(progressContext(), currView, ci.Name, newFileContent)
 				if err != nil {
 					return err
 				}
@@ -853,16 +994,16 @@ func editCurrent(m *model.Model) error {
 		updateStatusf("DataPower configuration '%s' updated.", ci.Name)
 
 	case model.ItemDpObject:
-		objectContent, err := dp.Repo.GetObject(ci.Config.DpDomain, ci.Config.Path, ci.Name, false)
+		objectContent, err := dp.Repos[side].GetObject(progressContext(), ci.Config.DpDomain, ci.Config.Path, ci.Name, false)
 		if err != nil {
 			return err
 		}
-		changed, newObjectContent, err := extprogs.Edit(getObjectTmpName(ci.Name), objectContent)
+		changed, newObjectContent, err := extprogs.Edit(getObjectTmpName(side, ci.Name), objectContent)
 		if err != nil {
 			return err
 		}
 		if changed {
-			err := dp.Repo.SetObject(ci.Config.DpDomain, ci.Config.Path, ci.Name, newObjectContent, true)
+			err := dp.Repos[side].SetObject(progressContext(), ci.Config.DpDomain, ci.Config.Path, ci.Name, newObjectContent, true)
 			if err != nil {
 				return err
 			}
@@ -894,10 +1035,16 @@ func copyCurrent(m *model.Model) error {
 	}
 	updateStatusf("Copy from '%s' to '%s', items: %v", fromViewConfig.Path, toViewConfig.Path, itemsDisplayToCopy)
 
+	if copyAsJob(itemsToCopy) {
+		return copyItemsJob(m, itemsToCopy, itemsDisplayToCopy)
+	}
+
+	ctx := showProgressDialog("Copying files from/to DataPower...")
+	defer hideProgressDialog()
 	var confirmOverwrite = "n"
 	var err error
 	for _, item := range itemsToCopy {
-		confirmOverwrite, err = copyItem(repos[fromSide], repos[toSide], fromViewConfig, toViewConfig, item, confirmOverwrite)
+		confirmOverwrite, err = copyItem(ctx, repos[fromSide], repos[toSide], fromViewConfig, toViewConfig, item, confirmOverwrite)
 		if err != nil {
 			return err
 		}
@@ -909,6 +1056,79 @@ func copyCurrent(m *model.Model) error {
 	return showItem(toSide, m.ViewConfig(toSide), ".")
 }
 
+// copyAsJob checks if items should be copied in the background job - copy of
+// directories, filestores, domains and appliances can take a long time.
+func copyAsJob(items []model.Item) bool {
+	for _, item := range items {
+		switch item.Config.Type {
+		case model.ItemDirectory, model.ItemDpFilestore, model.ItemDpDomain, model.ItemDpConfiguration:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// copyItemsJob asks user all questions needed to copy items and starts the
+// background job copying them.
+func copyItemsJob(m *model.Model, items []model.Item, itemsDisplay []string) error {
+	fromSide := m.CurrSide()
+	toSide := m.OtherSide()
+	fromViewConfig := m.ViewConfig(fromSide)
+	toViewConfig := m.ViewConfig(toSide)
+
+	confirmOverwrite := "na"
+	for _, item := range items {
+		switch item.Config.Type {
+		case model.ItemDirectory, model.ItemDpFilestore:
+			if confirmOverwrite == "ya" {
+				continue
+			}
+			dialogResult := askUserInput(
+				fmt.Sprintf("Overwrite existing files when copying to '%s' (y/n): ", toViewConfig.Path),
+				"", []string{"y", "n"}, false)
+			if dialogResult.dialogCanceled {
+				updateStatus("Copy canceled.")
+				return nil
+			}
+			if dialogResult.inputAnswer == "y" {
+				confirmOverwrite = "ya"
+			}
+		case model.ItemDpConfiguration:
+			if !askDpPasswordIfNotSet(item.Config.DpAppliance) {
+				return nil
+			}
+		}
+	}
+
+	fromRepo := jobRepo(fromSide)
+	toRepo := jobRepo(toSide)
+	startJob(fmt.Sprintf("Copy %v to '%s'", itemsDisplay, toViewConfig.Path), "",
+		func(ctx context.Context) ([]byte, error) {
+			fromRepo.InvalidateCache()
+			toRepo.InvalidateCache()
+			defer postViewUpdate(events.UpdateViewEvent{Type: events.UpdateViewRefreshPanel, Side: toSide})
+			for _, item := range items {
+				_, err := copyItem(ctx, fromRepo, toRepo, fromViewConfig, toViewConfig, item, confirmOverwrite)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return nil, nil
+		})
+
+	return nil
+}
+
+// jobRepo returns repository shown in the panel to be used by the background
+// job, DataPower repository is copied so job doesn't change its state.
+func jobRepo(side model.Side) repo.Repo {
+	if isDpSide(side) {
+		return dp.Repos[side].Copy()
+	}
+	return repos[side]
+}
+
 func diffFilesWithCleanup(tmpDir, oldPath, newPath string) error {
 	logging.LogDebug("ui/diffFiles()")
 	err := extprogs.Diff(oldPath, newPath)
@@ -946,33 +1166,25 @@ func secureBackupCurrent(m *model.Model) error {
 
 	applianceName := itemAppliance.Config.DpAppliance
 
-	applicanceConfig := config.Conf.DataPowerAppliances[applianceName]
-	dpTransientPassword := config.DpTransientPasswordMap[applianceName]
-	logging.LogDebugf("ui/secureBackupCurrent(), applicanceConfig: '%s'", applicanceConfig)
-	if applicanceConfig.Password == "" && dpTransientPassword == "" {
-		logging.LogDebugf("ui/secureBackupCurrent(), before asking password.")
-		dialogResult := askUserInput("Please enter DataPower password: ", "", nil, true)
-		logging.LogDebugf("ui/secureBackupCurrent(), after asking password (%#v).", dialogResult)
-		if dialogResult.dialogCanceled || dialogResult.inputAnswer == "" {
-			return nil
-		}
-		setCurrentDpPlainPassword(dialogResult.inputAnswer)
+	if !askDpPasswordIfNotSet(applianceName) {
+		return nil
 	}
 
-	dpRepo := dp.Repo
+	if !isDpSide(m.CurrSide()) {
+		return errs.Errorf("Must select a DataPower configuration to perform secure backup.")
+	}
+	// Secure backup is run as a job using its own copy of DataPower repo.
+	dpRepo := dp.Repos[m.CurrSide()].Copy()
 	fileRepo := repos[m.OtherSide()]
 	certsItem := model.ItemConfig{Type: model.ItemDpObjectClass,
 		DpAppliance: applianceName,
 		DpDomain:    "default",
 		Name:        "CryptoCertificate",
 		Path:        "CryptoCertificate"}
-	currentViewMode := dpRepo.DpViewMode
-	restoreCurrentViewMode := func() {
-		dpRepo.DpViewMode = currentViewMode
-	}
 	dpRepo.DpViewMode = model.DpObjectMode
-	defer restoreCurrentViewMode()
-	certItemList, err := dpRepo.GetList(&certsItem)
+	ctx := showProgressDialog("Fetching DataPower certificates...")
+	certItemList, err := dpRepo.GetList(ctx, &certsItem)
+	hideProgressDialog()
 	if err != nil {
 		return err
 	}
@@ -1012,87 +1224,32 @@ loop:
 
 		dpExportDirName := "secure_backup_" + time.Now().Format("20060102150405")
 		localExportDirName := applianceName + "_" + dpExportDirName
-		dpExportDestPath := "temporary:/" + dpExportDirName
 		toSide := m.OtherSide()
 		toViewConfig := m.ViewConfig(toSide)
 
 		toParentPath := toViewConfig.Path
 		logging.LogDebugf("ui/secureBackupCurrent(), certName: '%v', toParentPath '%v', dpExportDirName: '%v', localExportDirName: '%v'",
 			certName, toParentPath, dpExportDirName, localExportDirName)
-		destDirType, err := fileRepo.GetFileType(toViewConfig, toParentPath, localExportDirName)
+		destDirType, err := fileRepo.GetFileType(progressContext(), toViewConfig, toParentPath, localExportDirName)
 		if err != nil {
 			return err
 		}
 		if destDirType != model.ItemNone {
 			return errs.Errorf("Local secure backup directory '%v' already exists.", localExportDirName)
 		}
-		_, err = fileRepo.CreateDir(toViewConfig, toParentPath, localExportDirName)
+		_, err = fileRepo.CreateDir(progressContext(), toViewConfig, toParentPath, localExportDirName)
 		if err != nil {
 			return errs.Errorf("Can't create local secure backup directory with name '%s' - '%v'.", localExportDirName, err)
 		}
 		updateStatusf("Local secure backup directory '%s' created.", localExportDirName)
 
-		showProgressDialogf("Secure DataPower appliance backup '%s'...", applianceName)
-		err = dp.Repo.SecureBackupAppliance(applianceName, certName, dpExportDestPath)
-		logging.LogDebugf("ui/secureBackupCurrent(), created backup at '%v'", dpExportDestPath)
-		hideProgressDialog()
-		if err != nil {
-			return err
-		}
-
-		dpTemporaryFilestoreConfig := model.ItemConfig{
-			Parent:      nil,
-			Type:        model.ItemDpFilestore,
-			Path:        "temporary:",
-			Name:        "temporary:",
-			DpAppliance: applianceName,
-			DpDomain:    "default",
-			DpFilestore: "temporary:"}
-		dpBackupDirConfig := model.ItemConfig{
-			Parent:      &dpTemporaryFilestoreConfig,
-			Type:        model.ItemDirectory,
-			Path:        dpExportDestPath,
-			Name:        dpExportDirName,
-			DpAppliance: applianceName,
-			DpDomain:    "default",
-			DpFilestore: "temporary:"}
 		dpRepo.DpViewMode = model.DpFilestoreMode
-		// Refresh termporary: filestore (SOMA filestore is cached after we fetch
-		//   it the first time).
-		_, err = dpRepo.GetList(&dpTemporaryFilestoreConfig)
-		if err != nil {
-			return err
-		}
-		secureBackupItems, err := dpRepo.GetList(&dpBackupDirConfig)
-		logging.LogDebugf("ui/secureBackupCurrent(), secureBackupItems: '%v'", secureBackupItems)
-		if err != nil {
-			return err
-		}
-
-		showProgressDialog("Copying secure backup files from DataPower...")
-		defer hideProgressDialog()
-		fileViewBackupDirConfig := model.ItemConfig{Parent: toViewConfig,
-			Path:        fileRepo.GetFilePath(toViewConfig.Path, localExportDirName),
-			DpAppliance: toViewConfig.DpAppliance,
-			DpDomain:    toViewConfig.DpDomain,
-			DpFilestore: toViewConfig.DpFilestore}
-		for _, sbi := range secureBackupItems {
-			if sbi.Name != ".." {
-				_, err = copyItem(&dpRepo, fileRepo, &dpBackupDirConfig, &fileViewBackupDirConfig, sbi, "y")
-				if err != nil {
-					return err
-				}
-			}
-		}
-
-		dpBackupDirItem := model.Item{Name: dpExportDirName, Config: &dpBackupDirConfig}
-		_, err = deleteItem(&dpRepo, &dpTemporaryFilestoreConfig, dpBackupDirItem, "n")
-		if err != nil {
-			return err
-		}
-		updateStatusf("Secure backup appliance directory deleted ('%v').", dpExportDirName)
-		updateStatusf("Secure backup copied to new local directory '%v'.", localExportDirName)
-		refreshView(m, model.Right)
+		startJob(fmt.Sprintf("Secure backup of appliance '%s'", applianceName), "",
+			func(ctx context.Context) ([]byte, error) {
+				defer postViewUpdate(events.UpdateViewEvent{Type: events.UpdateViewRefreshPanel, Side: toSide})
+				return nil, secureBackupAppliance(ctx, dpRepo, fileRepo, toViewConfig,
+					applianceName, certName, dpExportDirName, localExportDirName)
+			})
 	} else {
 		updateStatusf("Secure backup canceled...")
 	}
@@ -1100,91 +1257,369 @@ loop:
 	return nil
 }
 
-func diffCurrent(m *model.Model) error {
-	logging.LogDebug("ui/diffCurrent()")
-	dpItem := m.CurrItemForSide(model.Left)
-	if dpItem.Name == ".." {
-		return errs.Errorf("Can't diff dp parent directory '%s',", dpItem.Name)
+// secureBackupAppliance creates secure backup of the appliance in the new
+// DataPower temporary directory, copies it to the local directory and deletes
+// DataPower directory.
+func secureBackupAppliance(ctx context.Context, dpRepo dpRepository, fileRepo repo.Repo, toViewConfig *model.ItemConfig,
+	applianceName, certName, dpExportDirName, localExportDirName string) error {
+	logging.LogDebugf("ui/secureBackupAppliance('%s', '%s', '%s', '%s')",
+		applianceName, certName, dpExportDirName, localExportDirName)
+	dpExportDestPath := "temporary:/" + dpExportDirName
+	updateProgressf(ctx, "Secure DataPower appliance backup '%s'...", applianceName)
+	err := dpRepo.SecureBackupAppliance(ctx, applianceName, certName, dpExportDestPath)
+	logging.LogDebugf("ui/secureBackupAppliance(), created backup at '%v'", dpExportDestPath)
+	if err != nil {
+		return err
+	}
+
+	dpTemporaryFilestoreConfig := model.ItemConfig{
+		Parent:      nil,
+		Type:        model.ItemDpFilestore,
+		Path:        "temporary:",
+		Name:        "temporary:",
+		DpAppliance: applianceName,
+		DpDomain:    "default",
+		DpFilestore: "temporary:"}
+	dpBackupDirConfig := model.ItemConfig{
+		Parent:      &dpTemporaryFilestoreConfig,
+		Type:        model.ItemDirectory,
+		Path:        dpExportDestPath,
+		Name:        dpExportDirName,
+		DpAppliance: applianceName,
+		DpDomain:    "default",
+		DpFilestore: "temporary:"}
+	// Refresh termporary: filestore (SOMA filestore is cached after we fetch
+	//   it the first time).
+	_, err = dpRepo.GetList(ctx, &dpTemporaryFilestoreConfig)
+	if err != nil {
+		return err
+	}
+	secureBackupItems, err := dpRepo.GetList(ctx, &dpBackupDirConfig)
+	logging.LogDebugf("ui/secureBackupAppliance(), secureBackupItems: '%v'", secureBackupItems)
+	if err != nil {
+		return err
+	}
+
+	updateProgressf(ctx, "Copying secure backup files from DataPower...")
+	fileViewBackupDirConfig := model.ItemConfig{Parent: toViewConfig,
+		Path:        fileRepo.GetFilePath(toViewConfig.Path, localExportDirName),
+		DpAppliance: toViewConfig.DpAppliance,
+		DpDomain:    toViewConfig.DpDomain,
+		DpFilestore: toViewConfig.DpFilestore}
+	for _, sbi := range secureBackupItems {
+		if sbi.Name != ".." {
+			_, err = copyItem(ctx, dpRepo, fileRepo, &dpBackupDirConfig, &fileViewBackupDirConfig, sbi, "y")
+			if err != nil {
+				return err
+			}
+		}
 	}
-	localItem := m.CurrItemForSide(model.Right)
-	if localItem.Name == ".." && dpItem.Config.Type != model.ItemDpObject {
-		return errs.Errorf("Can't diff local parent directory '%s',", localItem.Name)
+
+	_, err = dpRepo.Delete(ctx, &dpTemporaryFilestoreConfig, model.ItemDirectory,
+		dpTemporaryFilestoreConfig.Path, dpExportDirName)
+	if err != nil {
+		return err
 	}
+	updateStatusCtxf(ctx, "Secure backup appliance directory deleted ('%v').", dpExportDirName)
+	updateStatusCtxf(ctx, "Secure backup copied to new local directory '%v'.", localExportDirName)
 
-	dpView := m.ViewConfig(model.Left)
-	localView := m.ViewConfig(model.Right)
+	return nil
+}
 
-	if dpItem.Config.Type == localItem.Config.Type ||
-		(dpItem.Config.Type == model.ItemDpFilestore && localItem.Config.Type == model.ItemDirectory) ||
-		(dpItem.Config.Type == model.ItemDpObject && dpItem.Modified == "modified") {
+// diffCurrent compares current items in the left and right panel using
+// external diff command or (if structural is true) using built-in structural
+// comparison which is available only for DataPower objects and domains.
+func diffCurrent(m *model.Model, structural bool) error {
+	logging.LogDebugf("ui/diffCurrent(%t)", structural)
+	leftItem := m.CurrItemForSide(model.Left)
+	rightItem := m.CurrItemForSide(model.Right)
 
+	// DataPower objects are compared between panels or (if only one panel shows
+	// objects) saved configuration is compared to the configuration in memory.
+	objectSide := m.CurrSide()
+	if m.CurrItemForSide(objectSide).Config.Type != model.ItemDpObject {
+		objectSide = m.OtherSide()
 	}
+	objectItem := m.CurrItemForSide(objectSide)
 	switch {
-	case dpItem.Config.Type == localItem.Config.Type ||
-		(dpItem.Config.Type == model.ItemDpFilestore && localItem.Config.Type == model.ItemDirectory):
-		dpCopyDir := extprogs.CreateTempDir("dp")
-		updateStatusf("Created tmp dir on localfs '%s'", dpCopyDir)
-		localViewTmp := model.ItemConfig{Type: model.ItemDirectory, Path: dpCopyDir}
-		copyItem(&dp.Repo, localfs.Repo, dpView, &localViewTmp, *dpItem, "y")
-		var dpDirName string
-		switch dpItem.Config.Type {
-		case model.ItemDpFilestore:
-			dpDirName = dpItem.Name[0 : len(dpItem.Name)-1]
-		default:
-			dpDirName = dpItem.Name
-		}
-		dpCopyItemPath := localfs.Repo.GetFilePath(dpCopyDir, dpDirName)
-		localItemPath := localfs.Repo.GetFilePath(localView.Path, localItem.Name)
+	case leftItem.Config.Type == model.ItemDpDomain && rightItem.Config.Type == model.ItemDpDomain &&
+		isDpSide(model.Left) && isDpSide(model.Right):
+		return diffDomains(leftItem, rightItem, structural)
+	case leftItem.Config.Type == model.ItemDpObject && rightItem.Config.Type == model.ItemDpObject:
+		return diffObjects(leftItem, rightItem, structural)
+	case objectItem.Config.Type == model.ItemDpObject && objectItem.Modified == "modified":
+		return diffObjectChanges(objectSide, objectItem, structural)
+	case objectItem.Config.Type == model.ItemDpObject:
+		err := errs.Errorf("Can't view changes on DataPower object '%s' if not modified (%s)",
+			objectItem.Name, objectItem.Modified)
+		logging.LogDebug(err)
+		return err
+	case structural:
+		return errs.Error("Structural diff can be used only on DataPower objects and domains.")
+	}
 
-		err := diffFilesWithCleanup(dpCopyDir, dpCopyItemPath, localItemPath)
+	if leftItem.Name == ".." || rightItem.Name == ".." {
+		return errs.Errorf("Can't diff parent directory '..'.")
+	}
+	isDirType := func(itemType model.ItemType) bool {
+		return itemType == model.ItemDirectory || itemType == model.ItemDpFilestore
+	}
+	if leftItem.Config.Type != rightItem.Config.Type &&
+		!(isDirType(leftItem.Config.Type) && isDirType(rightItem.Config.Type)) {
+		err := errs.Errorf("Can't compare different file types '%s' (%s) to '%s' (%s)",
+			leftItem.Name, string(leftItem.Config.Type), rightItem.Name, string(rightItem.Config.Type))
+		logging.LogDebug(err)
+		return err
+	}
 
+	diffDir := extprogs.CreateTempDir("dp")
+	updateStatusf("Created tmp dir on localfs '%s'", diffDir)
+	leftItemPath, err := diffItemPath(m, model.Left, diffDir)
+	if err != nil {
+		return err
+	}
+	rightItemPath, err := diffItemPath(m, model.Right, diffDir)
+	if err != nil {
 		return err
-	case dpItem.Config.Type == model.ItemDpObject && dpItem.Modified == "modified":
-		dpCopyDir := extprogs.CreateTempDir("dp")
-		updateStatusf("Created tmp dir on localfs '%s'", dpCopyDir)
+	}
+
+	return diffFilesWithCleanup(diffDir, leftItemPath, rightItemPath)
+}
+
+// diffItemPath returns local filesystem path of the current item on given side
+// which is used for comparison - DataPower items are copied to the subdirectory
+// of the temporary diff directory first.
+func diffItemPath(m *model.Model, side model.Side, diffDir string) (string, error) {
+	item := m.CurrItemForSide(side)
+	viewConfig := m.ViewConfig(side)
+	if !isDpSide(side) {
+		return localfs.Repo.GetFilePath(viewConfig.Path, item.Name), nil
+	}
+
+	sideDirName := "left"
+	if side == model.Right {
+		sideDirName = "right"
+	}
+	diffDirConfig := model.ItemConfig{Type: model.ItemDirectory, Path: diffDir}
+	_, err := localfs.Repo.CreateDir(progressContext(), &diffDirConfig, diffDir, sideDirName)
+	if err != nil {
+		return "", err
+	}
+	sideDir := localfs.Repo.GetFilePath(diffDir, sideDirName)
+	sideDirConfig := model.ItemConfig{Type: model.ItemDirectory, Path: sideDir}
+	ctx := showProgressDialogf("Copying '%s' from DataPower...", item.Name)
+	defer hideProgressDialog()
+	_, err = copyItem(ctx, repos[side], localfs.Repo, viewConfig, &sideDirConfig, *item, "y")
+	if err != nil {
+		return "", err
+	}
+
+	dpDirName := item.Name
+	if item.Config.Type == model.ItemDpFilestore {
+		dpDirName = item.Name[0 : len(item.Name)-1]
+	}
+	return localfs.Repo.GetFilePath(sideDir, dpDirName), nil
+}
 
-		localViewTmp := model.ItemConfig{Type: model.ItemDirectory, Path: dpCopyDir}
+// diffObjects compares DataPower objects shown in the left and right panel.
+func diffObjects(leftItem, rightItem *model.Item, structural bool) error {
+	logging.LogDebugf("ui/diffObjects(%v, %v, %t)", leftItem, rightItem, structural)
+	items := []*model.Item{model.Left: leftItem, model.Right: rightItem}
+	objectContents := make([][]byte, 2)
+	for side, item := range items {
+		objectContent, err := dp.Repos[side].GetObject(progressContext(),
+			item.Config.DpDomain, item.Config.Path, item.Name, false)
+		if err != nil {
+			return err
+		}
+		objectContents[side] = objectContent
+	}
 
-		objectContentMemory, err := dp.Repo.GetObject(
-			dpItem.Config.DpDomain, dpItem.Config.Path, dpItem.Name, false)
+	if structural {
+		return showStructuralDiff(
+			fmt.Sprintf("Object '%s' (%s) in '%s' (%s) compared to object '%s' (%s) in '%s' (%s)",
+				leftItem.Name, leftItem.Config.Path, leftItem.Config.DpAppliance, leftItem.Config.DpDomain,
+				rightItem.Name, rightItem.Config.Path, rightItem.Config.DpAppliance, rightItem.Config.DpDomain),
+			objectContents[model.Left], objectContents[model.Right])
+	}
+
+	diffDir := extprogs.CreateTempDir("dp")
+	updateStatusf("Created tmp dir on localfs '%s'", diffDir)
+	diffDirConfig := model.ItemConfig{Type: model.ItemDirectory, Path: diffDir}
+
+	objectPaths := make([]string, 2)
+	for side, item := range items {
+		objectContent := objectContents[side]
+		objectFileSuffix, err := dpObjectFileSuffix(dp.Repos[side])
+		if err != nil {
+			return err
+		}
+		objectFileName := fmt.Sprintf("%d_%s_%s%s",
+			side+1, item.Config.DpAppliance, item.Name, objectFileSuffix)
+		_, err = localfs.Repo.UpdateFile(progressContext(), &diffDirConfig, objectFileName, objectContent)
 		if err != nil {
 			return err
 		}
-		objectContentSaved, err := dp.Repo.GetObject(
-			dpItem.Config.DpDomain, dpItem.Config.Path, dpItem.Name, true)
+		objectPaths[side] = localfs.Repo.GetFilePath(diffDir, objectFileName)
+	}
+
+	return diffFilesWithCleanup(diffDir, objectPaths[model.Left], objectPaths[model.Right])
+}
+
+// diffDomains compares all DataPower objects of domains shown in the left and
+// right panel and shows objects which differ - each object can be compared
+// using diff.
+func diffDomains(leftItem, rightItem *model.Item, structural bool) error {
+	logging.LogDebugf("ui/diffDomains(%v, %v, %t)", leftItem, rightItem, structural)
+	if dp.Repos[model.Left].GetManagementInterface() != dp.Repos[model.Right].GetManagementInterface() {
+		return errs.Errorf("Can't compare domains on appliances using different management interfaces (%s, %s).",
+			dp.Repos[model.Left].GetManagementInterface(), dp.Repos[model.Right].GetManagementInterface())
+	}
+
+	domainObjects := make([][]dp.DomainObject, 2)
+	for side, item := range []*model.Item{model.Left: leftItem, model.Right: rightItem} {
+		ctx := showProgressDialogf("Fetching objects of domain '%s' (%s)...",
+			item.Config.DpDomain, item.Config.DpAppliance)
+		objects, err := dp.Repos[side].GetDomainObjects(ctx, item.Config.DpDomain)
+		hideProgressDialog()
 		if err != nil {
 			return err
 		}
+		domainObjects[side] = objects
+	}
 
-		objectNameMemory := dpItem.Name + "_memory.xml"
-		objectNameSaved := dpItem.Name + "_saved.xml"
+	objectDiffs := dp.DiffDomainObjects(domainObjects[model.Left], domainObjects[model.Right])
+	updateStatusf("Compared domain '%s' (%s) to domain '%s' (%s), %d object(s) differ.",
+		leftItem.Config.DpDomain, leftItem.Config.DpAppliance,
+		rightItem.Config.DpDomain, rightItem.Config.DpAppliance, len(objectDiffs))
+	if len(objectDiffs) == 0 {
+		return nil
+	}
 
-		_, err = localfs.Repo.UpdateFile(&localViewTmp, objectNameMemory, objectContentMemory)
+	diffList := make([]string, len(objectDiffs))
+	for idx, objectDiff := range objectDiffs {
+		diffList[idx] = objectDiff.String()
+	}
+	dialogSession := listSelectionDialogSessionInfo{
+		message: fmt.Sprintf("Objects which differ in domain '%s' (%s) and '%s' (%s) (Enter - diff object, Esc - close):",
+			leftItem.Config.DpDomain, leftItem.Config.DpAppliance,
+			rightItem.Config.DpDomain, rightItem.Config.DpAppliance),
+		list: diffList}
+	for {
+		dialogSession.dialogSubmitted = false
+		runListSelectionDialog(&dialogSession)
+		if !dialogSession.dialogSubmitted {
+			return nil
+		}
+		err := diffDomainObject(objectDiffs[dialogSession.selectionIdx],
+			leftItem.Config.DpAppliance, rightItem.Config.DpAppliance, structural)
 		if err != nil {
 			return err
 		}
-		_, err = localfs.Repo.UpdateFile(&localViewTmp, objectNameSaved, objectContentSaved)
+	}
+}
+
+// diffDomainObject compares configurations of one DataPower object from two
+// domains using diff (missing object is compared as an empty file).
+func diffDomainObject(objectDiff dp.ObjectDiff, leftAppliance, rightAppliance string, structural bool) error {
+	logging.LogDebugf("ui/diffDomainObject(%v, '%s', '%s', %t)",
+		objectDiff, leftAppliance, rightAppliance, structural)
+	if structural {
+		return showStructuralDiff(
+			fmt.Sprintf("Object '%s' (%s) in '%s' compared to object in '%s' (%s)",
+				objectDiff.Name, objectDiff.Class, leftAppliance, rightAppliance, objectDiff.Status),
+			objectDiff.Left, objectDiff.Right)
+	}
+
+	diffDir := extprogs.CreateTempDir("dp")
+	updateStatusf("Created tmp dir on localfs '%s'", diffDir)
+	diffDirConfig := model.ItemConfig{Type: model.ItemDirectory, Path: diffDir}
+
+	objectFileSuffix, err := dpObjectFileSuffix(dp.Repos[model.Left])
+	if err != nil {
+		return err
+	}
+	objectPaths := make([]string, 2)
+	for side, objectContent := range [][]byte{model.Left: objectDiff.Left, model.Right: objectDiff.Right} {
+		appliance := leftAppliance
+		if model.Side(side) == model.Right {
+			appliance = rightAppliance
+		}
+		objectFileName := fmt.Sprintf("%d_%s_%s%s",
+			side+1, appliance, objectDiff.Name, objectFileSuffix)
+		_, err = localfs.Repo.UpdateFile(progressContext(), &diffDirConfig, objectFileName, objectContent)
 		if err != nil {
 			return err
 		}
+		objectPaths[side] = localfs.Repo.GetFilePath(diffDir, objectFileName)
+	}
+
+	return diffFilesWithCleanup(diffDir, objectPaths[model.Left], objectPaths[model.Right])
+}
+
+// diffObjectChanges compares saved configuration of the DataPower object with
+// the configuration in memory.
+func diffObjectChanges(side model.Side, dpItem *model.Item, structural bool) error {
+	logging.LogDebugf("ui/diffObjectChanges(%v, %v, %t)", side, dpItem, structural)
+	objectContentMemory, err := dp.Repos[side].GetObject(progressContext(),
+		dpItem.Config.DpDomain, dpItem.Config.Path, dpItem.Name, false)
+	if err != nil {
+		return err
+	}
+	objectContentSaved, err := dp.Repos[side].GetObject(progressContext(),
+		dpItem.Config.DpDomain, dpItem.Config.Path, dpItem.Name, true)
+	if err != nil {
+		return err
+	}
 
-		dpObjectMemoryPath := localfs.Repo.GetFilePath(dpCopyDir, objectNameMemory)
-		dpObjectSavedPath := localfs.Repo.GetFilePath(dpCopyDir, objectNameSaved)
+	if structural {
+		return showStructuralDiff(
+			fmt.Sprintf("Saved object '%s' (%s) compared to object in memory", dpItem.Name, dpItem.Config.Path),
+			objectContentSaved, objectContentMemory)
+	}
+
+	dpCopyDir := extprogs.CreateTempDir("dp")
+	updateStatusf("Created tmp dir on localfs '%s'", dpCopyDir)
 
-		err = diffFilesWithCleanup(dpCopyDir, dpObjectSavedPath, dpObjectMemoryPath)
+	localViewTmp := model.ItemConfig{Type: model.ItemDirectory, Path: dpCopyDir}
 
+	objectNameMemory := dpItem.Name + "_memory.xml"
+	objectNameSaved := dpItem.Name + "_saved.xml"
+
+	_, err = localfs.Repo.UpdateFile(progressContext(), &localViewTmp, objectNameMemory, objectContentMemory)
+	if err != nil {
 		return err
-	case dpItem.Config.Type == model.ItemDpObject:
-		err := errs.Errorf("Can't view changes on DataPower object '%s' if not modified (%s)",
-			dpItem.Name, dpItem.Modified)
-		logging.LogDebug(err)
+	}
+	_, err = localfs.Repo.UpdateFile(progressContext(), &localViewTmp, objectNameSaved, objectContentSaved)
+	if err != nil {
 		return err
-	default:
-		err := errs.Errorf("Can't compare different file types '%s' (%s) to '%s' (%s)",
-			dpItem.Name, string(dpItem.Config.Type), localItem.Name, string(localItem.Config.Type))
-		logging.LogDebug(err)
+	}
+
+	dpObjectMemoryPath := localfs.Repo.GetFilePath(dpCopyDir, objectNameMemory)
+	dpObjectSavedPath := localfs.Repo.GetFilePath(dpCopyDir, objectNameSaved)
+
+	return diffFilesWithCleanup(dpCopyDir, dpObjectSavedPath, dpObjectMemoryPath)
+}
+
+// showStructuralDiff compares DataPower object configurations structurally
+// and shows added, removed and changed properties in the viewer.
+func showStructuralDiff(title string, oldObject, newObject []byte) error {
+	logging.LogDebugf("ui/showStructuralDiff('%s')", title)
+	propertyDiffs, err := dp.CompareObjects(oldObject, newObject)
+	if err != nil {
 		return err
 	}
+
+	diffText := title + "\n\n"
+	if len(propertyDiffs) == 0 {
+		diffText = diffText + "(no differences)\n"
+	}
+	for _, propertyDiff := range propertyDiffs {
+		diffText = diffText + propertyDiff.String() + "\n"
+	}
+	updateStatusf("Structural diff found %d difference(s).", len(propertyDiffs))
+
+	return extprogs.View("Object_Diff", []byte(diffText))
 }
 
 func getSelectedOrCurrent(m *model.Model) []model.Item {
@@ -1199,138 +1634,306 @@ func getSelectedOrCurrent(m *model.Model) []model.Item {
 	return selectedItems
 }
 
-func copyItem(fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.ItemConfig, item model.Item, confirmOverwrite string) (string, error) {
+func copyItem(ctx context.Context, fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.ItemConfig, item model.Item, confirmOverwrite string) (string, error) {
 	logging.LogDebugf("ui/copyItem(.., .., %v, %v, %v, '%s')", fromViewConfig, toViewConfig, item, confirmOverwrite)
 	res := confirmOverwrite
 	var err error
 	switch item.Config.Type {
 	case model.ItemDpFilestore:
-		res, err = copyFilestore(fromRepo, toRepo, fromViewConfig, toViewConfig, item.Name, confirmOverwrite)
+		res, err = copyFilestore(ctx, fromRepo, toRepo, fromViewConfig, toViewConfig, item.Name, confirmOverwrite)
 		if err != nil {
 			return res, err
 		}
 	case model.ItemDirectory:
-		res, err = copyDirs(fromRepo, toRepo, fromViewConfig, toViewConfig, item.Name, confirmOverwrite)
+		res, err = copyDirs(ctx, fromRepo, toRepo, fromViewConfig, toViewConfig, item.Name, confirmOverwrite)
 		if err != nil {
 			return res, err
 		}
 	case model.ItemFile:
 		// If we copy to DataPower and we are in ObjectConfigMode we copy file to object.
 		switch {
-		case toRepo.String() == dp.Repo.String() && dp.Repo.DpViewMode == model.DpObjectMode:
-			res, err = copyFileToObject(item.Config, item.Name, fromRepo, toRepo, fromViewConfig, toViewConfig, confirmOverwrite)
-		case toRepo.String() == dp.Repo.String() && dp.Repo.DpViewMode == model.DpStatusMode:
+		case isDpRepo(toRepo) &&
+			(toViewConfig.Type == model.ItemDpDomain || toViewConfig.Type == model.ItemDpConfiguration) &&
+			strings.HasSuffix(item.Name, ".zip"):
+			err = importFile(ctx, fromRepo, toRepo, fromViewConfig, toViewConfig, item.Name)
+		case isDpRepo(toRepo) && toViewConfig.DpViewMode() == model.DpObjectMode:
+			res, err = copyFileToObject(ctx, item.Config, item.Name, fromRepo, toRepo, fromViewConfig, toViewConfig, confirmOverwrite)
+		case isDpRepo(toRepo) && toViewConfig.DpViewMode() == model.DpStatusMode:
 			err = errs.Errorf("Can't copy to DataPower status.")
 		default:
-			res, err = copyFile(fromRepo, toRepo, fromViewConfig, toViewConfig, item.Name, confirmOverwrite)
+			res, err = copyFile(ctx, fromRepo, toRepo, fromViewConfig, toViewConfig, item.Name, confirmOverwrite)
 		}
 
 		if err != nil {
 			return res, err
 		}
 	case model.ItemDpDomain:
-		err = exportDomain(fromViewConfig, toViewConfig, item.Name)
+		err = exportDomain(ctx, fromRepo, toRepo, fromViewConfig, toViewConfig, item.Name)
 		if err != nil {
 			return res, err
 		}
 	case model.ItemDpConfiguration:
-		err = exportAppliance(item.Config, toViewConfig, item.Name)
+		err = exportAppliance(ctx, fromRepo, toRepo, item.Config, toViewConfig, item.Name)
 		if err != nil {
 			return res, err
 		}
 	case model.ItemDpObject:
-		res, err = copyObjectToFile(item.Config, item.Name, fromRepo, toRepo, fromViewConfig, toViewConfig, confirmOverwrite)
+		// If we copy to DataPower in ObjectConfigMode we copy object to object.
+		if isDpRepo(toRepo) && toViewConfig.DpViewMode() == model.DpObjectMode {
+			res, err = copyObjectToObject(ctx, item.Config, item.Name, fromRepo, toRepo, toViewConfig, confirmOverwrite)
+		} else {
+			res, err = copyObjectToFile(ctx, item.Config, item.Name, fromRepo, toRepo, fromViewConfig, toViewConfig, confirmOverwrite)
+		}
 		if err != nil {
 			return res, err
 		}
 	default:
-		updateStatusf("Item of type '%s' can't be copied/exported.", item.Config.Type.UserFriendlyString())
+		updateStatusCtxf(ctx, "Item of type '%s' can't be copied/exported.", item.Config.Type.UserFriendlyString())
 	}
 
 	logging.LogDebugf("ui/copyItem(), res: '%s'", res)
 	return res, nil
 }
 
-func copyFilestore(fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.ItemConfig, dirName, confirmOverwrite string) (string, error) {
+func copyFilestore(ctx context.Context, fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.ItemConfig, dirName, confirmOverwrite string) (string, error) {
 	dirToName := dirName[0 : len(dirName)-1]
-	return copyDirsOrFilestores(fromRepo, toRepo, fromViewConfig, toViewConfig, dirName, dirToName, confirmOverwrite)
+	return copyDirsOrFilestores(ctx, fromRepo, toRepo, fromViewConfig, toViewConfig, dirName, dirToName, confirmOverwrite)
+}
+
+func copyDirs(ctx context.Context, fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.ItemConfig, dirName, confirmOverwrite string) (string, error) {
+	return copyDirsOrFilestores(ctx, fromRepo, toRepo, fromViewConfig, toViewConfig, dirName, dirName, confirmOverwrite)
 }
 
-func copyDirs(fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.ItemConfig, dirName, confirmOverwrite string) (string, error) {
-	return copyDirsOrFilestores(fromRepo, toRepo, fromViewConfig, toViewConfig, dirName, dirName, confirmOverwrite)
+// copyWorkers is the number of files copied in parallel when directory tree is
+// copied (less than the number of connections kept open to each appliance).
+const copyWorkers = 4
+
+// copyFileTask is a file found in the copied directory tree.
+type copyFileTask struct {
+	fromViewConfig *model.ItemConfig
+	toViewConfig   *model.ItemConfig
+	fileName       string
+	size           int64
 }
 
-func copyDirsOrFilestores(fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.ItemConfig, dirFromName, dirToName, confirmOverwrite string) (string, error) {
+// copyDirsOrFilestores copies directory tree - directories are created and
+// overwrite of existing files is confirmed while walking the tree and after
+// that files are copied in parallel.
+func copyDirsOrFilestores(ctx context.Context, fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.ItemConfig, dirFromName, dirToName, confirmOverwrite string) (string, error) {
 	logging.LogDebugf("ui/copyDirsOrFilestores(.., .., %v, %v, '%s', '%s', '%s')", fromViewConfig, toViewConfig, dirFromName, dirToName, confirmOverwrite)
-	toParentPath := toViewConfig.Path
-	toFileType, err := toRepo.GetFileType(toViewConfig, toParentPath, dirToName)
+	toFileType, err := toRepo.GetFileType(ctx, toViewConfig, toViewConfig.Path, dirToName)
 	if err != nil {
 		return confirmOverwrite, err
 	}
-	toPath := toRepo.GetFilePath(toParentPath, dirToName)
+
+	res, tasks, err := walkCopyTree(ctx, fromRepo, toRepo, fromViewConfig, toViewConfig,
+		dirFromName, dirToName, toFileType, confirmOverwrite, nil)
+	if err != nil {
+		return res, err
+	}
+
+	return res, copyFiles(ctx, fromRepo, toRepo, tasks)
+}
+
+// walkCopyTree creates destination directory (if it doesn't exist) and adds
+// files of the source directory tree which should be copied to tasks. Type of
+// the destination files is taken from the destination directory listing.
+func walkCopyTree(ctx context.Context, fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.ItemConfig,
+	dirFromName, dirToName string, toFileType model.ItemType, confirmOverwrite string, tasks []copyFileTask) (string, []copyFileTask, error) {
+	logging.LogDebugf("ui/walkCopyTree(.., .., %v, %v, '%s', '%s', %v, '%s')",
+		fromViewConfig, toViewConfig, dirFromName, dirToName, toFileType, confirmOverwrite)
+	res := confirmOverwrite
+	toPath := toRepo.GetFilePath(toViewConfig.Path, dirToName)
 	switch toFileType {
 	case model.ItemNone:
-		_, err = toRepo.CreateDir(toViewConfig, toParentPath, dirToName)
+		_, err := toRepo.CreateDir(ctx, toViewConfig, toViewConfig.Path, dirToName)
 		if err != nil {
-			logging.LogDebugf("ui/copyDirsOrFilestores() - err: %v", err)
-			return confirmOverwrite, err
+			logging.LogDebugf("ui/walkCopyTree() - err: %v", err)
+			return res, tasks, err
 		}
-		updateStatusf("Directory '%s' created.", toPath)
+		updateStatusCtxf(ctx, "Directory '%s' created.", toPath)
 	case model.ItemDirectory:
-		updateStatusf("Directory '%s' already exists.", toPath)
+		updateStatusCtxf(ctx, "Directory '%s' already exists.", toPath)
 	case model.ItemDpFilestore:
-		updateStatusf("DataPower filestore '%s' already exists.", toPath)
+		updateStatusCtxf(ctx, "DataPower filestore '%s' already exists.", toPath)
 	default:
 		errMsg := fmt.Sprintf("Non dir '%s' exists (%v), can't create dir.", toPath, toFileType)
-		logging.LogDebugf("ui/copyDirsOrFilestores() - %s", errMsg)
-		return confirmOverwrite, errs.Error(errMsg)
+		logging.LogDebugf("ui/walkCopyTree() - %s", errMsg)
+		return res, tasks, errs.Error(errMsg)
 	}
 
-	fromViewConfigDir := model.ItemConfig{
+	fromViewConfigDir := &model.ItemConfig{
 		Parent:      fromViewConfig,
 		Type:        model.ItemDirectory,
 		Path:        fromRepo.GetFilePath(fromViewConfig.Path, dirFromName),
 		DpAppliance: fromViewConfig.DpAppliance,
 		DpDomain:    fromViewConfig.DpDomain,
 		DpFilestore: fromViewConfig.DpFilestore}
-	items, err := fromRepo.GetList(&fromViewConfigDir)
+	items, err := fromRepo.GetList(ctx, fromViewConfigDir)
 	if err != nil {
-		return confirmOverwrite, err
+		return res, tasks, err
+	}
+
+	toViewConfigDir := &model.ItemConfig{Parent: toViewConfig,
+		Type:        toFileType,
+		Path:        toPath,
+		DpAppliance: toViewConfig.DpAppliance,
+		DpDomain:    toViewConfig.DpDomain,
+		DpFilestore: toViewConfig.DpFilestore}
+	toFileTypes := make(map[string]model.ItemType)
+	if toFileType == model.ItemNone {
+		toViewConfigDir.Type = model.ItemDirectory
+	} else {
+		toItems, err := toRepo.GetList(ctx, toViewConfigDir)
+		if err != nil {
+			return res, tasks, err
+		}
+		for _, toItem := range toItems {
+			if toItem.Name != ".." {
+				toFileTypes[toItem.Name] = toItem.Config.Type
+			}
+		}
 	}
 
-	showProgressDialog("Copying files from/to DataPower...")
-	defer hideProgressDialog()
 	for _, item := range items {
-		if item.Name != ".." {
-			childToType, err := toRepo.GetFileType(toViewConfig, toViewConfig.Path, dirToName)
-			if err != nil {
-				return confirmOverwrite, err
-			}
-			toViewConfigDir := model.ItemConfig{Parent: toViewConfig,
-				Type:        childToType,
-				Path:        toRepo.GetFilePath(toViewConfig.Path, dirToName),
-				DpAppliance: toViewConfig.DpAppliance,
-				DpDomain:    toViewConfig.DpDomain,
-				DpFilestore: toViewConfig.DpFilestore}
-			confirmOverwrite, err = copyItem(fromRepo, toRepo, &fromViewConfigDir, &toViewConfigDir, item, confirmOverwrite)
-			if err != nil {
-				return confirmOverwrite, err
+		if item.Name == ".." {
+			continue
+		}
+		childToType, ok := toFileTypes[item.Name]
+		if !ok {
+			childToType = model.ItemNone
+		}
+		switch item.Config.Type {
+		case model.ItemDirectory:
+			res, tasks, err = walkCopyTree(ctx, fromRepo, toRepo, fromViewConfigDir, toViewConfigDir,
+				item.Name, item.Name, childToType, res, tasks)
+			if err != nil {
+				return res, tasks, err
+			}
+		case model.ItemFile:
+			switch childToType {
+			case model.ItemNone:
+			case model.ItemFile:
+				if res != "ya" && res != "na" {
+					dialogResult := askUserInput(
+						fmt.Sprintf("Confirm overwrite of file '%s' at '%s' (y/ya/n/na): ",
+							item.Name, toViewConfigDir.Path), "", []string{"y", "ya", "n", "na"}, false)
+					if dialogResult.dialogSubmitted {
+						res = dialogResult.inputAnswer
+					}
+				}
+				if res != "y" && res != "ya" {
+					updateStatusCtxf(ctx, "Canceled overwrite of '%s'", item.Name)
+					continue
+				}
+			default:
+				updateStatusCtxf(ctx, "ERROR: File '%s' could not be copied from '%s' to '%s' - directory with same name exists.",
+					item.Name, fromViewConfigDir.Path, toViewConfigDir.Path)
+				continue
+			}
+			size, _ := strconv.ParseInt(item.Size, 10, 64)
+			tasks = append(tasks, copyFileTask{fromViewConfig: fromViewConfigDir,
+				toViewConfig: toViewConfigDir, fileName: item.Name, size: size})
+		default:
+			updateStatusCtxf(ctx, "Item of type '%s' can't be copied/exported.", item.Config.Type.UserFriendlyString())
+		}
+	}
+
+	return res, tasks, nil
+}
+
+// copyFiles copies files using copyWorkers goroutines and shows number of
+// copied files and bytes (file sizes from the directory listing) as progress.
+// DataPower repo isn't safe for concurrent use so each worker uses its own
+// copy of the repo. Remaining files are not copied after the first error.
+func copyFiles(ctx context.Context, fromRepo, toRepo repo.Repo, tasks []copyFileTask) error {
+	logging.LogDebugf("ui/copyFiles(.., .., %d)", len(tasks))
+	if len(tasks) == 0 {
+		return nil
+	}
+	var totalBytes int64
+	for _, task := range tasks {
+		totalBytes += task.size
+	}
+
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var mutex sync.Mutex
+	var filesDone int
+	var bytesDone int64
+	var notCopied []copyFileTask
+	var copyErr error
+	updateProgressf(ctx, "Copied 0/%d files (0/%d bytes)...", len(tasks), totalBytes)
+
+	taskCh := make(chan copyFileTask)
+	var wg sync.WaitGroup
+	workers := copyWorkers
+	if len(tasks) < workers {
+		workers = len(tasks)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(fromRepo, toRepo repo.Repo) {
+			defer wg.Done()
+			for task := range taskCh {
+				fBytes, err := fromRepo.GetFile(workerCtx, task.fromViewConfig, task.fileName)
+				copySuccess := false
+				if err == nil {
+					copySuccess, err = toRepo.UpdateFile(workerCtx, task.toViewConfig, task.fileName, fBytes)
+				}
+				mutex.Lock()
+				switch {
+				case err != nil:
+					if copyErr == nil {
+						copyErr = err
+						cancel()
+					}
+				case !copySuccess:
+					notCopied = append(notCopied, task)
+				default:
+					filesDone++
+					bytesDone += task.size
+					updateProgressf(ctx, "Copied %d/%d files (%d/%d bytes)...",
+						filesDone, len(tasks), bytesDone, totalBytes)
+				}
+				mutex.Unlock()
 			}
+		}(dp.CopyRepo(fromRepo), dp.CopyRepo(toRepo))
+	}
+
+feed:
+	for _, task := range tasks {
+		select {
+		case taskCh <- task:
+		case <-workerCtx.Done():
+			break feed
 		}
 	}
+	close(taskCh)
+	wg.Wait()
 
-	return confirmOverwrite, err
+	for _, task := range notCopied {
+		updateStatusCtxf(ctx, "ERROR: File '%s' not copied from '%s' to '%s'.",
+			task.fileName, task.fromViewConfig.Path, task.toViewConfig.Path)
+	}
+	if copyErr != nil {
+		return copyErr
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	updateStatusCtxf(ctx, "Copied %d files (%d bytes) from %s to %s.", filesDone, bytesDone, fromRepo, toRepo)
+
+	return nil
 }
 
-func copyFile(fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.ItemConfig, fileName, confirmOverwrite string) (string, error) {
+func copyFile(ctx context.Context, fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.ItemConfig, fileName, confirmOverwrite string) (string, error) {
 	logging.LogDebugf("ui/copyFile(.., .., %v, %v, '%s', '%s')",
 		fromViewConfig, toViewConfig, fileName, confirmOverwrite)
-	showProgressDialog("Copying file(s) from DataPower...")
-	defer hideProgressDialog()
-	updateProgressDialogMessagef("Preparing to copy file '%s' from %s to %s...",
+	updateProgressf(ctx, "Preparing to copy file '%s' from %s to %s...",
 		fileName, fromRepo, toRepo)
 	res := confirmOverwrite
-	targetFileType, err := toRepo.GetFileType(toViewConfig, toViewConfig.Path, fileName)
+	targetFileType, err := toRepo.GetFileType(ctx, toViewConfig, toViewConfig.Path, fileName)
 	if err != nil {
 		return res, err
 	}
@@ -1340,7 +1943,7 @@ func copyFile(fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.It
 		copyFileToDirStatus :=
 			fmt.Sprintf("ERROR: File '%s' could not be copied from '%s' to '%s' - directory with same name exists.",
 				fileName, fromViewConfig.Path, toViewConfig.Path)
-		updateStatus(copyFileToDirStatus)
+		updateStatusCtx(ctx, copyFileToDirStatus)
 	case model.ItemFile:
 		if res != "ya" && res != "na" {
 			logging.LogDebugf("ui/copyFile(), confirm overwrite: '%s'", res)
@@ -1357,11 +1960,11 @@ func copyFile(fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.It
 	if res == "y" || res == "ya" {
 		switch targetFileType {
 		case model.ItemFile, model.ItemNone:
-			fBytes, err := fromRepo.GetFile(fromViewConfig, fileName)
+			fBytes, err := fromRepo.GetFile(ctx, fromViewConfig, fileName)
 			if err != nil {
 				return res, err
 			}
-			copySuccess, err := toRepo.UpdateFile(toViewConfig, fileName, fBytes)
+			copySuccess, err := toRepo.UpdateFile(ctx, toViewConfig, fileName, fBytes)
 			if err != nil {
 				return res, err
 			}
@@ -1369,22 +1972,22 @@ func copyFile(fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.It
 			if copySuccess {
 				copySuccessStatus := fmt.Sprintf("File '%s' copied from '%s' to '%s'.",
 					fileName, fromViewConfig.Path, toViewConfig.Path)
-				updateStatus(copySuccessStatus)
+				updateStatusCtx(ctx, copySuccessStatus)
 			} else {
 				copyErrStatus := fmt.Sprintf("ERROR: File '%s' not copied from '%s' to '%s'.",
 					fileName, fromViewConfig.Path, toViewConfig.Path)
-				updateStatus(copyErrStatus)
+				updateStatusCtx(ctx, copyErrStatus)
 			}
 		}
 	} else {
-		updateStatusf("Canceled overwrite of '%s'", fileName)
+		updateStatusCtxf(ctx, "Canceled overwrite of '%s'", fileName)
 	}
 
 	logging.LogDebugf("ui/copyFile(), res: '%s'", res)
 	return res, nil
 }
 
-func copyObjectToFile(itemConfig *model.ItemConfig, itemName string,
+func copyObjectToFile(ctx context.Context, itemConfig *model.ItemConfig, itemName string,
 	fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.ItemConfig,
 	confirmOverwrite string) (string, error) {
 	logging.LogDebugf("ui/copyObjectToFile(%v, '%s', .., .., %v, %v, '%s')",
@@ -1392,22 +1995,16 @@ func copyObjectToFile(itemConfig *model.ItemConfig, itemName string,
 	res := confirmOverwrite
 
 	objectName := itemName
-	var objectFileSuffix string
-
-	switch dp.Repo.GetManagementInterface() {
-	case config.DpInterfaceRest:
-		objectFileSuffix = ".json"
-	case config.DpInterfaceSoma:
-		objectFileSuffix = ".xml"
-	default:
-		logging.LogDebug("ui/copyObjectToFile(), using neither REST neither SOMA.")
-		return "", errs.Error("DataPower management interface not set.")
+	fromDpRepo := fromRepo.(dpRepository)
+	objectFileSuffix, err := dpObjectFileSuffix(fromDpRepo)
+	if err != nil {
+		return "", err
 	}
 	objectFileName := itemName + objectFileSuffix
 	logging.LogDebugf("ui/copyObjectToFile(), objectName: '%s', objectFileName: '%s'.",
 		objectName, objectFileName)
 
-	targetFileType, err := toRepo.GetFileType(toViewConfig, toViewConfig.Path, objectFileName)
+	targetFileType, err := toRepo.GetFileType(ctx, toViewConfig, toViewConfig.Path, objectFileName)
 	if err != nil {
 		return res, err
 	}
@@ -1417,7 +2014,7 @@ func copyObjectToFile(itemConfig *model.ItemConfig, itemName string,
 		copyFileToDirStatus :=
 			fmt.Sprintf("ERROR: Object '%s' could not be copied from '%s' to '%s' - directory with same name exists.",
 				objectFileName, fromViewConfig.Path, toViewConfig.Path)
-		updateStatus(copyFileToDirStatus)
+		updateStatusCtx(ctx, copyFileToDirStatus)
 	case model.ItemFile:
 		if res != "ya" && res != "na" {
 			logging.LogDebugf("ui/copyObjectToFile(), confirm overwrite: '%s'", res)
@@ -1434,11 +2031,11 @@ func copyObjectToFile(itemConfig *model.ItemConfig, itemName string,
 	if res == "y" || res == "ya" {
 		switch targetFileType {
 		case model.ItemFile, model.ItemNone:
-			fBytes, err := dp.Repo.GetObject(itemConfig.DpDomain, itemConfig.Path, objectName, false)
+			fBytes, err := fromDpRepo.GetObject(ctx, itemConfig.DpDomain, itemConfig.Path, objectName, false)
 			if err != nil {
 				return res, err
 			}
-			copySuccess, err := toRepo.UpdateFile(toViewConfig, objectFileName, fBytes)
+			copySuccess, err := toRepo.UpdateFile(ctx, toViewConfig, objectFileName, fBytes)
 			if err != nil {
 				return res, err
 			}
@@ -1446,38 +2043,32 @@ func copyObjectToFile(itemConfig *model.ItemConfig, itemName string,
 			if copySuccess {
 				copySuccessStatus := fmt.Sprintf("File '%s' copied from '%s' to '%s'.",
 					objectFileName, fromViewConfig.Path, toViewConfig.Path)
-				updateStatus(copySuccessStatus)
+				updateStatusCtx(ctx, copySuccessStatus)
 			} else {
 				copyErrStatus := fmt.Sprintf("ERROR: File '%s' not copied from '%s' to '%s'.",
 					objectFileName, fromViewConfig.Path, toViewConfig.Path)
-				updateStatus(copyErrStatus)
+				updateStatusCtx(ctx, copyErrStatus)
 			}
 		}
 	} else {
-		updateStatusf("Canceled overwrite of '%s'", objectFileName)
+		updateStatusCtxf(ctx, "Canceled overwrite of '%s'", objectFileName)
 	}
 
 	logging.LogDebugf("ui/copyObjectToFile(), res: '%s'", res)
 	return res, nil
 }
 
-func copyFileToObject(itemConfig *model.ItemConfig, itemName string,
+func copyFileToObject(ctx context.Context, itemConfig *model.ItemConfig, itemName string,
 	fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.ItemConfig,
 	confirmOverwrite string) (string, error) {
 	logging.LogDebugf("ui/copyFileToObject(%v, '%s', .., .., %v, %v, '%s')",
 		itemConfig, itemName, fromViewConfig, toViewConfig, confirmOverwrite)
 	res := confirmOverwrite
 
-	var objectFileSuffix string
-
-	switch dp.Repo.GetManagementInterface() {
-	case config.DpInterfaceRest:
-		objectFileSuffix = ".json"
-	case config.DpInterfaceSoma:
-		objectFileSuffix = ".xml"
-	default:
-		logging.LogDebug("ui/copyFileToObject(), using neither REST neither SOMA.")
-		return "", errs.Error("DataPower management interface not set.")
+	toDpRepo := toRepo.(dpRepository)
+	objectFileSuffix, err := dpObjectFileSuffix(toDpRepo)
+	if err != nil {
+		return "", err
 	}
 
 	if !strings.HasSuffix(itemName, objectFileSuffix) {
@@ -1486,15 +2077,15 @@ func copyFileToObject(itemConfig *model.ItemConfig, itemName string,
 	}
 	objectFileName := itemName
 
-	objectBytesLocal, err := localfs.Repo.GetFile(fromViewConfig, objectFileName)
+	objectBytesLocal, err := fromRepo.GetFile(ctx, fromViewConfig, objectFileName)
 	if err != nil {
 		return "", err
 	}
-	objectClassName, objectName, err := dp.Repo.ParseObjectClassAndName(objectBytesLocal)
+	objectClassName, objectName, err := toDpRepo.ParseObjectClassAndName(objectBytesLocal)
 	if err != nil {
 		return "", err
 	}
-	objectBytesDp, err := dp.Repo.GetObject(
+	objectBytesDp, err := toDpRepo.GetObject(ctx,
 		toViewConfig.DpDomain, objectClassName, objectName, false)
 	if err != nil {
 		return "", err
@@ -1528,72 +2119,266 @@ func copyFileToObject(itemConfig *model.ItemConfig, itemName string,
 		targetItemType, existingObject, res)
 
 	if res == "y" || res == "ya" {
-		err = dp.Repo.SetObject(
+		err = toDpRepo.SetObject(ctx,
 			toViewConfig.DpDomain, objectClassName, objectName, objectBytesLocal, existingObject)
 		if err != nil {
 			return res, err
 		}
 		logging.LogDebugf("ui/copyFileToObject() Object '%s' of class '%s' copied from file '%s' to the appliance.",
 			objectName, objectClassName, objectFileName)
-		updateStatusf("Object '%s' of class '%s' copied from file '%s' to the appliance.",
+		updateStatusCtxf(ctx, "Object '%s' of class '%s' copied from file '%s' to the appliance.",
 			objectName, objectClassName, objectFileName)
 	} else {
-		updateStatusf("Canceled overwrite of '%s'", objectName)
+		updateStatusCtxf(ctx, "Canceled overwrite of '%s'", objectName)
 	}
 
 	logging.LogDebugf("ui/copyFileToObject(), res: '%s'", res)
 	return res, nil
 }
 
-func exportDomain(fromViewConfig, toViewConfig *model.ItemConfig, domainName string) error {
+// copyObjectToObject copies DataPower object to the (other) DataPower domain
+// or appliance shown in object mode.
+func copyObjectToObject(ctx context.Context, itemConfig *model.ItemConfig, objectName string,
+	fromRepo, toRepo repo.Repo, toViewConfig *model.ItemConfig,
+	confirmOverwrite string) (string, error) {
+	logging.LogDebugf("ui/copyObjectToObject(%v, '%s', .., .., %v, '%s')",
+		itemConfig, objectName, toViewConfig, confirmOverwrite)
+	res := confirmOverwrite
+
+	fromDpRepo := fromRepo.(dpRepository)
+	toDpRepo := toRepo.(dpRepository)
+	if fromDpRepo.GetManagementInterface() != toDpRepo.GetManagementInterface() {
+		return res, errs.Errorf("Can't copy object '%s' between appliances using different management interfaces (%s, %s).",
+			objectName, fromDpRepo.GetManagementInterface(), toDpRepo.GetManagementInterface())
+	}
+	objectClassName := itemConfig.Path
+	if itemConfig.DpAppliance == toViewConfig.DpAppliance && itemConfig.DpDomain == toViewConfig.DpDomain {
+		return res, errs.Errorf("Can't copy object '%s' of class '%s' to itself.", objectName, objectClassName)
+	}
+
+	objectBytes, err := fromDpRepo.GetObject(ctx, itemConfig.DpDomain, objectClassName, objectName, false)
+	if err != nil {
+		return res, err
+	}
+	objectBytesTo, err := toDpRepo.GetObject(ctx, toViewConfig.DpDomain, objectClassName, objectName, false)
+	if err != nil {
+		return res, err
+	}
+
+	existingObject := objectBytesTo != nil
+	switch {
+	case !existingObject:
+		res = "y"
+	case res != "ya" && res != "na":
+		logging.LogDebugf("ui/copyObjectToObject(), confirm overwrite: '%s'", res)
+		dialogResult := askUserInput(
+			fmt.Sprintf("Confirm overwrite of object '%s' of class '%s' in domain '%s' (y/ya/n/na): ",
+				objectName, objectClassName, toViewConfig.DpDomain), "", []string{"y", "ya", "n", "na"}, false)
+		if dialogResult.dialogSubmitted {
+			res = dialogResult.inputAnswer
+		}
+	}
+
+	if res == "y" || res == "ya" {
+		err = toDpRepo.SetObject(ctx,
+			toViewConfig.DpDomain, objectClassName, objectName, objectBytes, existingObject)
+		if err != nil {
+			return res, err
+		}
+		updateStatusCtxf(ctx, "Object '%s' of class '%s' copied from '%s' (%s) to '%s' (%s).",
+			objectName, objectClassName, itemConfig.DpAppliance, itemConfig.DpDomain,
+			toViewConfig.DpAppliance, toViewConfig.DpDomain)
+	} else {
+		updateStatusCtxf(ctx, "Canceled overwrite of '%s'", objectName)
+	}
+
+	logging.LogDebugf("ui/copyObjectToObject(), res: '%s'", res)
+	return res, nil
+}
+
+// dpObjectFileSuffix returns suffix of the file containing DataPower object
+// configuration for the appliance of given DataPower repository.
+func dpObjectFileSuffix(dpRepo dpRepository) (string, error) {
+	switch dpRepo.GetManagementInterface() {
+	case config.DpInterfaceRest:
+		return ".json", nil
+	case config.DpInterfaceSoma:
+		return ".xml", nil
+	default:
+		logging.LogDebug("ui/dpObjectFileSuffix(), using neither REST neither SOMA.")
+		return "", errs.Error("DataPower management interface not set.")
+	}
+}
+
+func exportDomain(ctx context.Context, fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.ItemConfig, domainName string) error {
 	logging.LogDebugf("ui/exportDomain(%v, %v, '%s')", fromViewConfig, toViewConfig, domainName)
+	fromDpRepo, ok := fromRepo.(dpRepository)
+	if !ok {
+		return errs.Errorf("Can't export domain '%s' from %s.", domainName, fromRepo)
+	}
 	exportFileName := fromViewConfig.DpAppliance + "_" + domainName + "_" + time.Now().Format("20060102150405") + ".zip"
 	logging.LogDebugf("ui/exportDomain() exportFileName: '%s'", exportFileName)
-	showProgressDialogf("Exporting domain '%s'...", domainName)
-	exportFileBytes, err := dp.Repo.ExportDomain(domainName, exportFileName)
-	hideProgressDialog()
+	updateProgressf(ctx, "Exporting domain '%s'...", domainName)
+	exportFileBytes, err := fromDpRepo.ExportDomain(ctx, domainName, exportFileName)
 	if err != nil {
 		return err
 	}
-	_, err = localfs.Repo.UpdateFile(toViewConfig, exportFileName, exportFileBytes)
+	_, err = toRepo.UpdateFile(ctx, toViewConfig, exportFileName, exportFileBytes)
 	if err == nil {
-		updateStatusf("Domain '%s' exported to file '%s' on path '%s'.",
+		updateStatusCtxf(ctx, "Domain '%s' exported to file '%s' on path '%s'.",
 			domainName, exportFileName, toViewConfig.Path)
 	}
 	return err
 }
 
-func exportAppliance(dpApplianceConfig, toViewConfig *model.ItemConfig, applianceConfigName string) error {
+func exportAppliance(ctx context.Context, fromRepo, toRepo repo.Repo, dpApplianceConfig, toViewConfig *model.ItemConfig, applianceConfigName string) error {
 	logging.LogDebugf("ui/exportAppliance(%v, %v)", dpApplianceConfig, toViewConfig)
+	fromDpRepo, ok := fromRepo.(dpRepository)
+	if !ok {
+		return errs.Errorf("Can't export appliance '%s' from %s.", applianceConfigName, fromRepo)
+	}
 	applianceName := dpApplianceConfig.DpAppliance
 	exportFileName := applianceName + "_" + time.Now().Format("20060102150405") + ".zip"
 	logging.LogDebugf("ui/exportAppliance() exportFileName: '%s'", exportFileName)
 
-	applicanceConfig := config.Conf.DataPowerAppliances[applianceName]
-	dpTransientPassword := config.DpTransientPasswordMap[applianceName]
-	logging.LogDebugf("ui/exportAppliance(), applicanceConfig: '%s'", applicanceConfig)
-	if applicanceConfig.Password == "" && dpTransientPassword == "" {
-		logging.LogDebugf("ui/exportAppliance(), before asking password.")
-		dialogResult := askUserInput("Please enter DataPower password: ", "", nil, true)
-		logging.LogDebugf("ui/exportAppliance(), after asking password (%#v).", dialogResult)
-		if dialogResult.dialogCanceled || dialogResult.inputAnswer == "" {
+	updateProgressf(ctx, "Exporting DataPower appliance '%s'...", applianceName)
+	exportFileBytes, err := fromDpRepo.ExportAppliance(ctx, applianceConfigName, exportFileName)
+	if err != nil {
+		return err
+	}
+	_, err = toRepo.UpdateFile(ctx, toViewConfig, exportFileName, exportFileBytes)
+	if err == nil {
+		updateStatusCtxf(ctx, "Appliance '%s' exported to file '%s' on path '%s'.",
+			applianceName, exportFileName, toViewConfig.Path)
+	}
+	return err
+}
+
+func importFile(ctx context.Context, fromRepo, toRepo repo.Repo, fromViewConfig, toViewConfig *model.ItemConfig, fileName string) error {
+	logging.LogDebugf("ui/importFile(%v, %v, '%s')", fromViewConfig, toViewConfig, fileName)
+	toDpRepo := toRepo.(dpRepository)
+
+	var importTarget string
+	switch toViewConfig.Type {
+	case model.ItemDpDomain:
+		importTarget = fmt.Sprintf("domain '%s'", toViewConfig.DpDomain)
+	case model.ItemDpConfiguration:
+		importTarget = fmt.Sprintf("appliance '%s'", toViewConfig.DpAppliance)
+		if !askDpPasswordIfNotSet(toViewConfig.DpAppliance) {
 			return nil
 		}
-		setCurrentDpPlainPassword(dialogResult.inputAnswer)
+	default:
+		return errs.Errorf("Can't import file '%s' to %s.", fileName, toViewConfig.Type.UserFriendlyString())
+	}
+
+	dialogResult := askUserInput(
+		fmt.Sprintf("Import file '%s' to %s, overwrite existing files (y/n): ",
+			fileName, importTarget), "", []string{"y", "n"}, false)
+	if dialogResult.dialogCanceled {
+		updateStatusCtxf(ctx, "Canceled import of '%s'.", fileName)
+		return nil
+	}
+	overwriteFiles := dialogResult.inputAnswer == "y"
+	dialogResult = askUserInput(
+		fmt.Sprintf("Import file '%s' to %s, overwrite existing objects (y/n): ",
+			fileName, importTarget), "", []string{"y", "n"}, false)
+	if dialogResult.dialogCanceled {
+		updateStatusCtxf(ctx, "Canceled import of '%s'.", fileName)
+		return nil
 	}
+	overwriteObjects := dialogResult.inputAnswer == "y"
 
-	showProgressDialogf("Exporting DataPower appliance '%s'...", applianceName)
-	exportFileBytes, err := dp.Repo.ExportAppliance(applianceConfigName, exportFileName)
+	importFileBytes, err := fromRepo.GetFile(ctx, fromViewConfig, fileName)
+	if err != nil {
+		return err
+	}
+
+	runImport := func(ctx context.Context, dryRun bool) ([]dp.ImportResult, error) {
+		if toViewConfig.Type == model.ItemDpDomain {
+			return toDpRepo.ImportDomain(ctx, toViewConfig.DpDomain, importFileBytes,
+				overwriteFiles, overwriteObjects, dryRun)
+		}
+		return toDpRepo.ImportAppliance(ctx, toViewConfig.DpAppliance, importFileBytes,
+			overwriteFiles, overwriteObjects, dryRun)
+	}
+
+	ctx = showProgressDialogf("Preparing import preview of file '%s' to %s...", fileName, importTarget)
+	importResults, err := runImport(ctx, true)
 	hideProgressDialog()
 	if err != nil {
 		return err
 	}
-	_, err = localfs.Repo.UpdateFile(toViewConfig, exportFileName, exportFileBytes)
-	if err == nil {
-		updateStatusf("Appliance '%s' exported to file '%s' on path '%s'.",
-			applianceName, exportFileName, toViewConfig.Path)
+	if !confirmImportPreview(
+		fmt.Sprintf("Import preview of file '%s' to %s (Enter - import, Esc - abort):",
+			fileName, importTarget), importResults) {
+		updateStatusCtxf(ctx, "Canceled import of '%s'.", fileName)
+		return nil
+	}
+
+	ctx = showProgressDialogf("Importing file '%s' to %s...", fileName, importTarget)
+	importResults, err = runImport(ctx, false)
+	hideProgressDialog()
+	if err != nil {
+		return err
+	}
+	updateStatusCtxf(ctx, "File '%s' imported to %s (%d objects/files).",
+		fileName, importTarget, len(importResults))
+
+	importResultsText := fmt.Sprintf("Import of file '%s' to %s\n"+
+		"(overwrite files: %t, overwrite objects: %t)\n\n",
+		fileName, importTarget, overwriteFiles, overwriteObjects)
+	for _, importResult := range importResults {
+		importResultsText = importResultsText + importResult.String() + "\n"
+	}
+
+	return extprogs.View("Import_Results", []byte(importResultsText))
+}
+
+// confirmImportPreview shows results of dry-run import in the list selection
+// dialog and returns true if user confirms the real import.
+func confirmImportPreview(message string, importResults []dp.ImportResult) bool {
+	logging.LogDebugf("ui/confirmImportPreview('%s', %v)", message, importResults)
+	// Progress dialog shouldn't hide our selection dialog while it is shown.
+	setWaitUserInput(true)
+	defer setWaitUserInput(false)
+
+	previewList := make([]string, len(importResults))
+	for idx, importResult := range importResults {
+		previewList[idx] = importResult.String()
+	}
+	if len(previewList) == 0 {
+		previewList = append(previewList, "(no objects or files to import)")
+	}
+	dialogSession := listSelectionDialogSessionInfo{message: message,
+		list: previewList}
+	runListSelectionDialog(&dialogSession)
+
+	return dialogSession.dialogSubmitted
+}
+
+// runListSelectionDialog shows list selection dialog and processes user's
+// input until selection is submitted or canceled.
+func runListSelectionDialog(dialogSession *listSelectionDialogSessionInfo) {
+	logging.LogDebugf("ui/runListSelectionDialog('%s')", dialogSession)
+loop:
+	for {
+		updateViewEvent := events.UpdateViewEvent{
+			Type:                     events.UpdateViewShowListSelectionDialog,
+			ListSelectionMessage:     dialogSession.message,
+			ListSelectionList:        dialogSession.list,
+			ListSelectionSelectedIdx: dialogSession.selectionIdx}
+
+		out.DrawEvent(updateViewEvent)
+		event := out.Screen.PollEvent()
+		switch event := event.(type) {
+		case *tcell.EventKey:
+			processSelectListDialogInput(dialogSession, event)
+		}
+
+		if dialogSession.dialogCanceled || dialogSession.dialogSubmitted {
+			break loop
+		}
 	}
-	return err
 }
 
 func createEmptyFile(m *model.Model) error {
@@ -1606,14 +2391,14 @@ func createEmptyFile(m *model.Model) error {
 		if dialogResult.dialogSubmitted {
 			fileName := dialogResult.inputAnswer
 			r := repos[side]
-			targetFileType, err := r.GetFileType(viewConfig, viewConfig.Path, fileName)
+			targetFileType, err := r.GetFileType(progressContext(), viewConfig, viewConfig.Path, fileName)
 			if err != nil {
 				return err
 			}
 			if targetFileType != model.ItemNone {
 				return errs.Errorf("File with name '%s' already exists at '%s'.", fileName, viewConfig.Path)
 			}
-			_, err = r.UpdateFile(viewConfig, fileName, make([]byte, 0))
+			_, err = r.UpdateFile(progressContext(), viewConfig, fileName, make([]byte, 0))
 			if err != nil {
 				return errs.Errorf("Can't create file with name '%s' - '%v'.", fileName, err)
 			}
@@ -1622,8 +2407,12 @@ func createEmptyFile(m *model.Model) error {
 			return showItem(side, viewConfig, ".")
 		}
 		updateStatus("Creation of new file canceled.")
+	case model.ItemDpObjectClassList, model.ItemDpObjectClass:
+		if isDpSide(side) {
+			return createDpObject(m)
+		}
 	case model.ItemNone:
-		if side == model.Left {
+		if isDpSide(side) {
 			dialogResult := askUserInput("Enter DataPower configuration name to create: ", "", nil, false)
 			if dialogResult.dialogSubmitted {
 				confName := dialogResult.inputAnswer
@@ -1652,6 +2441,79 @@ func createEmptyFile(m *model.Model) error {
 	return nil
 }
 
+func createDpObject(m *model.Model) error {
+	logging.LogDebugf("ui/createDpObject()")
+	side := m.CurrSide()
+	viewConfig := m.ViewConfig(side)
+	dpRepo := dp.Repos[side]
+
+	var objectClass string
+	switch viewConfig.Type {
+	case model.ItemDpObjectClass:
+		objectClass = viewConfig.Path
+	default:
+		ctx := showProgressDialog("Fetching DataPower object classes...")
+		classNames, err := dpRepo.ListObjectClassNames(ctx)
+		hideProgressDialog()
+		if err != nil {
+			return err
+		}
+		setWaitUserInput(true)
+		dialogSession := listSelectionDialogSessionInfo{
+			message: "Select a class of the DataPower object to create:",
+			list:    classNames}
+		runListSelectionDialog(&dialogSession)
+		setWaitUserInput(false)
+		if !dialogSession.dialogSubmitted {
+			updateStatus("Creation of new DataPower object canceled.")
+			return nil
+		}
+		objectClass = classNames[dialogSession.selectionIdx]
+	}
+
+	dialogResult := askUserInput(
+		fmt.Sprintf("Enter name of the DataPower object of class '%s' to create: ", objectClass),
+		"", nil, false)
+	if !dialogResult.dialogSubmitted || dialogResult.inputAnswer == "" {
+		updateStatus("Creation of new DataPower object canceled.")
+		return nil
+	}
+	objectName := dialogResult.inputAnswer
+
+	existingObject, err := dpRepo.GetObject(progressContext(), viewConfig.DpDomain, objectClass, objectName, false)
+	if err != nil {
+		return err
+	}
+	if existingObject != nil {
+		return errs.Errorf("DataPower object '%s' of class '%s' already exists.", objectName, objectClass)
+	}
+
+	objectSkeleton, err := dpRepo.CreateObjectSkeleton(objectClass, objectName)
+	if err != nil {
+		return err
+	}
+	changed, newObjectContent, err := extprogs.Edit(getObjectTmpName(side, objectName), objectSkeleton)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		updateStatusf("Creation of DataPower object '%s' of class '%s' canceled.", objectName, objectClass)
+		return nil
+	}
+
+	newObjectClass, newObjectName, err := dpRepo.ParseObjectClassAndName(newObjectContent)
+	if err != nil {
+		return err
+	}
+	err = dpRepo.SetObject(progressContext(), viewConfig.DpDomain, newObjectClass, newObjectName, newObjectContent, false)
+	if err != nil {
+		return err
+	}
+
+	updateStatusf("DataPower object '%s' of class '%s' created.", newObjectName, newObjectClass)
+	return showItem(side, viewConfig, ".")
+}
+
 func cloneCurrent(m *model.Model) error {
 	logging.LogDebug("ui/cloneCurrent()")
 	currentItem := m.CurrItem()
@@ -1688,7 +2550,7 @@ func cloneCurrent(m *model.Model) error {
 			objectClass := currentItem.Config.Path
 			objectNameOld := currentItem.Name
 			objectNameNew := newItemName
-			objectConfigToOverwrite, err := dp.Repo.GetObject(dpDomain, objectClass, objectNameNew, false)
+			objectConfigToOverwrite, err := dp.Repos[side].GetObject(progressContext(), dpDomain, objectClass, objectNameNew, false)
 			logging.LogDebugf("ui/cloneCurrent(), err: %v, objectConfigToOverwrite: '%v'", err, objectConfigToOverwrite)
 			if err != nil {
 				return err
@@ -1705,17 +2567,17 @@ func cloneCurrent(m *model.Model) error {
 					return nil
 				}
 			}
-			objectConfigOld, err := dp.Repo.GetObject(dpDomain, objectClass, objectNameOld, false)
+			objectConfigOld, err := dp.Repos[side].GetObject(progressContext(), dpDomain, objectClass, objectNameOld, false)
 			logging.LogDebugf("ui/cloneCurrent(), err: %v, objectConfigOld: '%v'", err, objectConfigOld)
 			if err != nil {
 				return err
 			}
-			objectConfigNew, err := dp.Repo.RenameObject(objectConfigOld, objectNameNew)
+			objectConfigNew, err := dp.Repos[side].RenameObject(objectConfigOld, objectNameNew)
 			logging.LogDebugf("ui/cloneCurrent(), err: %v, objectConfigNew: '%v'", err, objectConfigNew)
 			if err != nil {
 				return err
 			}
-			err = dp.Repo.SetObject(dpDomain, objectClass, objectNameNew, objectConfigNew, existingObject)
+			err = dp.Repos[side].SetObject(progressContext(), dpDomain, objectClass, objectNameNew, objectConfigNew, existingObject)
 			if err != nil {
 				return err
 			}
@@ -1766,14 +2628,14 @@ func createDirectory(m *model.Model) error {
 		side := m.CurrSide()
 		viewConfig := m.ViewConfig(side)
 		r := repos[side]
-		targetFileType, err := r.GetFileType(viewConfig, viewConfig.Path, dirName)
+		targetFileType, err := r.GetFileType(progressContext(), viewConfig, viewConfig.Path, dirName)
 		if err != nil {
 			return err
 		}
 		if targetFileType != model.ItemNone {
 			return errs.Errorf("Directory with name '%s' already exists at '%s'.", dirName, viewConfig.Path)
 		}
-		_, err = r.CreateDir(viewConfig, viewConfig.Path, dirName)
+		_, err = r.CreateDir(progressContext(), viewConfig, viewConfig.Path, dirName)
 		if err != nil {
 			return errs.Errorf("Can't create directory with name '%s' - '%v'.", dirName, err)
 		}
@@ -1792,7 +2654,7 @@ func createDomain(m *model.Model) error {
 		domainName := dialogResult.inputAnswer
 		side := m.CurrSide()
 		viewConfig := m.ViewConfig(side)
-		err := dp.Repo.CreateDomain(domainName)
+		err := dp.Repos[side].CreateDomain(progressContext(), domainName)
 		if err != nil {
 			return err
 		}
@@ -1829,6 +2691,11 @@ func deleteCurrent(m *model.Model) error {
 func deleteItem(repo repo.Repo, parentItemConfig *model.ItemConfig, item model.Item, confirmResponse string) (string, error) {
 	logging.LogDebugf("ui/deleteItem(%v, '%v')", item, confirmResponse)
 
+	if item.Config.Type == model.ItemDpDomain {
+		return confirmResponse, deleteDomain(repo, parentItemConfig, item.Name)
+	}
+	dpSide, _ := dpSideOfRepo(repo)
+
 	var confirmMsg string
 	var successMsg string
 	var errorMsg string
@@ -1896,12 +2763,12 @@ func deleteItem(repo repo.Repo, parentItemConfig *model.ItemConfig, item model.I
 		var err error
 		switch item.Config.Type {
 		case model.ItemDirectory, model.ItemFile, model.ItemDpConfiguration, model.ItemDpObject:
-			res, err = repo.Delete(parentItemConfig, item.Config.Type, parentItemConfig.Path, item.Name)
+			res, err = repo.Delete(progressContext(), parentItemConfig, item.Config.Type, parentItemConfig.Path, item.Name)
 		case model.ItemDpStatusClass:
-			res, err = dp.Repo.FlushCache(
+			res, err = dp.Repos[dpSide].FlushCache(progressContext(),
 				parentItemConfig.DpDomain, item.Name, "", item.Config.Type)
 		case model.ItemDpStatus:
-			res, err = dp.Repo.FlushCache(
+			res, err = dp.Repos[dpSide].FlushCache(progressContext(),
 				parentItemConfig.DpDomain, parentItemConfig.Path, item.Name, item.Config.Type)
 		default:
 			return confirmResponse,
@@ -1923,12 +2790,68 @@ func deleteItem(repo repo.Repo, parentItemConfig *model.ItemConfig, item model.I
 	return confirmResponse, nil
 }
 
+// deleteDomain deletes DataPower domain after user confirms deletion twice
+// (second time by typing the exact domain name) and optionally exports domain
+// to the other panel's view before deletion.
+func deleteDomain(dpRepo repo.Repo, parentItemConfig *model.ItemConfig, domainName string) error {
+	logging.LogDebugf("ui/deleteDomain(%v, '%s')", parentItemConfig, domainName)
+	dpSide, ok := dpSideOfRepo(dpRepo)
+	if !ok {
+		return errs.Errorf("Can't delete domain '%s' from %s.", domainName, dpRepo)
+	}
+	exportSide := oppositeSide(dpSide)
+
+	dialogResult := askUserInput(
+		fmt.Sprintf("Confirm deletion of DataPower domain '%s' (y/n): ", domainName),
+		"", []string{"y", "n"}, false)
+	if dialogResult.dialogCanceled || dialogResult.inputAnswer != "y" {
+		updateStatusf("Canceled deleting of domain '%s'.", domainName)
+		return nil
+	}
+
+	exportViewConfig := workingModel.ViewConfig(exportSide)
+	dialogResult = askUserInput(
+		fmt.Sprintf("Export domain '%s' to '%s' before deletion (y/n): ",
+			domainName, exportViewConfig.Path), "y", []string{"y", "n"}, false)
+	if dialogResult.dialogCanceled {
+		updateStatusf("Canceled deleting of domain '%s'.", domainName)
+		return nil
+	}
+	if dialogResult.inputAnswer == "y" {
+		ctx := showProgressDialogf("Exporting domain '%s'...", domainName)
+		err := exportDomain(ctx, dpRepo, repos[exportSide], parentItemConfig, exportViewConfig, domainName)
+		hideProgressDialog()
+		if err != nil {
+			return err
+		}
+		showItem(exportSide, exportViewConfig, ".")
+	}
+
+	dialogResult = askUserInput(
+		fmt.Sprintf("Enter domain name '%s' to confirm deletion: ", domainName),
+		"", nil, false)
+	if dialogResult.dialogCanceled || dialogResult.inputAnswer != domainName {
+		updateStatusf("Canceled deleting of domain '%s'.", domainName)
+		return nil
+	}
+
+	ctx := showProgressDialogf("Deleting domain '%s'...", domainName)
+	err := dp.Repos[dpSide].DeleteDomain(ctx, domainName)
+	hideProgressDialog()
+	if err != nil {
+		return err
+	}
+	updateStatusf("Successfully deleted domain '%s'.", domainName)
+
+	return nil
+}
+
 func enterDirectoryPath(m *model.Model) error {
 	logging.LogDebug("ui/enterDirectoryPath()")
 	side := m.CurrSide()
 	viewConfig := m.ViewConfig(side)
 
-	if side == model.Left && viewConfig.DpDomain == "" {
+	if isDpSide(side) && viewConfig.DpDomain == "" {
 		return errs.Error("Can't enter path if DataPower domain is not selected first.")
 	}
 
@@ -1997,7 +2920,7 @@ func saveDataPowerConfig(m *model.Model) error {
 	side := m.CurrSide()
 	viewConfig := m.ViewConfig(side)
 
-	if side == model.Left && viewConfig.DpDomain != "" {
+	if isDpSide(side) && viewConfig.DpDomain != "" {
 		confirmSave := askUserInput(
 			fmt.Sprintf("Are you sure you want to save current DataPower configuration for domain '%s' (y/n): ",
 				viewConfig.DpDomain),
@@ -2007,7 +2930,7 @@ func saveDataPowerConfig(m *model.Model) error {
 			return errs.Errorf("Canceled saving of DataPower configuration for domain '%s'.", viewConfig.DpDomain)
 		}
 
-		err := dp.Repo.SaveConfiguration(viewConfig)
+		err := dp.Repos[side].SaveConfiguration(progressContext(), viewConfig)
 		if err != nil {
 			return err
 		}
@@ -2029,180 +2952,15 @@ func showStatusMessages(statuses []string) error {
 	return extprogs.View("Status_Messages", []byte(statusesText))
 }
 
-// syncModeToggle toggles sync mode (off <-> on). Sync mode is used to copy
-// local changes to DataPower.
-func syncModeToggle(m *model.Model) error {
-	logging.LogDebug("ui/syncModeToggle()")
-	var syncModeToggleConfirm userDialogResult
-
-	dpViewConfig := m.ViewConfig(model.Left)
-	dpApplianceName := dpViewConfig.DpAppliance
-	dpDomain := dpViewConfig.DpDomain
-	dpDir := dpViewConfig.Path
-
-	if m.SyncModeOn {
-		syncModeToggleConfirm = askUserInput("Are you sure you want to disable sync mode (y/n): ", "", []string{"y", "n"}, false)
-	} else {
-		if dpDomain != "" && dpDir != "" {
-			syncModeToggleConfirm = askUserInput("Are you sure you want to enable sync mode (y/n): ", "", []string{"y", "n"}, false)
-		} else {
-			return errs.Errorf("Can't sync if DataPower domain (%s) or path (%s) are not selected.", dpDomain, dpDir)
-		}
-	}
-
-	if syncModeToggleConfirm.dialogSubmitted && syncModeToggleConfirm.inputAnswer == "y" {
-		m.SyncModeOn = !m.SyncModeOn
-		if m.SyncModeOn {
-			dp.SyncRepo.InitNetworkSettings(
-				dpApplianceName, config.Conf.DataPowerAppliances[dpApplianceName])
-			m.SyncDpDomain = dpDomain
-			m.SyncDirDp = dpDir
-			m.SyncDirLocal = m.ViewConfig(model.Right).Path
-			m.SyncInitial = true
-			go syncLocalToDp(m)
-			updateStatusf("Synchronization mode enabled (%s/'%s' <- '%s').", m.SyncDpDomain, m.SyncDirDp, m.SyncDirLocal)
-		} else {
-			m.SyncDpDomain = ""
-			m.SyncDirDp = ""
-			m.SyncDirLocal = ""
-			m.SyncInitial = false
-			updateStatus("Synchronization mode disabled.")
-		}
-	} else {
-		updateStatus("Synchronization mode change canceled.")
-	}
-
-	return nil
-}
-
-func syncLocalToDp(m *model.Model) {
-	logging.LogDebugf("worker/syncLocalToDp(), On: %v, Initial: %v, Domain: '%s', DirDp: '%s', DirLocal: '%s'",
-		m.SyncModeOn, m.SyncInitial, m.SyncDpDomain, m.SyncDirDp, m.SyncDirLocal)
-	// 1. Fetch dp & local file tree
-	// 2. Initial sync files from local to dp:
-	// 2a. Copy non-existing from local to dp
-	// 2b. Compare existing files and copy different files
-	// 3. Save local file tree (file path + modify timestamp)
-	// 4. Sync files from local to dp:
-	// 4a. When local modify timestamp changes or new file appears copy to dp
-	var treeOld localfs.Tree
-	syncCheckTime := time.Duration(config.Conf.Sync.Seconds) * time.Second
-	for m.SyncModeOn {
-		var changesMade bool
-		tree, err := localfs.LoadTree("", m.SyncDirLocal)
-		if err != nil {
-			updateStatusf("Sync err: %s.", err)
-		}
-		logging.LogDebug("worker/syncLocalToDp(), tree: ", tree)
-
-		if m.SyncInitial {
-			changesMade = syncLocalToDpInitial(&tree)
-			logging.LogDebug("worker/syncLocalToDp(), after initial sync - changesMade: ", changesMade)
-			m.SyncInitial = false
-		} else {
-			changesMade = syncLocalToDpLater(&tree, &treeOld)
-			logging.LogDebug("worker/syncLocalToDp(), after later sync - changesMade: ", changesMade)
-		}
-
-		treeOld = tree
-
-		logging.LogDebugf("worker/syncLocalToDp() changesMade: %v.", changesMade)
-		if changesMade {
-			refreshView(m, model.Left)
-		} else {
-			refreshStatus()
-		}
-		logging.LogDebugf("worker/syncLocalToDp() before sleep, m.SyncModeOn: %v.", m.SyncModeOn)
-		time.Sleep(syncCheckTime)
-		logging.LogDebugf("worker/syncLocalToDp() after sleep, m.SyncModeOn: %v.", m.SyncModeOn)
-	}
-	logging.LogDebug("worker/syncLocalToDp() ending.")
-}
-
-func syncLocalToDpInitial(tree *localfs.Tree) bool {
-	changesMade := false
-	logging.LogDebugf("worker/syncLocalToDpInitial(%v)", tree)
-	// m := &model.Model{}
-	m := &workingModel
-	// logging.LogDebug("syncLocalToDpInitial(), syncDirDp: ", syncDirDp, ", tree.PathFromRoot: ", tree.PathFromRoot)
-
-	if tree.Dir {
-		dpPath := dp.SyncRepo.GetFilePath(m.SyncDirDp, tree.PathFromRoot)
-		fileType, err := dp.SyncRepo.GetFileTypeByPath(m.SyncDpDomain, dpPath, ".")
-		if err != nil {
-			logging.LogDebug("worker/syncLocalToDpInitial(), err: ", err)
-		}
-
-		if fileType == model.ItemNone {
-			dp.SyncRepo.CreateDirByPath(m.SyncDpDomain, dpPath, ".")
-			changesMade = true
-		} else if fileType == model.ItemFile {
-			logging.LogDebugf("worker/syncLocalToDpInitial() - In place of dir there is a file on dp: '%s'", dpPath)
-		}
-		for _, child := range tree.Children {
-			if syncLocalToDpInitial(&child) {
-				changesMade = true
-			}
-		}
-	} else {
-		changesMade = updateDpFile(m, tree)
-	}
-
-	logging.LogDebugf("worker/syncLocalToDpInitial(), changesMade: %v", changesMade)
-	return changesMade
-}
-
-func syncLocalToDpLater(tree, treeOld *localfs.Tree) bool {
-	changesMade := false
-	logging.LogDebugf("worker/syncLocalToDpLater(%v, %v)", tree, treeOld)
-	// m := &model.Model{}
-	m := &workingModel
-
-	if tree.Dir {
-		if treeOld == nil {
-			dpPath := dp.SyncRepo.GetFilePath(m.SyncDirDp, tree.PathFromRoot)
-			fileType, err := dp.SyncRepo.GetFileTypeByPath(m.SyncDpDomain, dpPath, ".")
-			if err != nil {
-				logging.LogDebug("worker/syncLocalToDpLater(), err: ", err)
-				return false
-			}
-			if fileType == model.ItemNone {
-				dp.SyncRepo.CreateDirByPath(m.SyncDpDomain, dpPath, ".")
-				changesMade = true
-			} else if fileType == model.ItemFile {
-				logging.LogDebugf("worker/syncLocalToDpLater() - In place of dir there is a file on dp: '%s'", dpPath)
-				return false
-			}
-		}
-
-		for _, child := range tree.Children {
-			var childOld *localfs.Tree
-			if treeOld != nil {
-				childOld = treeOld.FindChild(&child)
-			}
-			if syncLocalToDpLater(&child, childOld) {
-				changesMade = true
-			}
-		}
-	} else {
-		if tree.FileChanged(treeOld) {
-			changesMade = updateDpFile(m, tree)
-		}
-	}
-
-	logging.LogDebugf("worker/syncLocalToDpLater()(), changesMade: %v", changesMade)
-	return changesMade
-}
-
 // execConfigFile switches between (default) filestore mode, object mode and
 // status mode for the DataPower view.
 func execConfigFile(m *model.Model) error {
-	logging.LogDebugf("worker/execConfigFile(), dp.Repo.DpViewMode: %s", dp.Repo.DpViewMode)
+	logging.LogDebugf("worker/execConfigFile()")
 
 	side := m.CurrSide()
 	// viewConfig := m.ViewConfig(side)
-	switch side {
-	case model.Left:
+	switch {
+	case isDpSide(side):
 		itemsToExec := getSelectedOrCurrent(m)
 		pathsToExec := make([]string, len(itemsToExec))
 		for idx, item := range itemsToExec {
@@ -2226,8 +2984,8 @@ func execConfigFile(m *model.Model) error {
 		logging.LogDebugf("ui/execConfigFile(), confirm exec: '%s'", res)
 		if res == "y" {
 			for _, item := range itemsToExec {
-				showProgressDialogf("Running exec command '%s' file from DataPower...", item.Name)
-				err := repos[m.CurrSide()].ExecConfig(item.Config)
+				ctx := showProgressDialogf("Running exec command '%s' file from DataPower...", item.Name)
+				err := repos[m.CurrSide()].ExecConfig(ctx, item.Config)
 				if err != nil {
 					hideProgressDialog()
 					return err
@@ -2248,33 +3006,34 @@ func execConfigFile(m *model.Model) error {
 // toggleObjectMode switches between (default) filestore mode, object mode and
 // status mode for the DataPower view.
 func toggleObjectMode(m *model.Model) error {
-	logging.LogDebugf("worker/toggleObjectMode(), dp.Repo.DpViewMode: %s", dp.Repo.DpViewMode)
-
 	side := m.CurrSide()
-	switch side {
-	case model.Left:
+	logging.LogDebugf("worker/toggleObjectMode(), side: %v", side)
+
+	switch {
+	case isDpSide(side):
+		dpRepo := dp.Repos[side]
 		oldView := m.ViewConfig(side)
 
 		if oldView.DpDomain == "" {
 			logging.LogDebugf("worker/toggleObjectMode(), can't switch to non-filestore mode, oldView: %v.", oldView)
-			dp.Repo.DpViewMode = model.DpFilestoreMode
+			dpRepo.DpViewMode = model.DpFilestoreMode
 			return errs.Errorf("Can't show object or status view if DataPower domain is not selected.")
 		}
 
-		switch dp.Repo.DpViewMode {
+		switch dpRepo.DpViewMode {
 		case model.DpFilestoreMode:
-			dp.Repo.DpViewMode = model.DpObjectMode
+			dpRepo.DpViewMode = model.DpObjectMode
 		case model.DpObjectMode:
-			dp.Repo.DpViewMode = model.DpStatusMode
+			dpRepo.DpViewMode = model.DpStatusMode
 		case model.DpStatusMode:
-			dp.Repo.DpViewMode = model.DpFilestoreMode
+			dpRepo.DpViewMode = model.DpFilestoreMode
 		default:
-			dp.Repo.DpViewMode = model.DpFilestoreMode
+			dpRepo.DpViewMode = model.DpFilestoreMode
 		}
 
 		// When we switch to object config mode - add/open object class list.
 		// When we switch to status mode - add/open status class list.
-		switch dp.Repo.DpViewMode {
+		switch dpRepo.DpViewMode {
 		case model.DpObjectMode:
 			newView := model.ItemConfig{
 				Parent:      oldView,
@@ -2284,7 +3043,7 @@ func toggleObjectMode(m *model.Model) error {
 				DpAppliance: oldView.DpAppliance,
 				DpDomain:    oldView.DpDomain,
 				DpFilestore: oldView.DpFilestore}
-			return showItem(model.Left, &newView, newView.Path)
+			return showItem(side, &newView, newView.Path)
 		case model.DpStatusMode:
 			newView := model.ItemConfig{
 				Parent:      oldView,
@@ -2294,7 +3053,7 @@ func toggleObjectMode(m *model.Model) error {
 				DpAppliance: oldView.DpAppliance,
 				DpDomain:    oldView.DpDomain,
 				DpFilestore: oldView.DpFilestore}
-			return showItem(model.Left, &newView, newView.Path)
+			return showItem(side, &newView, newView.Path)
 		case model.DpFilestoreMode:
 			// When we switch from status mode navigate back to first filestore mode.
 			ic := m.NavCurrentViewBack(side)
@@ -2302,11 +3061,11 @@ func toggleObjectMode(m *model.Model) error {
 				ic = m.NavCurrentViewBack(side)
 			}
 			firstNonObjectView := m.ViewConfig(side)
-			return showItem(model.Left, firstNonObjectView, ".")
+			return showItem(side, firstNonObjectView, ".")
 		default:
 			logging.LogDebugf("worker/toggleObjectMode(), unknown view mode %v.",
-				dp.Repo.DpViewMode)
-			return errs.Errorf("Unknown view mode %v.", dp.Repo.DpViewMode)
+				dpRepo.DpViewMode)
+			return errs.Errorf("Unknown view mode %v.", dpRepo.DpViewMode)
 		}
 
 	default:
@@ -2317,7 +3076,7 @@ func toggleObjectMode(m *model.Model) error {
 
 // showItemInfo shows information about current item.
 func showItemInfo(m *model.Model) error {
-	logging.LogDebugf("worker/showItemInfo(), dp.Repo.DpViewMode: %s", dp.Repo.DpViewMode)
+	logging.LogDebugf("worker/showItemInfo()")
 
 	currentItem := m.CurrItem()
 	if currentItem.Name == ".." {
@@ -2349,10 +3108,10 @@ func showItemInfo(m *model.Model) error {
 // showObjectDetails shows details (service, policy, matches, rules & actions)
 // for the current object.
 func showObjectDetails(m *model.Model) error {
-	logging.LogDebugf("worker/showObjectPolicy(), dp.Repo.ObjectConfigMode: %s",
-		dp.Repo.DpViewMode)
+	side := m.CurrSide()
+	logging.LogDebugf("worker/showObjectPolicy(), side: %v", side)
 
-	if dp.Repo.DpViewMode != model.DpObjectMode {
+	if !isDpSide(side) || dp.Repos[side].DpViewMode != model.DpObjectMode {
 		return errs.Error("Can't show policy for DataPower object if object mode is not active.")
 	}
 
@@ -2384,27 +3143,21 @@ func showObjectDetails(m *model.Model) error {
 				currentItem.Config.Path)
 		}
 
-		updateStatusf("Fetching policy for object '%s' (%s) from domain '%s'.",
-			currentItem.Config.Name, currentItem.Config.Path,
-			currentItem.Config.DpDomain)
-		showProgressDialogf("Exporting object '%s' (%s) from domain '%s'...",
-			currentItem.Config.Name, currentItem.Config.Path,
-			currentItem.Config.DpDomain)
-		objectInfoBytes, err :=
-			dp.Repo.GetObjectDetails(currentItem.Config.DpDomain,
-				currentItem.Config.Path, currentItem.Config.Name)
-		hideProgressDialog()
-		if err != nil {
-			return err
-		}
-		if objectInfoBytes != nil {
-			err = extprogs.View("*."+currentItem.Name, objectInfoBytes)
-			if err != nil {
-				return err
-			}
-		} else {
-			return errs.Errorf("Can't show policy info for '%s' object.", currentItem.Name)
-		}
+		// Object details are fetched in the job, result is shown from the job list.
+		dpRepo := dp.Repos[side].Copy()
+		itemConfig := currentItem.Config
+		startJob(fmt.Sprintf("Policy for object '%s' (%s) from domain '%s'",
+			itemConfig.Name, itemConfig.Path, itemConfig.DpDomain), "*."+currentItem.Name,
+			func(ctx context.Context) ([]byte, error) {
+				updateProgressf(ctx, "Exporting object '%s' (%s) from domain '%s'...",
+					itemConfig.Name, itemConfig.Path, itemConfig.DpDomain)
+				objectInfoBytes, err := dpRepo.GetObjectDetails(ctx, itemConfig.DpDomain,
+					itemConfig.Path, itemConfig.Name)
+				if err == nil && objectInfoBytes == nil {
+					err = errs.Errorf("Can't show policy info for '%s' object.", itemConfig.Name)
+				}
+				return objectInfoBytes, err
+			})
 	default:
 		return errs.Error("Can't show policy info for non DataPower object.")
 	}
@@ -2412,40 +3165,6 @@ func showObjectDetails(m *model.Model) error {
 	return nil
 }
 
-func updateDpFile(m *model.Model, tree *localfs.Tree) bool {
-	changesMade := false
-	localBytes, err := localfs.GetFileByPath(tree.Path)
-	if err != nil {
-		logging.LogDebug("worker/updateDpFile(), couldn't get local file - err: ", err)
-		return false
-	}
-	dpPath := dp.SyncRepo.GetFilePath(m.SyncDirDp, tree.PathFromRoot)
-	dpBytes, err := dp.SyncRepo.GetFileByPath(m.SyncDpDomain, dpPath)
-
-	if err != nil {
-		if respErr, ok := err.(errs.UnexpectedHTTPResponse); !ok || respErr.StatusCode != 404 {
-			logging.LogDebug("worker/updateDpFile(), couldn't get dp file - err: ", err)
-			return false
-		}
-	}
-
-	if bytes.Compare(localBytes, dpBytes) != 0 {
-		changesMade = true
-		res, err := dp.SyncRepo.UpdateFileByPath(m.SyncDpDomain, dpPath, localBytes)
-		if err != nil {
-			logging.LogDebug("worker/updateDpFile(), couldn't update dp file - err: ", err)
-		}
-		logging.LogDebugf("worker/updateDpFile(), file '%s' updated: %T", dpPath, res)
-		if res {
-			updateStatusf("Dp file '%s' updated.", dpPath)
-		} else {
-			updateStatusf("Error updating file '%s'.", dpPath)
-		}
-	}
-
-	return changesMade
-}
-
 func updateStatusf(format string, v ...interface{}) {
 	status := fmt.Sprintf(format, v...)
 	updateStatus(status)
@@ -2459,6 +3178,20 @@ func updateStatus(status string) {
 	workingModel.AddStatus(status)
 }
 
+// updateStatusCtx shows status message, when action runs as a job (context
+// contains the job) status message is posted from the job goroutine.
+func updateStatusCtx(ctx context.Context, status string) {
+	if _, ok := ctx.Value(jobContextKey{}).(*job); ok {
+		postStatusf("%s", status)
+		return
+	}
+	updateStatus(status)
+}
+
+func updateStatusCtxf(ctx context.Context, format string, v ...interface{}) {
+	updateStatusCtx(ctx, fmt.Sprintf(format, v...))
+}
+
 func refreshStatus() {
 	logging.LogDebugf("worker/refreshStatus()")
 	updateView := events.UpdateViewEvent{
@@ -2466,30 +3199,124 @@ func refreshStatus() {
 	out.DrawEvent(updateView)
 }
 
-func showProgressDialog(msg string) {
-	progressDialogSession.value = 0
-	progressDialogSession.visible = true
+// postViewUpdate posts view update from the background goroutine to the
+// goroutine reading user input, which is the only one changing the model.
+func postViewUpdate(updateView events.UpdateViewEvent) {
+	logging.LogDebugf("worker/postViewUpdate(%d, '%s')", updateView.Type, updateView.Status)
+	postedViewUpdatesMutex.Lock()
+	postedViewUpdates = append(postedViewUpdates, updateView)
+	postedViewUpdatesMutex.Unlock()
+	out.PostInterrupt()
+}
+
+// postStatusf posts status message from the background goroutine.
+func postStatusf(format string, v ...interface{}) {
+	postViewUpdate(events.UpdateViewEvent{
+		Type: events.UpdateViewShowStatus, Status: fmt.Sprintf(format, v...)})
+}
+
+// applyPostedViewUpdates applies view updates posted by background goroutines.
+func applyPostedViewUpdates() {
+	postedViewUpdatesMutex.Lock()
+	updates := postedViewUpdates
+	postedViewUpdates = nil
+	postedViewUpdatesMutex.Unlock()
+
+	for _, updateView := range updates {
+		switch updateView.Type {
+		case events.UpdateViewShowStatus:
+			updateStatus(updateView.Status)
+		case events.UpdateViewRefreshPanel:
+			refreshView(&workingModel, updateView.Side)
+		case events.UpdateViewRefreshSyncPanels:
+			refreshSyncViews(&workingModel, updateView.SyncSession)
+		}
+	}
+}
+
+// showProgressDialog shows progress dialog and returns context canceled when
+// user presses Esc. When progress dialog is already shown (nested long running
+// actions) its message is changed and context of the shown dialog is returned.
+func showProgressDialog(msg string) context.Context {
+	progressDialogSession.mutex.Lock()
+	defer progressDialogSession.mutex.Unlock()
 	progressDialogSession.msg = msg
-	go runProgressDialog()
+	progressDialogSession.depth++
+	if progressDialogSession.depth == 1 {
+		progressDialogSession.value = 0
+		progressDialogSession.visible = true
+		progressDialogSession.ctx, progressDialogSession.cancel = context.WithCancel(context.Background())
+		progressDialogSession.done = make(chan struct{})
+		go runProgressDialog(progressDialogSession.done)
+	}
+
+	return progressDialogSession.ctx
 }
 
-func showProgressDialogf(format string, v ...interface{}) {
-	showProgressDialog(fmt.Sprintf(format, v...))
+func showProgressDialogf(format string, v ...interface{}) context.Context {
+	return showProgressDialog(fmt.Sprintf(format, v...))
 }
 
+// hideProgressDialog hides progress dialog (when the outermost long running
+// action is done) and processes input events received while it was shown.
 func hideProgressDialog() {
+	progressDialogSession.mutex.Lock()
+	defer progressDialogSession.mutex.Unlock()
+	if progressDialogSession.depth == 0 {
+		return
+	}
+	progressDialogSession.depth--
+	if progressDialogSession.depth > 0 {
+		return
+	}
 	progressDialogSession.visible = false
+	progressDialogSession.cancel()
+	close(progressDialogSession.done)
+	for _, event := range progressDialogSession.pendingEvents {
+		out.Screen.PostEvent(event)
+	}
+	progressDialogSession.pendingEvents = nil
 }
 
-func runProgressDialog() {
-	for progressDialogSession.visible {
-		updateProgressDialog()
-		time.Sleep(1 * time.Second)
+// progressContext returns context of the shown progress dialog (or background
+// context if progress dialog is not shown).
+func progressContext() context.Context {
+	progressDialogSession.mutex.Lock()
+	defer progressDialogSession.mutex.Unlock()
+	if progressDialogSession.depth == 0 {
+		return context.Background()
+	}
+	return progressDialogSession.ctx
+}
+
+// setWaitUserInput marks that user input is read by a dialog, when progress
+// dialog is shown we don't want it to hide our dialog or read its input.
+func setWaitUserInput(wait bool) {
+	progressDialogSession.mutex.Lock()
+	defer progressDialogSession.mutex.Unlock()
+	progressDialogSession.waitUserInput = wait
+}
+
+func runProgressDialog(done <-chan struct{}) {
+	ticker := time.NewTicker(progressDialogTick)
+	defer ticker.Stop()
+	for tick := 0; ; tick++ {
+		if tick%progressDialogRedrawTicks == 0 {
+			updateProgressDialog()
+		}
+		processProgressDialogInput()
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
 	}
 }
 
 func updateProgressDialog() {
-	if !progressDialogSession.waitUserInput {
+	progressDialogSession.mutex.Lock()
+	defer progressDialogSession.mutex.Unlock()
+	if progressDialogSession.visible && !progressDialogSession.waitUserInput {
 		progressEvent := events.UpdateViewEvent{Type: events.UpdateViewShowProgress,
 			Message:  progressDialogSession.msg,
 			Progress: progressDialogSession.value}
@@ -2501,14 +3328,39 @@ func updateProgressDialog() {
 	}
 }
 
+// processProgressDialogInput reads input events received while progress
+// dialog is shown - Esc cancels the running action, other events are kept.
+func processProgressDialogInput() {
+	progressDialogSession.mutex.Lock()
+	defer progressDialogSession.mutex.Unlock()
+	if !progressDialogSession.visible || progressDialogSession.waitUserInput {
+		return
+	}
+	for out.Screen.HasPendingEvent() {
+		event := out.Screen.PollEvent()
+		if event == nil {
+			return
+		}
+		if keyEvent, ok := event.(*tcell.EventKey); ok && keyEvent.Key() == tcell.KeyEsc {
+			logging.LogDebug("ui/processProgressDialogInput() - canceling action")
+			progressDialogSession.cancel()
+			progressDialogSession.msg = "Canceling..."
+			continue
+		}
+		progressDialogSession.pendingEvents = append(progressDialogSession.pendingEvents, event)
+	}
+}
+
 func updateProgressDialogMessagef(format string, v ...interface{}) {
+	progressDialogSession.mutex.Lock()
+	defer progressDialogSession.mutex.Unlock()
 	progressDialogSession.msg = fmt.Sprintf(format, v...)
 }
 
 // getFileTypedName converts name without suffix to name with suffix - used for tmp
 // file naming. Proper tmp file name can enable viewer / editor (vim) to highlight syntax.
-func getObjectTmpName(objectName string) string {
-	switch dp.Repo.GetManagementInterface() {
+func getObjectTmpName(side model.Side, objectName string) string {
+	switch dp.Repos[side].GetManagementInterface() {
 	case config.DpInterfaceRest:
 		return "*." + objectName + ".json"
 	case config.DpInterfaceSoma:
@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/croz-ltd/dpcmder/events"
+	"github.com/croz-ltd/dpcmder/model"
+	"github.com/croz-ltd/dpcmder/repo"
+	"github.com/croz-ltd/dpcmder/repo/localfs"
+	"github.com/croz-ltd/dpcmder/utils/assert"
+	"github.com/croz-ltd/dpcmder/utils/errs"
+)
+
+// failingRepo is local filesystem repo which fails to get the file failName,
+// getting other files is slow so copy can be canceled before they are copied.
+type failingRepo struct {
+	repo.Repo
+	failName string
+	mutex    sync.Mutex
+	getCount int
+}
+
+func (r *failingRepo) GetFile(ctx context.Context, currentView *model.ItemConfig, fileName string) ([]byte, error) {
+	r.mutex.Lock()
+	r.getCount++
+	r.mutex.Unlock()
+	if fileName == r.failName {
+		return nil, errs.Errorf("Can't read '%s'.", fileName)
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(10 * time.Millisecond):
+	}
+	return r.Repo.GetFile(ctx, currentView, fileName)
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err == nil {
+		err = ioutil.WriteFile(path, []byte(content), 0644)
+	}
+	assert.Nil(t, "writeTestFile", err)
+}
+
+func readTestFile(t *testing.T, path string) string {
+	content, err := ioutil.ReadFile(path)
+	assert.Nil(t, "readTestFile", err)
+	return string(content)
+}
+
+func TestCopyDirsOrFilestores(t *testing.T) {
+	fromDir := t.TempDir()
+	toDir := t.TempDir()
+	writeTestFile(t, filepath.Join(fromDir, "tree", "a.txt"), "aaa")
+	writeTestFile(t, filepath.Join(fromDir, "tree", "sub", "b.txt"), "bbbb")
+	writeTestFile(t, filepath.Join(fromDir, "tree", "sub", "deep", "c.txt"), "cc")
+	writeTestFile(t, filepath.Join(toDir, "tree", "sub", "b.txt"), "old")
+	fromViewConfig := &model.ItemConfig{Type: model.ItemDirectory, Path: fromDir}
+	toViewConfig := &model.ItemConfig{Type: model.ItemDirectory, Path: toDir}
+
+	t.Run("Skip overwrite of existing files", func(t *testing.T) {
+		res, err := copyDirsOrFilestores(context.Background(), localfs.Repo, localfs.Repo,
+			fromViewConfig, toViewConfig, "tree", "tree", "na")
+		assert.Nil(t, "copyDirsOrFilestores", err)
+		assert.Equals(t, "copyDirsOrFilestores", res, "na")
+		assert.Equals(t, "a.txt", readTestFile(t, filepath.Join(toDir, "tree", "a.txt")), "aaa")
+		assert.Equals(t, "b.txt", readTestFile(t, filepath.Join(toDir, "tree", "sub", "b.txt")), "old")
+		assert.Equals(t, "c.txt", readTestFile(t, filepath.Join(toDir, "tree", "sub", "deep", "c.txt")), "cc")
+		assert.Equals(t, "LastStatus()", workingModel.LastStatus(),
+			"Copied 2 files (5 bytes) from local filesystem to local filesystem.")
+	})
+
+	t.Run("Overwrite existing files", func(t *testing.T) {
+		res, err := copyDirsOrFilestores(context.Background(), localfs.Repo, localfs.Repo,
+			fromViewConfig, toViewConfig, "tree", "tree", "ya")
+		assert.Nil(t, "copyDirsOrFilestores", err)
+		assert.Equals(t, "copyDirsOrFilestores", res, "ya")
+		assert.Equals(t, "b.txt", readTestFile(t, filepath.Join(toDir, "tree", "sub", "b.txt")), "bbbb")
+		assert.Equals(t, "LastStatus()", workingModel.LastStatus(),
+			"Copied 3 files (9 bytes) from local filesystem to local filesystem.")
+	})
+
+	t.Run("Error cancels remaining files", func(t *testing.T) {
+		fileCount := 50
+		for idx := 0; idx < fileCount; idx++ {
+			writeTestFile(t, filepath.Join(fromDir, "many", fmt.Sprintf("file%02d.txt", idx)), "content")
+		}
+		fromRepo := &failingRepo{Repo: localfs.Repo, failName: "file00.txt"}
+		_, err := copyDirsOrFilestores(context.Background(), fromRepo, localfs.Repo,
+			fromViewConfig, toViewConfig, "many", "many", "ya")
+		assert.DeepEqual(t, "copyDirsOrFilestores", err, errs.Errorf("Can't read 'file00.txt'."))
+		assert.True(t, "remaining files not copied", fromRepo.getCount < fileCount)
+		copiedFiles, err := ioutil.ReadDir(filepath.Join(toDir, "many"))
+		assert.Nil(t, "ReadDir", err)
+		assert.True(t, "remaining files not copied", len(copiedFiles) < fileCount)
+	})
+}
+
+func TestPostViewUpdate(t *testing.T) {
+	dir := t.TempDir()
+	defer func(itemMaxRows int) { workingModel.ItemMaxRows = itemMaxRows }(workingModel.ItemMaxRows)
+	workingModel.ItemMaxRows = 10
+	workingModel.SetCurrentView(model.Right, &model.ItemConfig{Type: model.ItemDirectory, Path: dir}, dir)
+
+	const updateCount = 100
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for idx := 0; idx < updateCount; idx++ {
+			postStatusf("Status %d", idx)
+			if idx == updateCount/2 {
+				writeTestFile(t, filepath.Join(dir, "a.txt"), "aaa")
+			}
+			postViewUpdate(events.UpdateViewEvent{Type: events.UpdateViewRefreshPanel, Side: model.Right})
+		}
+	}()
+
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		applyPostedViewUpdates()
+		workingModel.LastStatus()
+		workingModel.GetVisibleItemCount(model.Right)
+	}
+
+	visibleItems := make([]string, 0)
+	for idx := 0; idx < workingModel.GetVisibleItemCount(model.Right); idx++ {
+		visibleItems = append(visibleItems, workingModel.GetVisibleItem(model.Right, idx).Name)
+	}
+	assert.DeepEqual(t, "visible items", visibleItems, []string{"..", "a.txt"})
+	assert.Equals(t, "LastStatus()", workingModel.LastStatus(), fmt.Sprintf("Directory (%s) refreshed.", dir))
+	assert.True(t, "Statuses()", len(workingModel.Statuses()) > 1)
+	assert.Equals(t, "Statuses()", workingModel.Statuses()[len(workingModel.Statuses())-2],
+		fmt.Sprintf("Status %d", updateCount-1))
+}
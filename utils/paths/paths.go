@@ -17,6 +17,11 @@ func GetFileName(fullPath string) string {
 	return getFileNameUsingSeparator(fullPath, string(os.PathSeparator))
 }
 
+// GetDpFileName extract DataPower file name from fullPath.
+func GetDpFileName(fullPath string) string {
+	return getFileNameUsingSeparator(fullPath, "/")
+}
+
 // GetDpPath generates local os correct path from parentPath and fileName.
 func GetDpPath(parentPath, fileName string) string {
 	fullPath := getFilePathUsingSeparator(parentPath, fileName, "/")
@@ -71,6 +71,20 @@ func TestGetFileNameUsingSeparator(t *testing.T) {
 	}
 }
 
+func TestGetDpFileName(t *testing.T) {
+	testDataMatrix := [][]string{
+		{"local:/dir/test.xsl", "test.xsl"},
+		{"local:/dir", "dir"},
+		{"local:", "local:"},
+	}
+	for _, testCase := range testDataMatrix {
+		gotName := GetDpFileName(testCase[0])
+		if gotName != testCase[1] {
+			t.Errorf("for GetDpFileName('%s'): got '%s', want '%s'", testCase[0], gotName, testCase[1])
+		}
+	}
+}
+
 func TestSplitDpPath(t *testing.T) {
 	testDataMatrix := []struct {
 		path       string